@@ -3,13 +3,16 @@ package pack
 import (
 	"context"
 	"fmt"
-	"math/rand"
+	"io"
+	"io/ioutil"
+	"net"
 	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/buildpacks/imgutil/local"
 	"github.com/buildpacks/imgutil/remote"
@@ -17,12 +20,17 @@ import (
 	"github.com/buildpacks/pack/logging"
 
 	"github.com/buildpacks/pack/config"
+	"github.com/buildpacks/pack/events"
 
 	"github.com/Masterminds/semver"
 	"github.com/buildpacks/imgutil"
-	"github.com/docker/docker/api/types"
+	"github.com/buildpacks/lifecycle"
+	"github.com/buildpacks/lifecycle/launch"
 	"github.com/docker/docker/volume/mounts"
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	v1remote "github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/pkg/errors"
 
 	"github.com/buildpacks/pack/internal/archive"
@@ -31,11 +39,13 @@ import (
 	"github.com/buildpacks/pack/internal/buildpack"
 	"github.com/buildpacks/pack/internal/buildpackage"
 	"github.com/buildpacks/pack/internal/dist"
+	"github.com/buildpacks/pack/internal/gitsource"
 	"github.com/buildpacks/pack/internal/layer"
 	"github.com/buildpacks/pack/internal/paths"
 	"github.com/buildpacks/pack/internal/stack"
 	"github.com/buildpacks/pack/internal/stringset"
 	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/project"
 )
 
 const (
@@ -45,8 +55,14 @@ const (
 	minLifecycleVersionSupportingCreator = "0.7.4"
 	prevLifecycleVersionSupportingImage  = "0.6.1"
 	minLifecycleVersionSupportingImage   = "0.7.5"
+
+	// The repository ephemeral builders are saved to in the docker daemon.
+	ephemeralBuilderRepo = "pack.local/builder"
 )
 
+// StdinAppPath is the app path that reads the app from stdin.
+const StdinAppPath = "-"
+
 // LifecycleExecutor executes the lifecycle which satisfies the Cloud Native Buildpacks Lifecycle specification.
 // Implementations of the Lifecycle must execute the following phases by calling the
 // phase-specific lifecycle binary in order:
@@ -65,6 +81,10 @@ type LifecycleExecutor interface {
 	// Execute is responsible for invoking each of these binaries
 	// with the desired configuration.
 	Execute(ctx context.Context, opts build.LifecycleOptions) error
+
+	// Detect invokes only the detector, reporting the groups of buildpacks it tried
+	// and the group it selected.
+	Detect(ctx context.Context, opts build.LifecycleOptions) (build.DetectResult, error)
 }
 
 // BuildOptions defines configuration settings for a Build.
@@ -79,10 +99,16 @@ type BuildOptions struct {
 	// add buildpacks to a build.
 	Registry string
 
-	// AppPath is the path to application bits.
+	// AppPath is the path to application bits: a directory, a zip, tar or tar.gz file,
+	// a git repository in the form 'git+<url>#<ref>[:<subdir>]',
+	// or StdinAppPath to read a tar or tar.gz archive from AppReader.
 	// If unset it defaults to current working directory.
 	AppPath string
 
+	// Stream of the tar or tar.gz archive of the app, when AppPath is StdinAppPath.
+	// The CLI passes os.Stdin.
+	AppReader io.Reader
+
 	// Specify the run image the Image will be
 	// built atop.
 	RunImage string
@@ -98,8 +124,15 @@ type BuildOptions struct {
 
 	// User provided environment variables to the buildpacks.
 	// Buildpacks may both read and overwrite these values.
+	// The values are stored in the layers of the ephemeral builder, which is removed after the build
+	// instead of being kept for reuse; Secrets are better suited to credentials.
 	Env map[string]string
 
+	// Secrets made available to buildpacks as files under /platform/secrets during the build phase only,
+	// in the form 'id=<id>,src=<path>' or 'id=<id>,env=<VAR>'.
+	// Unlike Env, secrets are never stored in an image and their values are masked in the build output.
+	Secrets []string
+
 	// Option passed directly to the lifecycle.
 	// If true, publishes Image directly to a registry.
 	// Assumes Image contains a valid registry with credentials
@@ -109,6 +142,10 @@ type BuildOptions struct {
 	// Clear the build cache from previous builds.
 	ClearCache bool
 
+	// Image reference of a registry image to use as the build cache,
+	// instead of a docker volume. Requires Publish to be true.
+	CacheImage string
+
 	// TrustBuilder when true optimizes builds by running
 	// all lifecycle phases in a single container.
 	// This places registry credentials on the builder's build image.
@@ -120,9 +157,18 @@ type BuildOptions struct {
 	// share both an ID and Version with a buildpack on the builder.
 	Buildpacks []string
 
+	// Buildpacks synthesized from scripts and added to the builder, for the stack of the builder.
+	// Each is used where Buildpacks names it by '<id>' or '<id>@<version>'.
+	InlineBuildpacks []InlineBuildpack
+
 	// Additional image tags to push to, each will contain contents identical to Image
 	AdditionalTags []string
 
+	// File to also save the built image to, including the metadata the lifecycle adds to it,
+	// in the form 'oci:<dir>' for an OCI image layout or 'docker-archive:<file.tar>'
+	// for a tarball that 'docker load' accepts.
+	Output string
+
 	// Configure the proxy environment variables,
 	// These variables will only be set in the build image
 	// and will not be used if proxy env vars are already set.
@@ -140,6 +186,22 @@ type BuildOptions struct {
 
 	// Strategy for updating local images before a build.
 	PullPolicy config.PullPolicy
+
+	// Platform to build for, in the form '<os>/<arch>[/<variant>]', such as 'linux/arm64'.
+	// It selects the matching image from builder, run image and lifecycle image indexes.
+	// If unset, registries choose the image of an index.
+	Platform string
+
+	// How long the lifecycle may run before the build fails. Zero means no limit.
+	Timeout time.Duration
+
+	// How long each lifecycle phase may run before the build fails, keyed by phase name,
+	// such as 'builder'. Phases without a timeout may run until the build times out.
+	PhaseTimeouts map[string]time.Duration
+
+	// Receives events describing the progress of the build,
+	// such as image fetches, lifecycle phases and their output.
+	EventHandler events.Handler
 }
 
 // ProxyConfig specifies proxy setting to be set as environment variables in a container.
@@ -169,6 +231,83 @@ type ContainerConfig struct {
 	// - /layers
 	// - anything below /cnb/**
 	Volumes []string
+
+	// SSH agents forwarded to the detect and build phases, so that buildpacks
+	// can use them to fetch private dependencies. Each is either 'default',
+	// for the agent SSH_AUTH_SOCK points to, or '<id>=<socket path>'.
+	// Sockets are available at /platform/ssh/<id>, and SSH_AUTH_SOCK is set
+	// to the default agent, or else the first one.
+	SSH []string
+
+	// Memory limit of each build container, in bytes. Zero means no limit.
+	Memory int64
+
+	// Number of CPUs each build container may use, such as 1.5. Zero means no limit.
+	CPUs float64
+
+	// Maximum number of processes in each build container. Zero means no limit.
+	PidsLimit int64
+
+	// Additional /etc/hosts entries of the build containers, in the form 'host:ip'.
+	AddHosts []string
+
+	// DNS servers of the build containers.
+	DNS []string
+}
+
+// BuildResult describes the app image produced by a successful build.
+type BuildResult struct {
+	// ID of the image, the digest of its config, which the docker daemon names the image by.
+	ImageID string
+
+	// Digest of the image manifest in the registry of the image.
+	// For an image saved to the docker daemon, it is only set when the daemon knows of one,
+	// as it does once the image is pushed, and is otherwise empty.
+	ImageDigest string
+
+	// Every tag the image was written to, starting with the Image name.
+	Tags []string
+
+	// The builder image the build ran on.
+	Builder ResolvedImage
+
+	// The run image the app image was built atop.
+	RunImage ResolvedImage
+
+	// The buildpack group that passed detection and contributed to the image.
+	Buildpacks []lifecycle.GroupBuildpack
+
+	// The processes contributed by buildpacks.
+	Processes []launch.Process
+
+	// Version of the lifecycle that ran the build.
+	LifecycleVersion string
+
+	// Platform API version pack used to talk to the lifecycle.
+	PlatformAPI string
+
+	// Each lifecycle phase that ran, in order of execution.
+	Phases []PhaseResult
+}
+
+// ResolvedImage identifies the exact image used for an image reference.
+type ResolvedImage struct {
+	// Name of the image as it was requested.
+	Name string
+
+	// Content-addressable identifier of the image.
+	// This is the manifest digest for a registry image,
+	// or the image ID for an image read from the docker daemon.
+	Digest string
+}
+
+// PhaseResult records how long a single lifecycle phase took to run.
+type PhaseResult struct {
+	// Name of the phase, e.g. 'detector' or 'creator'.
+	Name string
+
+	// Time taken by the phase.
+	Duration time.Duration
 }
 
 // Build configures settings for the build container(s) and lifecycle.
@@ -176,13 +315,81 @@ type ContainerConfig struct {
 // If any configuration is deemed invalid, or if any lifecycle phases fail,
 // an error will be returned and no image produced.
 func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
+	return c.build(ctx, opts, nil)
+}
+
+// BuildWithResult builds an app image in the same way as Build.
+// On success it also returns a BuildResult describing the image that was produced.
+func (c *Client) BuildWithResult(ctx context.Context, opts BuildOptions) (*BuildResult, error) {
+	result := &BuildResult{}
+	if err := c.build(ctx, opts, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// build runs the build. If result is not nil, it is populated as the build progresses.
+func (c *Client) build(ctx context.Context, opts BuildOptions, result *BuildResult) error {
+	if opts.EventHandler != nil {
+		c = c.withEventHandler(opts.EventHandler)
+	}
+
 	imageRef, err := c.parseTagReference(opts.Image)
 	if err != nil {
 		return errors.Wrapf(err, "invalid image name '%s'", opts.Image)
 	}
 
-	appPath, err := c.processAppPath(opts.AppPath)
+	platform, err := parsePlatform(opts.Platform)
 	if err != nil {
+		return errors.Wrap(err, "invalid platform")
+	}
+
+	var output *imageOutput
+	if opts.Output != "" {
+		if output, err = parseImageOutput(opts.Output); err != nil {
+			return errors.Wrapf(err, "invalid output '%s'", opts.Output)
+		}
+	}
+
+	if opts.CacheImage != "" {
+		if !opts.Publish {
+			return errors.New("cache image requires the publish option")
+		}
+		if _, err := name.ParseReference(opts.CacheImage, name.WeakValidation); err != nil {
+			return errors.Wrapf(err, "invalid cache image name '%s'", opts.CacheImage)
+		}
+	}
+
+	var (
+		appPath         string
+		fileFilter      = opts.FileFilter
+		projectMetadata lifecycle.ProjectMetadata
+	)
+	if gitsource.IsSource(opts.AppPath) {
+		checkout, err := c.checkoutGitSource(ctx, opts.AppPath)
+		if err != nil {
+			return errors.Wrapf(err, "invalid app path '%s'", opts.AppPath)
+		}
+		defer os.RemoveAll(checkout.Dir)
+
+		appPath = checkout.AppPath
+		projectMetadata = gitProjectMetadata(checkout)
+		if fileFilter == nil {
+			if fileFilter, err = projectFileFilter(appPath, c.logger); err != nil {
+				return err
+			}
+		}
+	} else if opts.AppPath == StdinAppPath {
+		stdinPath, err := readAppFromStdin(opts.AppReader)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(stdinPath)
+
+		if appPath, err = c.processAppPath(stdinPath); err != nil {
+			return errors.Wrap(err, "invalid app read from stdin")
+		}
+	} else if appPath, err = c.processAppPath(opts.AppPath); err != nil {
 		return errors.Wrapf(err, "invalid app path '%s'", opts.AppPath)
 	}
 
@@ -193,18 +400,31 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		return errors.Wrapf(err, "invalid builder '%s'", opts.Builder)
 	}
 
-	rawBuilderImage, err := c.imageFetcher.Fetch(ctx, builderRef.Name(), true, opts.PullPolicy)
+	rawBuilderImage, err := c.fetchForPlatform(ctx, builderRef.Name(), true, opts.PullPolicy, platform)
 	if err != nil {
 		return errors.Wrapf(err, "failed to fetch builder image '%s'", builderRef.Name())
 	}
 
+	if platform != nil {
+		if err := c.validateDaemonPlatform(ctx, rawBuilderImage); err != nil {
+			return errors.Wrapf(err, "invalid builder %s", style.Symbol(opts.Builder))
+		}
+	}
+
 	bldr, err := c.getBuilder(rawBuilderImage)
 	if err != nil {
 		return errors.Wrapf(err, "invalid builder %s", style.Symbol(opts.Builder))
 	}
 
+	if result != nil {
+		// resolve the builder now, as the ephemeral builder created from it renames the image
+		if result.Builder, err = resolveImage(rawBuilderImage.Name(), rawBuilderImage); err != nil {
+			return err
+		}
+	}
+
 	runImageName := c.resolveRunImage(opts.RunImage, imageRef.Context().RegistryStr(), builderRef.Context().RegistryStr(), bldr.Stack(), opts.AdditionalMirrors, opts.Publish)
-	runImage, err := c.validateRunImage(ctx, runImageName, opts.PullPolicy, opts.Publish, bldr.StackID)
+	runImage, err := c.validateRunImage(ctx, runImageName, opts.PullPolicy, opts.Publish, bldr.StackID, platform)
 	if err != nil {
 		return errors.Wrapf(err, "invalid run-image '%s'", runImageName)
 	}
@@ -214,7 +434,12 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		return err
 	}
 
-	fetchedBPs, order, err := c.processBuildpacks(ctx, bldr.Image(), bldr.Buildpacks(), bldr.Order(), opts.Buildpacks, opts.PullPolicy, opts.Publish, opts.Registry)
+	inlineBPs, err := createInlineBuildpacks(opts.InlineBuildpacks, bldr.Image(), bldr.StackID)
+	if err != nil {
+		return err
+	}
+
+	fetchedBPs, order, err := c.processBuildpacks(ctx, bldr.Image(), bldr.Buildpacks(), bldr.Order(), opts.Buildpacks, inlineBPs, opts.PullPolicy, opts.Publish, opts.Registry)
 	if err != nil {
 		return err
 	}
@@ -223,11 +448,15 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		return errors.Wrap(err, "validating stack mixins")
 	}
 
-	ephemeralBuilder, err := c.createEphemeralBuilder(rawBuilderImage, opts.Env, order, fetchedBPs)
+	ephemeralBuilder, reused, err := c.ephemeralBuilder(ctx, rawBuilderImage, opts.Env, order, fetchedBPs)
 	if err != nil {
 		return err
 	}
-	defer c.docker.ImageRemove(context.Background(), ephemeralBuilder.Name(), types.ImageRemoveOptions{Force: true})
+	defer c.releaseEphemeralBuilder(context.Background(), ephemeralBuilder.Name(), opts.Env)
+
+	if opts.EventHandler != nil {
+		opts.EventHandler(events.EphemeralBuilderCreated{Name: ephemeralBuilder.Name(), Builder: builderRef.Name(), Reused: reused})
+	}
 
 	builderPlatformAPIs := append(
 		ephemeralBuilder.LifecycleDescriptor().APIs.Platform.Deprecated,
@@ -254,12 +483,32 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		c.logger.Warn(warning)
 	}
 
+	secrets, err := processSecrets(opts.Secrets)
+	if err != nil {
+		return err
+	}
+
+	sshAgents, err := processSSHAgents(imgOS, opts.ContainerConfig.SSH)
+	if err != nil {
+		return err
+	}
+
+	resources, err := processResources(opts.ContainerConfig)
+	if err != nil {
+		return err
+	}
+
+	if err := validateTimeouts(opts.Timeout, opts.PhaseTimeouts); err != nil {
+		return err
+	}
+
 	lifecycleOpts := build.LifecycleOptions{
 		AppPath:            appPath,
 		Image:              imageRef,
 		Builder:            ephemeralBuilder,
 		RunImage:           runImageName,
 		ClearCache:         opts.ClearCache,
+		CacheImage:         opts.CacheImage,
 		Publish:            opts.Publish,
 		UseCreator:         false,
 		TrustBuilder:       opts.TrustBuilder,
@@ -271,10 +520,35 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		AdditionalTags:     opts.AdditionalTags,
 		Volumes:            processedVolumes,
 		DefaultProcessType: opts.DefaultProcessType,
-		FileFilter:         opts.FileFilter,
+		FileFilter:         fileFilter,
+		EventHandler:       opts.EventHandler,
+		ProjectMetadata:    projectMetadata,
+		Secrets:            secrets,
+		SSHAgents:          sshAgents,
+		Resources:          resources,
+		Timeout:            opts.Timeout,
+		PhaseTimeouts:      opts.PhaseTimeouts,
+	}
+
+	if platform != nil {
+		// export onto the run image selected for the platform, rather than the one the registry chooses
+		lifecycleOpts.RunImage = runImage.Name()
 	}
 
 	lifecycleVersion := ephemeralBuilder.LifecycleDescriptor().Info.Version
+
+	if result != nil {
+		if err := c.prepareBuildResult(result, imageRef, opts.AdditionalTags, runImageName, runImage, ephemeralBuilder); err != nil {
+			return err
+		}
+
+		lifecycleOpts.EventHandler = recordPhases(result, opts.EventHandler)
+	}
+
+	if opts.CacheImage == "" {
+		c.recordCacheUse(imageRef)
+	}
+
 	// Technically the creator is supported as of platform API version 0.3 (lifecycle version 0.7.0+) but earlier versions
 	// have bugs that make using the creator problematic.
 	lifecycleSupportsCreator := !lifecycleVersion.LessThan(semver.MustParse(minLifecycleVersionSupportingCreator))
@@ -286,16 +560,17 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 			return errors.Wrap(err, "executing lifecycle")
 		}
 
-		return c.logImageNameAndSha(ctx, opts.Publish, imageRef)
+		return c.finishBuild(ctx, opts.Publish, imageRef, output, result)
 	}
 
 	if !opts.TrustBuilder {
 		if lifecycleImageSupported(imgOS, lifecycleVersion) {
-			lifecycleImage, err := c.imageFetcher.Fetch(
+			lifecycleImage, err := c.fetchForPlatform(
 				ctx,
 				fmt.Sprintf("%s:%s", lifecycleImageRepo, lifecycleVersion.String()),
 				true,
 				opts.PullPolicy,
+				platform,
 			)
 			if err != nil {
 				return errors.Wrap(err, "fetching lifecycle image")
@@ -311,7 +586,7 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		return errors.Wrap(err, "executing lifecycle. This may be the result of using an untrusted builder")
 	}
 
-	return c.logImageNameAndSha(ctx, opts.Publish, imageRef)
+	return c.finishBuild(ctx, opts.Publish, imageRef, output, result)
 }
 
 func lifecycleImageSupported(builderOS string, lifecycleVersion *builder.Version) bool {
@@ -363,11 +638,11 @@ func (c *Client) getBuilder(img imgutil.Image) (*builder.Builder, error) {
 	return bldr, nil
 }
 
-func (c *Client) validateRunImage(context context.Context, name string, pullPolicy config.PullPolicy, publish bool, expectedStack string) (imgutil.Image, error) {
+func (c *Client) validateRunImage(context context.Context, name string, pullPolicy config.PullPolicy, publish bool, expectedStack string, platform *v1.Platform) (imgutil.Image, error) {
 	if name == "" {
 		return nil, errors.New("run image must be specified")
 	}
-	img, err := c.imageFetcher.Fetch(context, name, !publish, pullPolicy)
+	img, err := c.fetchForPlatform(context, name, !publish, pullPolicy, platform)
 	if err != nil {
 		return nil, err
 	}
@@ -502,13 +777,99 @@ func (c *Client) processAppPath(appPath string) (string, error) {
 		}
 
 		if !isZip {
-			return "", errors.New("app path must be a directory or zip")
+			if _, err := fh.Seek(0, io.SeekStart); err != nil {
+				return "", errors.Wrap(err, "read file")
+			}
+
+			isTar, err := archive.IsTar(fh)
+			if err != nil {
+				return "", errors.Wrap(err, "check tar")
+			}
+
+			if !isTar {
+				return "", errors.New("app path must be a directory, zip, tar or tar.gz")
+			}
 		}
 	}
 
 	return resolvedAppPath, nil
 }
 
+// readAppFromStdin saves the app archive streamed to r to a temporary file, so that it can be validated before it is read.
+func readAppFromStdin(r io.Reader) (string, error) {
+	if r == nil {
+		return "", errors.Errorf("app path %s requires an app reader", style.Symbol(StdinAppPath))
+	}
+
+	file, err := ioutil.TempFile("", "pack.stdin.")
+	if err != nil {
+		return "", errors.Wrap(err, "creating temp file")
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, r); err != nil {
+		os.Remove(file.Name())
+		return "", errors.Wrap(err, "reading app from stdin")
+	}
+	return file.Name(), nil
+}
+
+// checkoutGitSource clones the git repository referenced by an app path to a temporary directory.
+func (c *Client) checkoutGitSource(ctx context.Context, appPath string) (gitsource.Checkout, error) {
+	source, err := gitsource.Parse(appPath)
+	if err != nil {
+		return gitsource.Checkout{}, err
+	}
+
+	dir, err := ioutil.TempDir("", "pack.git.")
+	if err != nil {
+		return gitsource.Checkout{}, errors.Wrap(err, "creating temp dir")
+	}
+
+	c.logger.Debugf("Cloning repository %s", style.Symbol(source.PublicRepository()))
+	checkout, err := source.Clone(ctx, dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		return gitsource.Checkout{}, err
+	}
+
+	c.logger.Debugf("Checked out commit %s", style.Symbol(checkout.Commit))
+	return checkout, nil
+}
+
+// gitProjectMetadata describes the source of an app checked out from a git repository,
+// to be added to the app image by the exporter.
+func gitProjectMetadata(checkout gitsource.Checkout) lifecycle.ProjectMetadata {
+	metadata := map[string]interface{}{
+		"repository": checkout.PublicRepository(),
+	}
+	if checkout.Ref != "" {
+		metadata["refs"] = []string{checkout.Ref}
+	}
+
+	return lifecycle.ProjectMetadata{
+		Source: &lifecycle.ProjectSource{
+			Type:     "git",
+			Version:  map[string]interface{}{"commit": checkout.Commit},
+			Metadata: metadata,
+		},
+	}
+}
+
+// projectFileFilter returns the file filter of the project descriptor in an app directory, if any.
+func projectFileFilter(appPath string, logger logging.Logger) (func(string) bool, error) {
+	descriptorPath := filepath.Join(appPath, "project.toml")
+	if _, err := os.Stat(descriptorPath); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	descriptor, err := project.ReadProjectDescriptorWithLogger(descriptorPath, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading project descriptor")
+	}
+	return descriptor.FileFilter(), nil
+}
+
 func (c *Client) processProxyConfig(config *ProxyConfig) ProxyConfig {
 	var (
 		httpProxy, httpsProxy, noProxy string
@@ -534,7 +895,7 @@ func (c *Client) processProxyConfig(config *ProxyConfig) ProxyConfig {
 }
 
 // processBuildpacks computes an order group based on the existing builder order and declared buildpacks. Additionally,
-// it returns buildpacks that should be added to the builder.
+// it returns buildpacks that should be added to the builder. Declared buildpacks naming an inline buildpack use it.
 //
 // Visual examples:
 //
@@ -577,9 +938,15 @@ func (c *Client) processProxyConfig(config *ProxyConfig) ProxyConfig {
 // 	----------
 // 	- group:
 //		- A
-func (c *Client) processBuildpacks(ctx context.Context, builderImage imgutil.Image, builderBPs []dist.BuildpackInfo, builderOrder dist.Order, declaredBPs []string, pullPolicy config.PullPolicy, publish bool, registry string) (fetchedBPs []dist.Buildpack, order dist.Order, err error) {
+func (c *Client) processBuildpacks(ctx context.Context, builderImage imgutil.Image, builderBPs []dist.BuildpackInfo, builderOrder dist.Order, declaredBPs []string, inlineBPs []dist.Buildpack, pullPolicy config.PullPolicy, publish bool, registry string) (fetchedBPs []dist.Buildpack, order dist.Order, err error) {
 	order = dist.Order{{Group: []dist.BuildpackRef{}}}
 	for _, bp := range declaredBPs {
+		if inlineBP, ok := findInlineBuildpack(bp, inlineBPs); ok {
+			fetchedBPs = append(fetchedBPs, inlineBP)
+			order = appendBuildpackToOrder(order, inlineBP.Descriptor().Info)
+			continue
+		}
+
 		locatorType, err := buildpack.GetLocatorType(bp, builderBPs)
 		if err != nil {
 			return nil, nil, err
@@ -729,9 +1096,9 @@ func ensureBPSupport(bpPath string) (err error) {
 	return nil
 }
 
-func (c *Client) createEphemeralBuilder(rawBuilderImage imgutil.Image, env map[string]string, order dist.Order, buildpacks []dist.Buildpack) (*builder.Builder, error) {
+func (c *Client) createEphemeralBuilder(rawBuilderImage imgutil.Image, builderName string, env map[string]string, order dist.Order, buildpacks []dist.Buildpack) (*builder.Builder, error) {
 	origBuilderName := rawBuilderImage.Name()
-	bldr, err := builder.New(rawBuilderImage, fmt.Sprintf("pack.local/builder/%x:latest", randString(10)))
+	bldr, err := builder.New(rawBuilderImage, builderName)
 	if err != nil {
 		return nil, errors.Wrapf(err, "invalid builder %s", style.Symbol(origBuilderName))
 	}
@@ -753,14 +1120,6 @@ func (c *Client) createEphemeralBuilder(rawBuilderImage imgutil.Image, env map[s
 	return bldr, nil
 }
 
-func randString(n int) string {
-	b := make([]byte, n)
-	for i := range b {
-		b[i] = 'a' + byte(rand.Intn(26))
-	}
-	return string(b)
-}
-
 func processVolumes(imgOS string, volumes []string) (processed []string, warnings []string, err error) {
 	parserOS := mounts.OSLinux
 	if imgOS == "windows" {
@@ -788,6 +1147,195 @@ func processVolumes(imgOS string, volumes []string) (processed []string, warning
 	return processed, warnings, nil
 }
 
+func processSecrets(specs []string) ([]build.Secret, error) {
+	var secrets []build.Secret
+	ids := map[string]bool{}
+	for _, spec := range specs {
+		secret, err := parseSecret(spec)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid secret %s", style.Symbol(spec))
+		}
+
+		if ids[secret.ID] {
+			return nil, errors.Errorf("secret %s is provided more than once", style.Symbol(secret.ID))
+		}
+		ids[secret.ID] = true
+
+		secrets = append(secrets, secret)
+	}
+	return secrets, nil
+}
+
+func parseSecret(spec string) (build.Secret, error) {
+	var id, src, env string
+	for _, field := range strings.Split(spec, ",") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return build.Secret{}, errors.Errorf("field %s must have the form 'key=value'", style.Symbol(field))
+		}
+
+		switch kv[0] {
+		case "id":
+			id = kv[1]
+		case "src":
+			src = kv[1]
+		case "env":
+			env = kv[1]
+		default:
+			return build.Secret{}, errors.Errorf("unknown field %s, must be one of 'id', 'src' or 'env'", style.Symbol(kv[0]))
+		}
+	}
+
+	// the id is the name of the secret file
+	if id == "" || id == "." || id == ".." || strings.ContainsAny(id, `/\:`) {
+		return build.Secret{}, errors.New("'id' must be a valid file name")
+	}
+
+	switch {
+	case src != "" && env != "":
+		return build.Secret{}, errors.New("only one of 'src' or 'env' may be provided")
+	case src != "":
+		path, err := expandHome(src)
+		if err != nil {
+			return build.Secret{}, err
+		}
+
+		value, err := ioutil.ReadFile(path)
+		if err != nil {
+			return build.Secret{}, errors.Wrap(err, "reading secret")
+		}
+		return build.Secret{ID: id, Value: value}, nil
+	case env != "":
+		value, ok := os.LookupEnv(env)
+		if !ok {
+			return build.Secret{}, errors.Errorf("environment variable %s is not set", style.Symbol(env))
+		}
+		return build.Secret{ID: id, Value: []byte(value)}, nil
+	default:
+		return build.Secret{}, errors.New("one of 'src' or 'env' must be provided")
+	}
+}
+
+func processSSHAgents(imgOS string, specs []string) ([]build.SSHAgent, error) {
+	if len(specs) > 0 && imgOS == "windows" {
+		return nil, errors.New("SSH agent forwarding is not supported for Windows builders")
+	}
+
+	var agents []build.SSHAgent
+	ids := map[string]bool{}
+	for _, spec := range specs {
+		agent, err := parseSSHAgent(spec)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid SSH agent %s", style.Symbol(spec))
+		}
+
+		if ids[agent.ID] {
+			return nil, errors.Errorf("SSH agent %s is provided more than once", style.Symbol(agent.ID))
+		}
+		ids[agent.ID] = true
+
+		agents = append(agents, agent)
+	}
+	return agents, nil
+}
+
+func parseSSHAgent(spec string) (build.SSHAgent, error) {
+	id, socket := spec, ""
+	if i := strings.Index(spec, "="); i >= 0 {
+		id, socket = spec[:i], spec[i+1:]
+	}
+
+	// the id is the name of the socket file
+	if id == "" || id == "." || id == ".." || strings.ContainsAny(id, `/\:`) {
+		return build.SSHAgent{}, errors.New("id must be a valid file name")
+	}
+
+	if socket == "" {
+		if id != build.DefaultSSHAgentID {
+			return build.SSHAgent{}, errors.Errorf("a socket must be provided in the form '%s=<socket path>'", id)
+		}
+
+		socket = os.Getenv("SSH_AUTH_SOCK")
+		if socket == "" {
+			return build.SSHAgent{}, errors.New("SSH_AUTH_SOCK is not set")
+		}
+	}
+
+	socket, err := expandHome(socket)
+	if err != nil {
+		return build.SSHAgent{}, err
+	}
+
+	// docker requires an absolute path to bind
+	if socket, err = filepath.Abs(socket); err != nil {
+		return build.SSHAgent{}, err
+	}
+	return build.SSHAgent{ID: id, Socket: socket}, nil
+}
+
+func processResources(config ContainerConfig) (build.Resources, error) {
+	if config.Memory < 0 {
+		return build.Resources{}, errors.New("memory limit must not be negative")
+	}
+	if config.CPUs < 0 {
+		return build.Resources{}, errors.New("CPUs must not be negative")
+	}
+	if config.PidsLimit < 0 {
+		return build.Resources{}, errors.New("PIDs limit must not be negative")
+	}
+
+	for _, host := range config.AddHosts {
+		parts := strings.SplitN(host, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || net.ParseIP(parts[1]) == nil {
+			return build.Resources{}, errors.Errorf("invalid host %s, must be in the form 'host:ip'", style.Symbol(host))
+		}
+	}
+
+	for _, server := range config.DNS {
+		if net.ParseIP(server) == nil {
+			return build.Resources{}, errors.Errorf("invalid DNS server %s, must be an IP address", style.Symbol(server))
+		}
+	}
+
+	return build.Resources{
+		Memory:     config.Memory,
+		NanoCPUs:   int64(config.CPUs * 1e9),
+		PidsLimit:  config.PidsLimit,
+		ExtraHosts: config.AddHosts,
+		DNS:        config.DNS,
+	}, nil
+}
+
+func validateTimeouts(timeout time.Duration, phaseTimeouts map[string]time.Duration) error {
+	if timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+
+	phases := stringset.FromSlice(build.PhaseNames)
+	for phase, phaseTimeout := range phaseTimeouts {
+		if _, ok := phases[phase]; !ok {
+			return errors.Errorf("unknown phase %s, must be one of %s", style.Symbol(phase), strings.Join(build.PhaseNames, ", "))
+		}
+		if phaseTimeout < 0 {
+			return errors.Errorf("timeout of phase %s must not be negative", style.Symbol(phase))
+		}
+	}
+	return nil
+}
+
+// expandHome expands a leading '~' to the home dir of the current user, as shells do not expand it within flag values.
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.Wrap(err, "getting home dir")
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
+
 func processMode(mode string) string {
 	if mode == "" {
 		return "ro"
@@ -796,6 +1344,161 @@ func processMode(mode string) string {
 	return mode
 }
 
+// prepareBuildResult records everything about the build that is known before the lifecycle runs.
+func (c *Client) prepareBuildResult(result *BuildResult, imageRef name.Reference, additionalTags []string, runImageName string, runImage imgutil.Image, ephemeralBuilder *builder.Builder) error {
+	var err error
+	if result.RunImage, err = resolveImage(runImageName, runImage); err != nil {
+		return err
+	}
+
+	platformAPI, err := build.FindLatestSupported(append(
+		ephemeralBuilder.LifecycleDescriptor().APIs.Platform.Deprecated,
+		ephemeralBuilder.LifecycleDescriptor().APIs.Platform.Supported...,
+	))
+	if err != nil {
+		return err
+	}
+
+	result.Tags = append([]string{imageRef.Name()}, additionalTags...)
+	result.LifecycleVersion = ephemeralBuilder.LifecycleDescriptor().Info.Version.String()
+	result.PlatformAPI = platformAPI.String()
+	return nil
+}
+
+func resolveImage(name string, img imgutil.Image) (ResolvedImage, error) {
+	id, err := img.Identifier()
+	if err != nil {
+		return ResolvedImage{}, errors.Wrapf(err, "reading identifier of image %s", style.Symbol(name))
+	}
+
+	resolved := ResolvedImage{Name: name}
+	switch v := id.(type) {
+	case local.IDIdentifier:
+		resolved.Digest = v.String()
+	case remote.DigestIdentifier:
+		resolved.Digest = v.Digest.DigestStr()
+	}
+	return resolved, nil
+}
+
+// recordPhases returns an event handler that adds each successful phase to result,
+// before passing the event on to next, if set.
+func recordPhases(result *BuildResult, next events.Handler) events.Handler {
+	return func(e events.Event) {
+		if finished, ok := e.(events.PhaseFinished); ok && finished.Err == nil {
+			result.Phases = append(result.Phases, PhaseResult{Name: finished.Phase, Duration: finished.Duration})
+		}
+
+		if next != nil {
+			next(e)
+		}
+	}
+}
+
+// withEventHandler returns a copy of the client that reports its image fetches to handler.
+func (c *Client) withEventHandler(handler events.Handler) *Client {
+	client := *c
+	client.imageFetcher = &eventImageFetcher{fetcher: c.imageFetcher, handler: handler}
+	return &client
+}
+
+type eventImageFetcher struct {
+	fetcher ImageFetcher
+	handler events.Handler
+}
+
+func (f *eventImageFetcher) Fetch(ctx context.Context, name string, daemon bool, pullPolicy config.PullPolicy) (imgutil.Image, error) {
+	f.handler(events.ImageFetchStarted{Image: name})
+	img, err := f.fetcher.Fetch(ctx, name, daemon, pullPolicy)
+	f.handler(events.ImageFetchFinished{Image: name, Err: err})
+	return img, err
+}
+
+func (c *Client) finishBuild(ctx context.Context, publish bool, imageRef name.Reference, output *imageOutput, result *BuildResult) error {
+	if output != nil {
+		if err := c.saveImage(publish, imageRef, output); err != nil {
+			return err
+		}
+	}
+
+	if result != nil {
+		if err := c.describeBuiltImage(ctx, publish, imageRef, result); err != nil {
+			return err
+		}
+	}
+
+	return c.logImageNameAndSha(ctx, publish, imageRef)
+}
+
+// describeBuiltImage completes result with the metadata the lifecycle wrote to the built image.
+func (c *Client) describeBuiltImage(ctx context.Context, publish bool, imageRef name.Reference, result *BuildResult) error {
+	img, err := c.imageFetcher.Fetch(ctx, imageRef.Name(), !publish, config.PullNever)
+	if err != nil {
+		return errors.Wrap(err, "fetching built image")
+	}
+
+	id, err := img.Identifier()
+	if err != nil {
+		return errors.Wrap(err, "reading image identifier")
+	}
+
+	switch v := id.(type) {
+	case local.IDIdentifier:
+		result.ImageID = v.String()
+		if result.ImageDigest, err = c.repoDigest(ctx, v.String(), imageRef); err != nil {
+			return err
+		}
+	case remote.DigestIdentifier:
+		result.ImageDigest = v.Digest.DigestStr()
+		if result.ImageID, err = publishedImageID(v.Digest); err != nil {
+			return err
+		}
+	}
+
+	var buildMD lifecycle.BuildMetadata
+	if _, err := dist.GetLabel(img, lifecycle.BuildMetadataLabel, &buildMD); err != nil {
+		return err
+	}
+
+	result.Buildpacks = buildMD.Buildpacks
+	result.Processes = buildMD.Processes
+	return nil
+}
+
+// repoDigest returns the digest of the daemon image imageID in the repository of imageRef,
+// or an empty string if the daemon knows of none.
+func (c *Client) repoDigest(ctx context.Context, imageID string, imageRef name.Reference) (string, error) {
+	inspect, _, err := c.docker.ImageInspectWithRaw(ctx, imageID)
+	if err != nil {
+		return "", errors.Wrap(err, "inspecting built image")
+	}
+
+	for _, repoDigest := range inspect.RepoDigests {
+		digest, err := name.NewDigest(repoDigest, name.WeakValidation)
+		if err != nil {
+			continue
+		}
+		if digest.Context().Name() == imageRef.Context().Name() {
+			return digest.DigestStr(), nil
+		}
+	}
+	return "", nil
+}
+
+// publishedImageID returns the ID of a published image, the digest of its config.
+func publishedImageID(digest name.Digest) (string, error) {
+	img, err := v1remote.Image(digest, v1remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return "", errors.Wrap(err, "reading built image")
+	}
+
+	configName, err := img.ConfigName()
+	if err != nil {
+		return "", errors.Wrap(err, "reading config digest of built image")
+	}
+	return configName.String(), nil
+}
+
 func (c *Client) logImageNameAndSha(ctx context.Context, publish bool, imageRef name.Reference) error {
 	// The image name and sha are printed in the lifecycle logs, and there is no need to print it again, unless output is suppressed.
 	if !logging.IsQuiet(c.logger) {
@@ -0,0 +1,152 @@
+package pack
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/buildpacks/imgutil"
+
+	"github.com/buildpacks/pack/config"
+	"github.com/buildpacks/pack/internal/build"
+	"github.com/buildpacks/pack/logging"
+)
+
+// AppBuild is one of the builds run by BuildAll.
+type AppBuild struct {
+	// Name of the app, which prefixes the output of its build.
+	Name string
+
+	// Options the app is built with.
+	Options BuildOptions
+}
+
+// BuildAllOptions configures BuildAll.
+type BuildAllOptions struct {
+	// Apps to build.
+	Apps []AppBuild
+
+	// Maximum number of builds to run at the same time.
+	// Defaults to 1, building the apps one after the other.
+	Parallel int
+}
+
+// AppBuildResult is the outcome of the build of an app.
+type AppBuildResult struct {
+	// Name of the app.
+	Name string
+
+	// Image built for the app.
+	Image string
+
+	// How long the build took.
+	Duration time.Duration
+
+	// Why the build failed, or nil if it succeeded.
+	Err error
+}
+
+// BuildAll builds several apps, running up to Parallel builds at the same time.
+// Images shared by the builds, such as the builder and run image, are pulled once.
+// The output of each build is prefixed with the name of its app.
+// A failed build does not stop the others, and the result of every build is returned in the order of the apps.
+func (c *Client) BuildAll(ctx context.Context, opts BuildAllOptions) []AppBuildResult {
+	parallel := opts.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	shared := c.withImageFetcher(newSharedImageFetcher(c.imageFetcher))
+
+	results := make([]AppBuildResult, len(opts.Apps))
+	slots := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, app := range opts.Apps {
+		results[i] = AppBuildResult{Name: app.Name, Image: app.Options.Image}
+
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(result *AppBuildResult, app AppBuild) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			start := time.Now()
+			result.Err = shared.withLogger(logging.NewPrefixLogger(c.logger, app.Name)).Build(ctx, app.Options)
+			result.Duration = time.Since(start)
+		}(&results[i], app)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// withImageFetcher returns a copy of the client that fetches images with fetcher.
+func (c *Client) withImageFetcher(fetcher ImageFetcher) *Client {
+	client := *c
+	client.imageFetcher = fetcher
+	return &client
+}
+
+// withLogger returns a copy of the client that logs to logger, including the output of the lifecycle.
+func (c *Client) withLogger(logger logging.Logger) *Client {
+	client := *c
+	client.logger = logger
+	// other executors, such as fakes, are left alone
+	if _, ok := c.lifecycleExecutor.(*build.LifecycleExecutor); ok {
+		client.lifecycleExecutor = build.NewLifecycleExecutor(logger, c.docker)
+	}
+	return &client
+}
+
+// sharedImageFetcher pulls each image to the daemon at most once, across the builds it is shared by.
+// Later fetches of an image wait for it to be pulled, and then read it from the daemon.
+type sharedImageFetcher struct {
+	fetcher ImageFetcher
+
+	mu    sync.Mutex
+	pulls map[string]*imagePull
+}
+
+type imagePull struct {
+	done chan struct{}
+	err  error
+}
+
+func newSharedImageFetcher(fetcher ImageFetcher) *sharedImageFetcher {
+	return &sharedImageFetcher{fetcher: fetcher, pulls: map[string]*imagePull{}}
+}
+
+func (f *sharedImageFetcher) Fetch(ctx context.Context, name string, daemon bool, pullPolicy config.PullPolicy) (imgutil.Image, error) {
+	// remote images are not pulled, and each build needs an image of its own to modify
+	if !daemon || pullPolicy == config.PullNever {
+		return f.fetcher.Fetch(ctx, name, daemon, pullPolicy)
+	}
+
+	f.mu.Lock()
+	pull, pulling := f.pulls[name]
+	if !pulling {
+		pull = &imagePull{done: make(chan struct{})}
+		f.pulls[name] = pull
+	}
+	f.mu.Unlock()
+
+	if !pulling {
+		img, err := f.fetcher.Fetch(ctx, name, daemon, pullPolicy)
+		pull.err = err
+		close(pull.done)
+		return img, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-pull.done:
+	}
+
+	if pull.err != nil {
+		return nil, pull.err
+	}
+	return f.fetcher.Fetch(ctx, name, daemon, config.PullNever)
+}
@@ -0,0 +1,114 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/buildpacks/imgutil/fakes"
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/config"
+	"github.com/buildpacks/pack/internal/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+	"github.com/buildpacks/pack/testmocks"
+)
+
+func TestBuildAll(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "BuildAll", testBuildAll, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+func testBuildAll(t *testing.T, when spec.G, it spec.S) {
+	var (
+		mockController   *gomock.Controller
+		mockImageFetcher *testmocks.MockImageFetcher
+		mockDockerClient *testmocks.MockCommonAPIClient
+		out              bytes.Buffer
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockImageFetcher = testmocks.NewMockImageFetcher(mockController)
+		mockDockerClient = testmocks.NewMockCommonAPIClient(mockController)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#BuildAll", func() {
+		it("returns the result of every build in the order of the apps", func() {
+			subject, err := NewClient(WithLogger(logging.NewLogWithWriters(&out, &out)), WithFetcher(mockImageFetcher), WithDockerClient(mockDockerClient))
+			h.AssertNil(t, err)
+
+			results := subject.BuildAll(context.TODO(), BuildAllOptions{
+				Apps: []AppBuild{
+					{Name: "first", Options: BuildOptions{Image: "!invalid-image!"}},
+					{Name: "second", Options: BuildOptions{Image: "other/app", CacheImage: "some/cache"}},
+				},
+				Parallel: 2,
+			})
+
+			h.AssertEq(t, len(results), 2)
+			h.AssertEq(t, results[0].Name, "first")
+			h.AssertEq(t, results[0].Image, "!invalid-image!")
+			h.AssertError(t, results[0].Err, "invalid image name '!invalid-image!'")
+			h.AssertEq(t, results[1].Name, "second")
+			h.AssertError(t, results[1].Err, "cache image requires the publish option")
+		})
+	})
+
+	when("#sharedImageFetcher", func() {
+		var (
+			subject *sharedImageFetcher
+			image   *fakes.Image
+		)
+
+		it.Before(func() {
+			subject = newSharedImageFetcher(mockImageFetcher)
+			image = fakes.NewImage("some/builder", "", nil)
+		})
+
+		it("pulls an image once, and reads it from the daemon afterwards", func() {
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", true, config.PullAlways).Return(image, nil).Times(1)
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", true, config.PullNever).Return(image, nil).Times(4)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 5; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := subject.Fetch(context.TODO(), "some/builder", true, config.PullAlways)
+					h.AssertNil(t, err)
+				}()
+			}
+			wg.Wait()
+		})
+
+		it("fetches remote images every time", func() {
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/run-image", false, config.PullAlways).Return(image, nil).Times(2)
+
+			for i := 0; i < 2; i++ {
+				_, err := subject.Fetch(context.TODO(), "some/run-image", false, config.PullAlways)
+				h.AssertNil(t, err)
+			}
+		})
+
+		it("fails later fetches when the pull failed", func() {
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/builder", true, config.PullAlways).Return(nil, errors.New("some-pull-error"))
+
+			_, err := subject.Fetch(context.TODO(), "some/builder", true, config.PullAlways)
+			h.AssertError(t, err, "some-pull-error")
+
+			_, err = subject.Fetch(context.TODO(), "some/builder", true, config.PullIfNotPresent)
+			h.AssertError(t, err, "some-pull-error")
+		})
+	})
+}
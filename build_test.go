@@ -1,6 +1,7 @@
 package pack
 
 import (
+	"archive/tar"
 	"bytes"
 	"context"
 	"crypto/sha256"
@@ -8,8 +9,10 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"log"
 	"math/rand"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -28,19 +31,30 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/buildpacks/pack/config"
+	"github.com/buildpacks/pack/events"
 	"github.com/buildpacks/pack/internal/build"
 	cfg "github.com/buildpacks/pack/internal/config"
 	rg "github.com/buildpacks/pack/internal/registry"
 
 	"github.com/Masterminds/semver"
 	"github.com/buildpacks/imgutil/fakes"
+	"github.com/buildpacks/lifecycle"
 	"github.com/buildpacks/lifecycle/api"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/golang/mock/gomock"
+	ggcrregistry "github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	ggcrremote "github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/heroku/color"
 	"github.com/onsi/gomega/ghttp"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
 
+	"github.com/buildpacks/pack/internal/archive"
 	"github.com/buildpacks/pack/internal/blob"
 	"github.com/buildpacks/pack/internal/builder"
 	"github.com/buildpacks/pack/internal/buildpackage"
@@ -49,6 +63,7 @@ import (
 	ilogging "github.com/buildpacks/pack/internal/logging"
 	"github.com/buildpacks/pack/internal/style"
 	h "github.com/buildpacks/pack/testhelpers"
+	"github.com/buildpacks/pack/testmocks"
 )
 
 func TestBuild(t *testing.T) {
@@ -88,6 +103,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 
 		tmpDir, err = ioutil.TempDir("", "build-test")
 		h.AssertNil(t, err)
+		h.AssertNil(t, os.Setenv("PACK_HOME", tmpDir))
 
 		defaultBuilderImage = newFakeBuilderImage(t, tmpDir, defaultBuilderName, defaultBuilderStackID, defaultRunImageName, builder.DefaultLifecycleVersion, newLinuxImage)
 		h.AssertNil(t, defaultBuilderImage.SetLabel("io.buildpacks.stack.mixins", `["mixinA", "build:mixinB", "mixinX", "build:mixinY"]`))
@@ -142,6 +158,7 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 		fakeDefaultRunImage.Cleanup()
 		fakeMirror1.Cleanup()
 		fakeMirror2.Cleanup()
+		os.Unsetenv("PACK_HOME")
 		os.RemoveAll(tmpDir)
 		fakeLifecycleImage.Cleanup()
 	})
@@ -266,6 +283,151 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("#BuildWithResult", func() {
+			var (
+				builtImage     *fakes.Image
+				mockController *gomock.Controller
+				mockDocker     *testmocks.MockCommonAPIClient
+			)
+
+			it.Before(func() {
+				mockController = gomock.NewController(t)
+				mockDocker = testmocks.NewMockCommonAPIClient(mockController)
+				subject.docker = mockDocker
+				mockDocker.EXPECT().ImageList(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+				// no ephemeral builder was saved by a previous build
+				mockDocker.EXPECT().
+					ImageInspectWithRaw(gomock.Any(), gomock.Not("363c754893f0efe22480b4359a5956cf3bd3ce22742fc576973c61348308c2e4")).
+					Return(types.ImageInspect{}, nil, errdefs.NotFound(errors.New("no such image"))).
+					AnyTimes()
+
+				builtImage = fakes.NewImage("index.docker.io/some/app:latest", "", local.IDIdentifier{
+					ImageID: "363c754893f0efe22480b4359a5956cf3bd3ce22742fc576973c61348308c2e4",
+				})
+				h.AssertNil(t, builtImage.SetLabel("io.buildpacks.build.metadata", `{
+  "buildpacks": [{"id": "some/bp", "version": "1.2.3"}],
+  "processes": [{"type": "web", "command": "some-command", "args": ["some-arg"], "direct": true}]
+}`))
+				fakeImageFetcher.LocalImages[builtImage.Name()] = builtImage
+				fakeDefaultRunImage.SetIdentifier(local.IDIdentifier{ImageID: "some-run-image-id"})
+			})
+
+			it.After(func() {
+				mockController.Finish()
+				h.AssertNil(t, builtImage.Cleanup())
+			})
+
+			it("describes the built image", func() {
+				mockDocker.EXPECT().
+					ImageInspectWithRaw(gomock.Any(), "363c754893f0efe22480b4359a5956cf3bd3ce22742fc576973c61348308c2e4").
+					Return(types.ImageInspect{}, nil, nil)
+
+				result, err := subject.BuildWithResult(context.TODO(), BuildOptions{
+					Image:          "some/app",
+					Builder:        defaultBuilderName,
+					AdditionalTags: []string{"some/app:other-tag"},
+				})
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, result.ImageID, "363c754893f0efe22480b4359a5956cf3bd3ce22742fc576973c61348308c2e4")
+				h.AssertEq(t, result.ImageDigest, "")
+				h.AssertEq(t, result.Tags, []string{"index.docker.io/some/app:latest", "some/app:other-tag"})
+				h.AssertEq(t, result.Builder.Name, defaultBuilderName)
+				h.AssertEq(t, result.RunImage, ResolvedImage{Name: "default/run", Digest: "some-run-image-id"})
+				h.AssertEq(t, result.LifecycleVersion, builder.DefaultLifecycleVersion)
+				h.AssertEq(t, result.PlatformAPI, "0.4")
+				h.AssertEq(t, len(result.Buildpacks), 1)
+				h.AssertEq(t, result.Buildpacks[0].ID, "some/bp")
+				h.AssertEq(t, result.Buildpacks[0].Version, "1.2.3")
+				h.AssertEq(t, len(result.Processes), 1)
+				h.AssertEq(t, result.Processes[0].Type, "web")
+				h.AssertEq(t, result.Processes[0].Command, "some-command")
+			})
+
+			it("reads the digest of an image in the daemon from its repo digests", func() {
+				mockDocker.EXPECT().
+					ImageInspectWithRaw(gomock.Any(), "363c754893f0efe22480b4359a5956cf3bd3ce22742fc576973c61348308c2e4").
+					Return(types.ImageInspect{RepoDigests: []string{
+						"other/app@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+						"some/app@sha256:1111111111111111111111111111111111111111111111111111111111111111",
+					}}, nil, nil)
+
+				result, err := subject.BuildWithResult(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+				})
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, result.ImageID, "363c754893f0efe22480b4359a5956cf3bd3ce22742fc576973c61348308c2e4")
+				h.AssertEq(t, result.ImageDigest, "sha256:1111111111111111111111111111111111111111111111111111111111111111")
+			})
+
+			it("reads the ID of a published image from its config", func() {
+				server := httptest.NewServer(ggcrregistry.New(ggcrregistry.Logger(log.New(ioutil.Discard, "", 0))))
+				defer server.Close()
+				repoName := strings.TrimPrefix(server.URL, "http://") + "/some/app"
+
+				img, err := random.Image(1024, 1)
+				h.AssertNil(t, err)
+				ref, err := name.ParseReference(repoName)
+				h.AssertNil(t, err)
+				h.AssertNil(t, ggcrremote.Write(ref, img))
+				digest, err := img.Digest()
+				h.AssertNil(t, err)
+				configName, err := img.ConfigName()
+				h.AssertNil(t, err)
+
+				digestRef, err := name.NewDigest(repoName + "@" + digest.String())
+				h.AssertNil(t, err)
+				publishedImage := fakes.NewImage(repoName+":latest", "", remote.DigestIdentifier{Digest: digestRef})
+				defer publishedImage.Cleanup()
+				fakeImageFetcher.RemoteImages[publishedImage.Name()] = publishedImage
+				fakeImageFetcher.RemoteImages[fakeDefaultRunImage.Name()] = fakeDefaultRunImage
+
+				result, err := subject.BuildWithResult(context.TODO(), BuildOptions{
+					Image:   repoName,
+					Builder: defaultBuilderName,
+					Publish: true,
+				})
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, result.ImageDigest, digest.String())
+				h.AssertEq(t, result.ImageID, configName.String())
+			})
+
+			it("records the duration of each lifecycle phase", func() {
+				mockDocker.EXPECT().
+					ImageInspectWithRaw(gomock.Any(), "363c754893f0efe22480b4359a5956cf3bd3ce22742fc576973c61348308c2e4").
+					Return(types.ImageInspect{}, nil, nil)
+
+				result, err := subject.BuildWithResult(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+				})
+				h.AssertNil(t, err)
+
+				fakeLifecycle.Opts.EventHandler(events.PhaseFinished{Phase: "detector", Duration: time.Second})
+				fakeLifecycle.Opts.EventHandler(events.PhaseFinished{Phase: "builder", Duration: time.Minute})
+				fakeLifecycle.Opts.EventHandler(events.PhaseFinished{Phase: "exporter", ExitCode: 1, Err: errors.New("some-error")})
+				h.AssertEq(t, result.Phases, []PhaseResult{
+					{Name: "detector", Duration: time.Second},
+					{Name: "builder", Duration: time.Minute},
+				})
+			})
+
+			when("the built image cannot be found", func() {
+				it("errors", func() {
+					delete(fakeImageFetcher.LocalImages, builtImage.Name())
+
+					_, err := subject.BuildWithResult(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: defaultBuilderName,
+					})
+					h.AssertError(t, err, "fetching built image")
+				})
+			})
+		})
+
 		when("AppDir option", func() {
 			it("defaults to the current working directory", func() {
 				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
@@ -280,8 +442,10 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 				h.AssertEq(t, fakeLifecycle.Opts.AppPath, resolvedWd)
 			})
 			for fileDesc, appPath := range map[string]string{
-				"zip": filepath.Join("testdata", "zip-file.zip"),
-				"jar": filepath.Join("testdata", "jar-file.jar"),
+				"zip":    filepath.Join("testdata", "zip-file.zip"),
+				"jar":    filepath.Join("testdata", "jar-file.jar"),
+				"tar":    filepath.Join("testdata", "tar-file.tar"),
+				"tar.gz": filepath.Join("testdata", "tar-gz-file.tar.gz"),
 			} {
 				fileDesc := fileDesc
 				appPath := appPath
@@ -298,8 +462,8 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 
 			for fileDesc, testData := range map[string][]string{
 				"non-existent": {"not/exist/path", "does not exist"},
-				"empty":        {filepath.Join("testdata", "empty-file"), "app path must be a directory or zip"},
-				"non-zip":      {filepath.Join("testdata", "non-zip-file"), "app path must be a directory or zip"},
+				"empty":        {filepath.Join("testdata", "empty-file"), "app path must be a directory, zip, tar or tar.gz"},
+				"non-zip":      {filepath.Join("testdata", "non-zip-file"), "app path must be a directory, zip, tar or tar.gz"},
 			} {
 				fileDesc := fileDesc
 				appPath := testData[0]
@@ -327,6 +491,48 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 				h.AssertEq(t, fakeLifecycle.Opts.AppPath, absPath)
 			})
 
+			when("appDir is read from the app reader", func() {
+				it("supports tar.gz archives", func() {
+					file, err := os.Open(filepath.Join("testdata", "tar-gz-file.tar.gz"))
+					h.AssertNil(t, err)
+					defer file.Close()
+
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:     "some/app",
+						Builder:   defaultBuilderName,
+						AppPath:   StdinAppPath,
+						AppReader: file,
+					}))
+
+					h.AssertContains(t, filepath.Base(fakeLifecycle.Opts.AppPath), "pack.stdin.")
+					_, err = os.Stat(fakeLifecycle.Opts.AppPath)
+					h.AssertTrue(t, os.IsNotExist(err))
+				})
+
+				it("errors for anything other than an archive", func() {
+					file, err := os.Open(filepath.Join("testdata", "non-zip-file"))
+					h.AssertNil(t, err)
+					defer file.Close()
+
+					err = subject.Build(context.TODO(), BuildOptions{
+						Image:     "some/app",
+						Builder:   defaultBuilderName,
+						AppPath:   StdinAppPath,
+						AppReader: file,
+					})
+					h.AssertError(t, err, "invalid app read from stdin: app path must be a directory, zip, tar or tar.gz")
+				})
+
+				it("errors without an app reader", func() {
+					err := subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: defaultBuilderName,
+						AppPath: StdinAppPath,
+					})
+					h.AssertError(t, err, "app path '-' requires an app reader")
+				})
+			})
+
 			when("appDir is a symlink", func() {
 				var (
 					appDirName     = "some-app"
@@ -398,6 +604,96 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 					h.AssertEq(t, fakeLifecycle.Opts.AppPath, absoluteAppDir)
 				})
 			})
+
+			when("appDir is a git repository", func() {
+				var (
+					repoDir string
+					commit  string
+				)
+
+				it.Before(func() {
+					repoDir = filepath.Join(tmpDir, "some-repo")
+					h.AssertNil(t, os.MkdirAll(filepath.Join(repoDir, "some-app"), 0755))
+					h.AssertNil(t, ioutil.WriteFile(filepath.Join(repoDir, "some-app", "project.toml"), []byte("[build]\nexclude = [\"*.log\"]\n"), 0644))
+
+					repository, err := git.PlainInit(repoDir, false)
+					h.AssertNil(t, err)
+					worktree, err := repository.Worktree()
+					h.AssertNil(t, err)
+					_, err = worktree.Add("some-app")
+					h.AssertNil(t, err)
+					hash, err := worktree.Commit("add app", &git.CommitOptions{
+						Author: &object.Signature{Name: "John Doe", Email: "john@doe.org", When: time.Now()},
+					})
+					h.AssertNil(t, err)
+					commit = hash.String()
+				})
+
+				it("builds the app checked out from the repository", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: defaultBuilderName,
+						AppPath: "git+file://" + filepath.ToSlash(repoDir) + "#master:some-app",
+					}))
+
+					h.AssertEq(t, filepath.Base(fakeLifecycle.Opts.AppPath), "some-app")
+					h.AssertEq(t, fakeLifecycle.Opts.ProjectMetadata.Source.Type, "git")
+					h.AssertEq(t, fakeLifecycle.Opts.ProjectMetadata.Source.Version["commit"], commit)
+					h.AssertEq(t, fakeLifecycle.Opts.ProjectMetadata.Source.Metadata["repository"], "file://"+filepath.ToSlash(repoDir))
+					h.AssertEq(t, fakeLifecycle.Opts.ProjectMetadata.Source.Metadata["refs"], []string{"master"})
+
+					_, err := os.Stat(fakeLifecycle.Opts.AppPath)
+					h.AssertEq(t, os.IsNotExist(err), true)
+				})
+
+				it("leaves the git directory out of the app layer", func() {
+					lifecycle := &appLayerLifecycle{FakeLifecycle: fakeLifecycle}
+					subject.lifecycleExecutor = lifecycle
+
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: defaultBuilderName,
+						AppPath: "git+file://" + filepath.ToSlash(repoDir),
+					}))
+
+					h.AssertContains(t, strings.Join(lifecycle.entries, "\n"), "/workspace/some-app/project.toml")
+					for _, entry := range lifecycle.entries {
+						h.AssertNotContains(t, entry, ".git")
+					}
+				})
+
+				it("applies the file filter of the project descriptor", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: defaultBuilderName,
+						AppPath: "git+file://" + filepath.ToSlash(repoDir) + "#:some-app",
+					}))
+
+					h.AssertEq(t, fakeLifecycle.Opts.FileFilter("app.go"), true)
+					h.AssertEq(t, fakeLifecycle.Opts.FileFilter("debug.log"), false)
+				})
+
+				it("prefers the file filter option", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:      "some/app",
+						Builder:    defaultBuilderName,
+						AppPath:    "git+file://" + filepath.ToSlash(repoDir) + "#:some-app",
+						FileFilter: func(string) bool { return true },
+					}))
+
+					h.AssertEq(t, fakeLifecycle.Opts.FileFilter("debug.log"), true)
+				})
+
+				it("errors when the ref does not exist", func() {
+					err := subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: defaultBuilderName,
+						AppPath: "git+file://" + filepath.ToSlash(repoDir) + "#unknown",
+					})
+
+					h.AssertError(t, err, "ref 'unknown' not found in repository")
+				})
+			})
 		})
 
 		when("Builder option", func() {
@@ -644,6 +940,145 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("cache usage", func() {
+			it("records the use of the volume caches", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+				}))
+
+				volumes, err := cacheVolumeNames("some/app")
+				h.AssertNil(t, err)
+				for volume := range volumes {
+					_, err := os.Stat(filepath.Join(tmpDir, "cache-usage", volume))
+					h.AssertNil(t, err)
+				}
+			})
+		})
+
+		when("EventHandler option", func() {
+			var received []events.Event
+
+			it.Before(func() {
+				received = nil
+			})
+
+			handler := func(e events.Event) {
+				received = append(received, e)
+			}
+
+			it("reports image fetches", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:        "some/app",
+					Builder:      defaultBuilderName,
+					EventHandler: handler,
+				}))
+
+				h.AssertEq(t, received[0], events.ImageFetchStarted{Image: defaultBuilderName})
+				h.AssertEq(t, received[1], events.ImageFetchFinished{Image: defaultBuilderName})
+			})
+
+			it("reports the ephemeral builder", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:        "some/app",
+					Builder:      defaultBuilderName,
+					EventHandler: handler,
+				}))
+
+				var created []events.Event
+				for _, e := range received {
+					if e.Kind() == "ephemeral-builder-created" {
+						created = append(created, e)
+					}
+				}
+				h.AssertEq(t, created, []events.Event{
+					events.EphemeralBuilderCreated{Name: defaultBuilderImage.Name(), Builder: defaultBuilderName},
+				})
+			})
+
+			it("passes it through to lifecycle", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:        "some/app",
+					Builder:      defaultBuilderName,
+					EventHandler: handler,
+				}))
+
+				fakeLifecycle.Opts.EventHandler(events.PhaseStarted{Phase: "detector"})
+				h.AssertEq(t, received[len(received)-1], events.PhaseStarted{Phase: "detector"})
+			})
+		})
+
+		when("CacheImage option", func() {
+			it.Before(func() {
+				remoteRunImage := fakes.NewImage("default/run", "", nil)
+				h.AssertNil(t, remoteRunImage.SetLabel("io.buildpacks.stack.id", defaultBuilderStackID))
+				h.AssertNil(t, remoteRunImage.SetLabel("io.buildpacks.stack.mixins", `["mixinA", "mixinX", "run:mixinZ"]`))
+				fakeImageFetcher.RemoteImages[remoteRunImage.Name()] = remoteRunImage
+			})
+
+			it("passes it through to lifecycle", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:      "some/app",
+					Builder:    defaultBuilderName,
+					Publish:    true,
+					CacheImage: "some/cache-image",
+				}))
+				h.AssertEq(t, fakeLifecycle.Opts.CacheImage, "some/cache-image")
+			})
+
+			it("fails when publish is not set", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:      "some/app",
+					Builder:    defaultBuilderName,
+					CacheImage: "some/cache-image",
+				})
+				h.AssertError(t, err, "cache image requires the publish option")
+			})
+
+			it("fails for an invalid cache image name", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:      "some/app",
+					Builder:    defaultBuilderName,
+					Publish:    true,
+					CacheImage: "%%%",
+				})
+				h.AssertError(t, err, "invalid cache image name")
+			})
+		})
+
+		when("Platform option", func() {
+			it("fails for an invalid platform", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:    "some/app",
+					Builder:  defaultBuilderName,
+					Platform: "linux",
+				})
+				h.AssertError(t, err, "invalid platform: platform 'linux' must be in the form '<os>/<arch>[/<variant>]'")
+			})
+
+			it("fails when the local builder is for another platform", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:      "some/app",
+					Builder:    defaultBuilderName,
+					Platform:   "linux/arm64",
+					PullPolicy: config.PullNever,
+				})
+				h.AssertError(t, err, "is for platform 'linux/amd64', not 'linux/arm64'")
+			})
+		})
+
+		when("Output option", func() {
+			it("fails for an unknown output format", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					Output:  "zip:some/file.zip",
+				})
+				h.AssertError(t, err, "invalid output 'zip:some/file.zip': unknown output format 'zip'")
+				h.AssertNil(t, fakeLifecycle.Opts.Image)
+			})
+		})
+
 		when("Buildpacks option", func() {
 			assertOrderEquals := func(content string) {
 				t.Helper()
@@ -1404,6 +1839,64 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("InlineBuildpacks option", func() {
+			it("adds inline buildpacks to the ephemeral builder at their position", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					Buildpacks: []string{
+						"example/migrate",
+						"buildpack.1.id@buildpack.1.version",
+					},
+					InlineBuildpacks: []InlineBuildpack{{
+						ID:     "example/migrate",
+						API:    "0.3",
+						Shell:  "/bin/bash",
+						Script: "./migrate.sh",
+					}},
+				})
+
+				h.AssertNil(t, err)
+				bldr, err := builder.FromImage(defaultBuilderImage)
+				h.AssertNil(t, err)
+				h.AssertEq(t, bldr.Order(), dist.Order{
+					{Group: []dist.BuildpackRef{
+						{BuildpackInfo: dist.BuildpackInfo{ID: "example/migrate", Version: "0.0.0"}},
+						{BuildpackInfo: dist.BuildpackInfo{ID: "buildpack.1.id", Version: "buildpack.1.version"}},
+					}},
+				})
+
+				layerTar, err := defaultBuilderImage.FindLayerWithPath("/cnb/buildpacks/example_migrate/0.0.0/bin/build")
+				h.AssertNil(t, err)
+				h.AssertTarFileContents(t, layerTar, "/cnb/buildpacks/example_migrate/0.0.0/bin/build", "#!/bin/bash\n./migrate.sh\n")
+				h.AssertTarFileContents(t, layerTar, "/cnb/buildpacks/example_migrate/0.0.0/bin/detect", "#!/bin/bash\nexit 0\n")
+			})
+
+			it("ignores inline buildpacks the buildpacks do not name", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:            "some/app",
+					Builder:          defaultBuilderName,
+					InlineBuildpacks: []InlineBuildpack{{ID: "example/migrate", API: "0.3", Script: "./migrate.sh"}},
+				})
+
+				h.AssertNil(t, err)
+				bldr, err := builder.FromImage(defaultBuilderImage)
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(bldr.Order()), 2)
+			})
+
+			it("fails when an inline buildpack has no API", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:            "some/app",
+					Builder:          defaultBuilderName,
+					Buildpacks:       []string{"example/migrate"},
+					InlineBuildpacks: []InlineBuildpack{{ID: "example/migrate", Script: "./migrate.sh"}},
+				})
+
+				h.AssertError(t, err, "inline buildpack 'example/migrate' must have an API version")
+			})
+		})
+
 		when("Env option", func() {
 			it("should set the env on the ephemeral builder", func() {
 				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
@@ -1421,6 +1914,116 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("Secrets option", func() {
+			var secretFile string
+
+			it.Before(func() {
+				secretFile = filepath.Join(tmpDir, "some-secret-file")
+				h.AssertNil(t, ioutil.WriteFile(secretFile, []byte("some-file-secret"), 0600))
+				h.AssertNil(t, os.Setenv("SOME_SECRET_VAR", "some-env-secret"))
+			})
+
+			it.After(func() {
+				h.AssertNil(t, os.Unsetenv("SOME_SECRET_VAR"))
+			})
+
+			it("passes secrets from files and env vars to the lifecycle", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					Secrets: []string{"id=from-file,src=" + secretFile, "id=from-env,env=SOME_SECRET_VAR"},
+				}))
+
+				h.AssertEq(t, fakeLifecycle.Opts.Secrets, []build.Secret{
+					{ID: "from-file", Value: []byte("some-file-secret")},
+					{ID: "from-env", Value: []byte("some-env-secret")},
+				})
+			})
+
+			it("does not add secrets to the ephemeral builder", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					Secrets: []string{"id=from-env,env=SOME_SECRET_VAR"},
+				}))
+
+				_, err := defaultBuilderImage.FindLayerWithPath("/platform/env/from-env")
+				h.AssertNotNil(t, err)
+			})
+
+			for _, test := range []struct {
+				desc   string
+				specs  []string
+				errMsg string
+			}{
+				{"without id", []string{"src=/some/file"}, "'id' must be a valid file name"},
+				{"with an id that is a path", []string{"id=../some-id,env=SOME_SECRET_VAR"}, "'id' must be a valid file name"},
+				{"without a source", []string{"id=some-id"}, "one of 'src' or 'env' must be provided"},
+				{"with both sources", []string{"id=some-id,src=/some/file,env=SOME_SECRET_VAR"}, "only one of 'src' or 'env' may be provided"},
+				{"with unknown fields", []string{"id=some-id,type=file"}, "unknown field 'type'"},
+				{"with an unset env var", []string{"id=some-id,env=NOT_SET_VAR"}, "environment variable 'NOT_SET_VAR' is not set"},
+				{"with a missing file", []string{"id=some-id,src=/not/exist"}, "reading secret"},
+				{"with duplicate ids", []string{"id=some-id,env=SOME_SECRET_VAR", "id=some-id,env=SOME_SECRET_VAR"}, "secret 'some-id' is provided more than once"},
+			} {
+				test := test
+
+				it(fmt.Sprintf("errors for a secret %s", test.desc), func() {
+					err := subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: defaultBuilderName,
+						Secrets: test.specs,
+					})
+					h.AssertError(t, err, test.errMsg)
+				})
+			}
+		})
+
+		when("ephemeral builder", func() {
+			var env map[string]string
+
+			it.Before(func() {
+				env = map[string]string{"key1": "value1"}
+			})
+
+			it("is named after its content", func() {
+				builderName, err := ephemeralBuilderName(defaultBuilderImage, env, nil, nil)
+				h.AssertNil(t, err)
+
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					Env:     env,
+				}))
+
+				h.AssertEq(t, fakeLifecycle.Opts.Builder.Name(), builderName)
+				h.AssertEq(t, defaultBuilderImage.IsSaved(), true)
+			})
+
+			it("is reused when one with the same content exists", func() {
+				builderName, err := ephemeralBuilderName(defaultBuilderImage, env, nil, nil)
+				h.AssertNil(t, err)
+				existingBuilder := newFakeBuilderImage(t, tmpDir, builderName, defaultBuilderStackID, defaultRunImageName, builder.DefaultLifecycleVersion, newLinuxImage)
+				fakeImageFetcher.LocalImages[builderName] = existingBuilder
+
+				mockController := gomock.NewController(t)
+				defer mockController.Finish()
+				mockDocker := testmocks.NewMockCommonAPIClient(mockController)
+				mockDocker.EXPECT().ImageInspectWithRaw(gomock.Any(), builderName).Return(types.ImageInspect{}, nil, nil)
+				mockDocker.EXPECT().ImageList(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+				subject.docker = mockDocker
+
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					Env:     env,
+				}))
+
+				h.AssertEq(t, fakeLifecycle.Opts.Builder.Name(), builderName)
+				h.AssertEq(t, defaultBuilderImage.IsSaved(), false)
+				h.AssertEq(t, existingBuilder.IsSaved(), false)
+			})
+		})
+
 		when("Publish option", func() {
 			var remoteRunImage, builderWithoutLifecycleImageOrCreator *fakes.Image
 
@@ -2003,6 +2606,138 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("resource options", func() {
+			it("passes resource limits and timeouts to the lifecycle", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					ContainerConfig: ContainerConfig{
+						Memory:    1 << 30,
+						CPUs:      1.5,
+						PidsLimit: 100,
+						AddHosts:  []string{"some-host:10.0.0.1"},
+						DNS:       []string{"10.0.0.2"},
+					},
+					Timeout:       time.Hour,
+					PhaseTimeouts: map[string]time.Duration{"builder": 30 * time.Minute},
+				}))
+
+				h.AssertEq(t, fakeLifecycle.Opts.Resources, build.Resources{
+					Memory:     1 << 30,
+					NanoCPUs:   1500000000,
+					PidsLimit:  100,
+					ExtraHosts: []string{"some-host:10.0.0.1"},
+					DNS:        []string{"10.0.0.2"},
+				})
+				h.AssertEq(t, fakeLifecycle.Opts.Timeout, time.Hour)
+				h.AssertEq(t, fakeLifecycle.Opts.PhaseTimeouts, map[string]time.Duration{"builder": 30 * time.Minute})
+			})
+
+			it("fails for a host that is not in the form 'host:ip'", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:           "some/app",
+					Builder:         defaultBuilderName,
+					ContainerConfig: ContainerConfig{AddHosts: []string{"some-host"}},
+				})
+				h.AssertError(t, err, "invalid host 'some-host', must be in the form 'host:ip'")
+			})
+
+			it("fails for a DNS server that is not an IP address", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:           "some/app",
+					Builder:         defaultBuilderName,
+					ContainerConfig: ContainerConfig{DNS: []string{"dns.example.com"}},
+				})
+				h.AssertError(t, err, "invalid DNS server 'dns.example.com', must be an IP address")
+			})
+
+			it("fails for a negative memory limit", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:           "some/app",
+					Builder:         defaultBuilderName,
+					ContainerConfig: ContainerConfig{Memory: -1},
+				})
+				h.AssertError(t, err, "memory limit must not be negative")
+			})
+
+			it("fails for a timeout of an unknown phase", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:         "some/app",
+					Builder:       defaultBuilderName,
+					PhaseTimeouts: map[string]time.Duration{"compiler": time.Minute},
+				})
+				h.AssertError(t, err, "unknown phase 'compiler', must be one of detector, analyzer, restorer, builder, exporter, creator")
+			})
+		})
+
+		when("SSH option", func() {
+			var origAuthSock string
+
+			it.Before(func() {
+				origAuthSock = os.Getenv("SSH_AUTH_SOCK")
+				h.AssertNil(t, os.Setenv("SSH_AUTH_SOCK", "/some/agent.sock"))
+			})
+
+			it.After(func() {
+				h.AssertNil(t, os.Setenv("SSH_AUTH_SOCK", origAuthSock))
+			})
+
+			it("forwards the default agent and agents by id", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					ContainerConfig: ContainerConfig{
+						SSH: []string{"default", "other=/other/agent.sock"},
+					},
+				}))
+
+				h.AssertEq(t, fakeLifecycle.Opts.SSHAgents, []build.SSHAgent{
+					{ID: "default", Socket: "/some/agent.sock"},
+					{ID: "other", Socket: "/other/agent.sock"},
+				})
+			})
+
+			it("errors when SSH_AUTH_SOCK is not set for the default agent", func() {
+				h.AssertNil(t, os.Unsetenv("SSH_AUTH_SOCK"))
+
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:           "some/app",
+					Builder:         defaultBuilderName,
+					ContainerConfig: ContainerConfig{SSH: []string{"default"}},
+				})
+				h.AssertError(t, err, "SSH_AUTH_SOCK is not set")
+			})
+
+			it("errors when an agent other than the default has no socket", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:           "some/app",
+					Builder:         defaultBuilderName,
+					ContainerConfig: ContainerConfig{SSH: []string{"other"}},
+				})
+				h.AssertError(t, err, "a socket must be provided in the form 'other=<socket path>'")
+			})
+
+			it("errors for duplicate ids", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:           "some/app",
+					Builder:         defaultBuilderName,
+					ContainerConfig: ContainerConfig{SSH: []string{"default", "default=/other/agent.sock"}},
+				})
+				h.AssertError(t, err, "SSH agent 'default' is provided more than once")
+			})
+
+			it("errors for Windows builders", func() {
+				h.AssertNil(t, defaultBuilderImage.SetOS("windows"))
+
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:           "some/app",
+					Builder:         defaultBuilderName,
+					ContainerConfig: ContainerConfig{SSH: []string{"default"}},
+				})
+				h.AssertError(t, err, "SSH agent forwarding is not supported for Windows builders")
+			})
+		})
+
 		when("Volumes option", func() {
 			when("on posix", func() {
 				it.Before(func() {
@@ -2232,6 +2967,57 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 	})
+
+	when("#Detect", func() {
+		it("reports the selected group and the results of each group", func() {
+			fakeLifecycle.DetectResult = build.DetectResult{
+				Group: []lifecycle.GroupBuildpack{{ID: "buildpack.2.id", Version: "buildpack.2.version"}},
+				Groups: []build.DetectedGroup{
+					{Buildpacks: []build.DetectedBuildpack{{ID: "buildpack.1.id", Version: "buildpack.1.version", Status: build.DetectFail}}},
+					{Buildpacks: []build.DetectedBuildpack{{ID: "buildpack.2.id", Version: "buildpack.2.version", Status: build.DetectPass}}},
+				},
+			}
+
+			result, err := subject.Detect(context.TODO(), DetectOptions{
+				Builder: defaultBuilderName,
+				AppPath: filepath.Join("testdata", "some-app"),
+			})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, result.Group, []lifecycle.GroupBuildpack{{ID: "buildpack.2.id", Version: "buildpack.2.version"}})
+			h.AssertEq(t, result.Groups, []DetectedGroup{
+				{Buildpacks: []DetectedBuildpack{{ID: "buildpack.1.id", Version: "buildpack.1.version", Status: "fail"}}},
+				{Buildpacks: []DetectedBuildpack{{ID: "buildpack.2.id", Version: "buildpack.2.version", Status: "pass"}}},
+			})
+			h.AssertEq(t, filepath.Base(fakeLifecycle.Opts.AppPath), "some-app")
+			h.AssertEq(t, fakeLifecycle.Opts.Builder.Name(), fakeLifecycle.Opts.LifecycleImage)
+		})
+
+		it("reports the results along with the error when no group passes detection", func() {
+			fakeLifecycle.DetectResult = build.DetectResult{
+				Groups: []build.DetectedGroup{
+					{Buildpacks: []build.DetectedBuildpack{{ID: "buildpack.1.id", Version: "buildpack.1.version", Status: build.DetectFail}}},
+				},
+			}
+			fakeLifecycle.DetectError = errors.New("failed with status code: 100")
+
+			result, err := subject.Detect(context.TODO(), DetectOptions{
+				Builder: defaultBuilderName,
+				AppPath: filepath.Join("testdata", "some-app"),
+			})
+			h.AssertError(t, err, "failed with status code: 100")
+			h.AssertEq(t, len(result.Groups), 1)
+			h.AssertEq(t, len(result.Group), 0)
+		})
+
+		it("errors when the builder does not exist", func() {
+			_, err := subject.Detect(context.TODO(), DetectOptions{
+				Builder: "missing/builder",
+				AppPath: filepath.Join("testdata", "some-app"),
+			})
+			h.AssertError(t, err, "failed to fetch builder image 'index.docker.io/missing/builder:latest'")
+		})
+	})
 }
 
 func diffIDForFile(t *testing.T, path string) string {
@@ -2347,3 +3133,32 @@ func (f *executeFailsLifecycle) Execute(_ context.Context, opts build.LifecycleO
 	f.Opts = opts
 	return errors.New("")
 }
+
+func (f *executeFailsLifecycle) Detect(_ context.Context, opts build.LifecycleOptions) (build.DetectResult, error) {
+	f.Opts = opts
+	return build.DetectResult{}, errors.New("")
+}
+
+// appLayerLifecycle records the entries of the app layer a build would add, while the app exists.
+type appLayerLifecycle struct {
+	*ifakes.FakeLifecycle
+	entries []string
+}
+
+func (l *appLayerLifecycle) Execute(ctx context.Context, opts build.LifecycleOptions) error {
+	reader := archive.ReadDirAsTar(opts.AppPath, "/workspace", 0, 0, -1, true, opts.FileFilter)
+	defer reader.Close()
+
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		l.entries = append(l.entries, header.Name)
+	}
+	return l.FakeLifecycle.Execute(ctx, opts)
+}
@@ -0,0 +1,204 @@
+package pack
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/cache"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// CacheInfo describes a build or launch cache kept in a docker volume.
+type CacheInfo struct {
+	// Name of the docker volume holding the cache.
+	Volume string
+
+	// Name of the app image the cache was created for.
+	// Empty for caches created by versions of pack that did not record it.
+	Image string
+
+	// Kind of cache, either 'build' or 'launch'.
+	Kind string
+
+	// Size of the volume in bytes, or -1 if docker did not report it.
+	Size int64
+
+	// When the volume was created.
+	Created time.Time
+
+	// When the cache was last used by a build, or the zero time if unknown.
+	LastUsed time.Time
+}
+
+// RemoveCacheOptions defines which caches are removed by RemoveCache.
+type RemoveCacheOptions struct {
+	// Name of the app image whose caches are removed.
+	Image string
+
+	// Remove every cache, instead of those of Image.
+	All bool
+}
+
+// ListCaches returns the build and launch caches kept in docker volumes, sorted by volume name.
+func (c *Client) ListCaches(ctx context.Context) ([]CacheInfo, error) {
+	usage, err := c.docker.DiskUsage(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading docker disk usage")
+	}
+
+	usageDir, err := cacheUsageDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var caches []CacheInfo
+	for _, vol := range usage.Volumes {
+		if !strings.HasPrefix(vol.Name, cache.VolumePrefix) {
+			continue
+		}
+
+		info, err := cacheInfo(vol, usageDir)
+		if err != nil {
+			return nil, err
+		}
+		caches = append(caches, info)
+	}
+
+	sort.Slice(caches, func(i, j int) bool {
+		return caches[i].Volume < caches[j].Volume
+	})
+	return caches, nil
+}
+
+// InspectCache returns the caches of an app image.
+func (c *Client) InspectCache(ctx context.Context, imageName string) ([]CacheInfo, error) {
+	volumes, err := cacheVolumeNames(imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := c.ListCaches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var caches []CacheInfo
+	for _, info := range all {
+		if volumes[info.Volume] {
+			caches = append(caches, info)
+		}
+	}
+
+	if len(caches) == 0 {
+		return nil, errors.Errorf("no caches found for image %s", style.Symbol(imageName))
+	}
+	return caches, nil
+}
+
+// RemoveCache removes the caches selected by opts, and returns the caches that were removed.
+func (c *Client) RemoveCache(ctx context.Context, opts RemoveCacheOptions) ([]CacheInfo, error) {
+	var (
+		caches []CacheInfo
+		err    error
+	)
+	switch {
+	case opts.All:
+		caches, err = c.ListCaches(ctx)
+	case opts.Image != "":
+		caches, err = c.InspectCache(ctx, opts.Image)
+	default:
+		return nil, errors.New("an image or the all option is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	usageDir, err := cacheUsageDir()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, info := range caches {
+		if err := c.docker.VolumeRemove(ctx, info.Volume, true); err != nil {
+			return nil, errors.Wrapf(err, "removing cache volume %s", style.Symbol(info.Volume))
+		}
+
+		if err := cache.ForgetUse(usageDir, info.Volume); err != nil {
+			return nil, err
+		}
+	}
+	return caches, nil
+}
+
+// recordCacheUse notes that the volume caches of an image are used by a build.
+// Failing to do so is not fatal to the build.
+func (c *Client) recordCacheUse(imageRef name.Reference) {
+	usageDir, err := cacheUsageDir()
+	if err == nil {
+		for _, kind := range []string{"build", "launch"} {
+			if err = cache.RecordUse(usageDir, cache.NewVolumeCache(imageRef, kind, c.docker).Name()); err != nil {
+				break
+			}
+		}
+	}
+
+	if err != nil {
+		c.logger.Debugf("Unable to record cache use: %s", err)
+	}
+}
+
+func cacheInfo(vol *types.Volume, usageDir string) (CacheInfo, error) {
+	info := CacheInfo{
+		Volume: vol.Name,
+		Image:  vol.Labels[cache.ImageLabel],
+		Kind:   vol.Labels[cache.KindLabel],
+		Size:   -1,
+	}
+
+	if info.Kind == "" {
+		info.Kind = vol.Name[strings.LastIndex(vol.Name, ".")+1:]
+	}
+
+	if vol.UsageData != nil {
+		info.Size = vol.UsageData.Size
+	}
+
+	if created, err := time.Parse(time.RFC3339, vol.CreatedAt); err == nil {
+		info.Created = created
+	}
+
+	var err error
+	if info.LastUsed, err = cache.LastUse(usageDir, vol.Name); err != nil {
+		return CacheInfo{}, errors.Wrapf(err, "reading last use of cache volume %s", style.Symbol(vol.Name))
+	}
+	return info, nil
+}
+
+// cacheVolumeNames returns the names of the volumes that may hold caches of an image.
+func cacheVolumeNames(imageName string) (map[string]bool, error) {
+	imageRef, err := name.ParseReference(imageName, name.WeakValidation)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid image name '%s'", imageName)
+	}
+
+	return map[string]bool{
+		cache.NewVolumeCache(imageRef, "build", nil).Name():  true,
+		cache.NewVolumeCache(imageRef, "launch", nil).Name(): true,
+	}, nil
+}
+
+func cacheUsageDir() (string, error) {
+	home, err := config.PackHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "cache-usage"), nil
+}
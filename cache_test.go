@@ -0,0 +1,221 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/cache"
+	"github.com/buildpacks/pack/internal/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+	"github.com/buildpacks/pack/testmocks"
+)
+
+func TestCache(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Cache", testCache, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+func testCache(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		mockDockerClient *testmocks.MockCommonAPIClient
+		mockController   *gomock.Controller
+		out              bytes.Buffer
+		packHome         string
+		buildVolume      string
+		launchVolume     string
+		otherVolume      string
+	)
+
+	it.Before(func() {
+		var err error
+		packHome, err = ioutil.TempDir("", "cache-test")
+		h.AssertNil(t, err)
+		h.AssertNil(t, os.Setenv("PACK_HOME", packHome))
+
+		mockController = gomock.NewController(t)
+		mockDockerClient = testmocks.NewMockCommonAPIClient(mockController)
+
+		subject, err = NewClient(WithLogger(logging.NewLogWithWriters(&out, &out)), WithDockerClient(mockDockerClient))
+		h.AssertNil(t, err)
+
+		imageRef, err := name.ParseReference("some/app", name.WeakValidation)
+		h.AssertNil(t, err)
+		buildVolume = cache.NewVolumeCache(imageRef, "build", nil).Name()
+		launchVolume = cache.NewVolumeCache(imageRef, "launch", nil).Name()
+
+		otherRef, err := name.ParseReference("some/other-app", name.WeakValidation)
+		h.AssertNil(t, err)
+		otherVolume = cache.NewVolumeCache(otherRef, "build", nil).Name()
+
+		mockDockerClient.EXPECT().DiskUsage(gomock.Any()).Return(types.DiskUsage{
+			Volumes: []*types.Volume{
+				{
+					Name:      launchVolume,
+					CreatedAt: "2020-10-01T10:00:00Z",
+					Labels: map[string]string{
+						"io.buildpacks.pack.cache.image": "index.docker.io/some/app:latest",
+						"io.buildpacks.pack.cache.kind":  "launch",
+					},
+					UsageData: &types.VolumeUsageData{Size: 2048},
+				},
+				{
+					Name:      buildVolume,
+					CreatedAt: "2020-10-01T10:00:00Z",
+					Labels: map[string]string{
+						"io.buildpacks.pack.cache.image": "index.docker.io/some/app:latest",
+						"io.buildpacks.pack.cache.kind":  "build",
+					},
+					UsageData: &types.VolumeUsageData{Size: 1024},
+				},
+				{
+					Name: otherVolume,
+				},
+				{
+					Name: "some-unrelated-volume",
+				},
+			},
+		}, nil).AnyTimes()
+	})
+
+	it.After(func() {
+		mockController.Finish()
+		os.Unsetenv("PACK_HOME")
+		h.AssertNil(t, os.RemoveAll(packHome))
+	})
+
+	when("#ListCaches", func() {
+		it("returns the cache volumes sorted by name", func() {
+			caches, err := subject.ListCaches(context.TODO())
+			h.AssertNil(t, err)
+
+			var volumes []string
+			for _, info := range caches {
+				volumes = append(volumes, info.Volume)
+			}
+			h.AssertSliceContainsOnly(t, volumes, buildVolume, launchVolume, otherVolume)
+			for i := 1; i < len(volumes); i++ {
+				if volumes[i-1] > volumes[i] {
+					t.Fatalf("expected volumes to be sorted, got %v", volumes)
+				}
+			}
+		})
+
+		it("describes labelled volumes", func() {
+			h.AssertNil(t, cache.RecordUse(filepath.Join(packHome, "cache-usage"), buildVolume))
+
+			caches, err := subject.ListCaches(context.TODO())
+			h.AssertNil(t, err)
+
+			for _, info := range caches {
+				if info.Volume != buildVolume {
+					continue
+				}
+
+				h.AssertEq(t, info.Image, "index.docker.io/some/app:latest")
+				h.AssertEq(t, info.Kind, "build")
+				h.AssertEq(t, info.Size, int64(1024))
+				h.AssertEq(t, info.Created, time.Date(2020, 10, 1, 10, 0, 0, 0, time.UTC))
+				h.AssertEq(t, info.LastUsed.IsZero(), false)
+			}
+		})
+
+		it("describes volumes without labels", func() {
+			caches, err := subject.ListCaches(context.TODO())
+			h.AssertNil(t, err)
+
+			for _, info := range caches {
+				if info.Volume != otherVolume {
+					continue
+				}
+
+				h.AssertEq(t, info.Image, "")
+				h.AssertEq(t, info.Kind, "build")
+				h.AssertEq(t, info.Size, int64(-1))
+				h.AssertEq(t, info.Created.IsZero(), true)
+				h.AssertEq(t, info.LastUsed.IsZero(), true)
+			}
+		})
+
+		when("docker fails to report disk usage", func() {
+			it("errors", func() {
+				mockDockerClient = testmocks.NewMockCommonAPIClient(mockController)
+				mockDockerClient.EXPECT().DiskUsage(gomock.Any()).Return(types.DiskUsage{}, errors.New("some-error"))
+				subject.docker = mockDockerClient
+
+				_, err := subject.ListCaches(context.TODO())
+				h.AssertError(t, err, "reading docker disk usage: some-error")
+			})
+		})
+	})
+
+	when("#InspectCache", func() {
+		it("returns the caches of the image", func() {
+			caches, err := subject.InspectCache(context.TODO(), "some/app")
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(caches), 2)
+			h.AssertEq(t, caches[0].Image, "index.docker.io/some/app:latest")
+			h.AssertEq(t, caches[1].Image, "index.docker.io/some/app:latest")
+		})
+
+		it("errors when the image has no caches", func() {
+			_, err := subject.InspectCache(context.TODO(), "some/unknown-app")
+			h.AssertError(t, err, "no caches found for image 'some/unknown-app'")
+		})
+	})
+
+	when("#RemoveCache", func() {
+		it("removes the caches of the image", func() {
+			usageDir := filepath.Join(packHome, "cache-usage")
+			h.AssertNil(t, cache.RecordUse(usageDir, buildVolume))
+
+			mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), buildVolume, true).Return(nil)
+			mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), launchVolume, true).Return(nil)
+
+			removed, err := subject.RemoveCache(context.TODO(), RemoveCacheOptions{Image: "some/app"})
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(removed), 2)
+
+			lastUse, err := cache.LastUse(usageDir, buildVolume)
+			h.AssertNil(t, err)
+			h.AssertEq(t, lastUse.IsZero(), true)
+		})
+
+		it("removes every cache with the all option", func() {
+			mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), buildVolume, true).Return(nil)
+			mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), launchVolume, true).Return(nil)
+			mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), otherVolume, true).Return(nil)
+
+			removed, err := subject.RemoveCache(context.TODO(), RemoveCacheOptions{All: true})
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(removed), 3)
+		})
+
+		it("errors when a volume cannot be removed", func() {
+			mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), gomock.Any(), true).Return(errors.New("volume is in use"))
+
+			_, err := subject.RemoveCache(context.TODO(), RemoveCacheOptions{Image: "some/app"})
+			h.AssertError(t, err, "volume is in use")
+		})
+
+		it("errors when neither an image nor the all option is provided", func() {
+			_, err := subject.RemoveCache(context.TODO(), RemoveCacheOptions{})
+			h.AssertError(t, err, "an image or the all option is required")
+		})
+	})
+}
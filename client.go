@@ -54,6 +54,15 @@ type ImageFactory interface {
 	NewImage(repoName string, local bool) (imgutil.Image, error)
 }
 
+// LayerReader is an interface representing the ability to list the layers of an image.
+type LayerReader interface {
+	// Layers returns the layers of an image fetched from the daemon or a registry, bottom-most first.
+	Layers(ctx context.Context, img imgutil.Image) ([]image.Layer, error)
+
+	// LayersWithSizes returns the layers of an image as Layers does, along with their sizes where known.
+	LayersWithSizes(ctx context.Context, img imgutil.Image) ([]image.Layer, error)
+}
+
 // Client is an orchestration object, it contains all parameters needed to
 // build an app image using Cloud Native Buildpacks.
 // All settings on this object should be changed through ClientOption functions.
@@ -64,7 +73,11 @@ type Client struct {
 	lifecycleExecutor LifecycleExecutor
 	docker            dockerClient.CommonAPIClient
 	imageFactory      ImageFactory
+	layerReader       LayerReader
 	experimental      bool
+
+	// the builds using each ephemeral builder, which are not to be removed
+	buildersInUse *builderUsage
 }
 
 // ClientOption is a type of function that mutate settings on the client.
@@ -93,6 +106,14 @@ func WithFetcher(f ImageFetcher) ClientOption {
 	}
 }
 
+// WithLayerReader supply your own layer reader.
+// A LayerReader lists the layers of images, such as to report the layers a rebase replaces.
+func WithLayerReader(r LayerReader) ClientOption {
+	return func(c *Client) {
+		c.layerReader = r
+	}
+}
+
 // WithDownloader supply your own downloader.
 // A Downloader is used to gather buildpacks from both remote urls, or local sources.
 func WithDownloader(d Downloader) ClientOption {
@@ -163,7 +184,12 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 		client.imageFactory = image.NewFactory(client.docker, authn.DefaultKeychain)
 	}
 
+	if client.layerReader == nil {
+		client.layerReader = image.NewLayerReader(client.docker, authn.DefaultKeychain)
+	}
+
 	client.lifecycleExecutor = build.NewLifecycleExecutor(client.logger, client.docker)
+	client.buildersInUse = newBuilderUsage()
 
 	return &client, nil
 }
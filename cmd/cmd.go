@@ -66,11 +66,17 @@ func NewPackCommand(logger ConfigurableLogger) (*cobra.Command, error) {
 	commands.AddHelpFlag(rootCmd, "pack")
 
 	rootCmd.AddCommand(commands.Build(logger, cfg, &packClient))
+	rootCmd.AddCommand(commands.BuildAll(logger, cfg, &packClient))
+	rootCmd.AddCommand(commands.Detect(logger, cfg, &packClient))
 	rootCmd.AddCommand(commands.NewBuilderCommand(logger, cfg, &packClient))
 	rootCmd.AddCommand(commands.NewBuildpackCommand(logger, cfg, &packClient, buildpackage.NewConfigReader()))
+	rootCmd.AddCommand(commands.NewCacheCommand(logger, &packClient))
 	rootCmd.AddCommand(commands.NewConfigCommand(logger, cfg, cfgPath, &packClient))
 	rootCmd.AddCommand(commands.Rebase(logger, cfg, &packClient))
+	rootCmd.AddCommand(commands.NewImageCommand(logger, cfg, &packClient))
 	rootCmd.AddCommand(commands.NewStackCommand(logger))
+	rootCmd.AddCommand(commands.NewProjectCommand(logger))
+	rootCmd.AddCommand(commands.Prune(logger, &packClient))
 
 	rootCmd.AddCommand(commands.InspectImage(logger, imagewriter.NewFactory(), cfg, &packClient))
 	rootCmd.AddCommand(commands.InspectBuildpack(logger, &cfg, &packClient))
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Masterminds/semver"
 	"github.com/buildpacks/imgutil"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/pkg/errors"
 
 	pubbldr "github.com/buildpacks/pack/builder"
@@ -38,16 +39,26 @@ type CreateBuilderOptions struct {
 
 	// Strategy for updating images before a build.
 	PullPolicy config.PullPolicy
+
+	// Platform of the builder, in the form '<os>/<arch>[/<variant>]', such as 'linux/arm64'.
+	// It selects the matching image from build image and run image indexes, and the lifecycle for the architecture.
+	// If unset, registries choose the image of an index.
+	Platform string
 }
 
 // CreateBuilder creates and saves a builder image to a registry with the provided options.
 // If any configuration is invalid, it will error and exit without creating any images.
 func (c *Client) CreateBuilder(ctx context.Context, opts CreateBuilderOptions) error {
-	if err := c.validateConfig(ctx, opts); err != nil {
+	platform, err := parsePlatform(opts.Platform)
+	if err != nil {
+		return errors.Wrap(err, "invalid platform")
+	}
+
+	if err := c.validateConfig(ctx, opts, platform); err != nil {
 		return err
 	}
 
-	bldr, err := c.createBaseBuilder(ctx, opts)
+	bldr, err := c.createBaseBuilder(ctx, opts, platform)
 	if err != nil {
 		return errors.Wrap(err, "failed to create builder")
 	}
@@ -62,23 +73,23 @@ func (c *Client) CreateBuilder(ctx context.Context, opts CreateBuilderOptions) e
 	return bldr.Save(c.logger, builder.CreatorMetadata{Version: Version})
 }
 
-func (c *Client) validateConfig(ctx context.Context, opts CreateBuilderOptions) error {
+func (c *Client) validateConfig(ctx context.Context, opts CreateBuilderOptions, platform *v1.Platform) error {
 	if err := pubbldr.ValidateConfig(opts.Config); err != nil {
 		return errors.Wrap(err, "invalid builder config")
 	}
 
-	if err := c.validateRunImageConfig(ctx, opts); err != nil {
+	if err := c.validateRunImageConfig(ctx, opts, platform); err != nil {
 		return errors.Wrap(err, "invalid run image config")
 	}
 
 	return nil
 }
 
-func (c *Client) validateRunImageConfig(ctx context.Context, opts CreateBuilderOptions) error {
+func (c *Client) validateRunImageConfig(ctx context.Context, opts CreateBuilderOptions, platform *v1.Platform) error {
 	var runImages []imgutil.Image
 	for _, i := range append([]string{opts.Config.Stack.RunImage}, opts.Config.Stack.RunImageMirrors...) {
 		if !opts.Publish {
-			img, err := c.imageFetcher.Fetch(ctx, i, true, opts.PullPolicy)
+			img, err := c.fetchForPlatform(ctx, i, true, opts.PullPolicy, platform)
 			if err != nil {
 				if errors.Cause(err) != image.ErrNotFound {
 					return errors.Wrap(err, "failed to fetch image")
@@ -89,7 +100,7 @@ func (c *Client) validateRunImageConfig(ctx context.Context, opts CreateBuilderO
 			}
 		}
 
-		img, err := c.imageFetcher.Fetch(ctx, i, false, opts.PullPolicy)
+		img, err := c.fetchForPlatform(ctx, i, false, opts.PullPolicy, platform)
 		if err != nil {
 			if errors.Cause(err) != image.ErrNotFound {
 				return errors.Wrap(err, "failed to fetch image")
@@ -119,8 +130,8 @@ func (c *Client) validateRunImageConfig(ctx context.Context, opts CreateBuilderO
 	return nil
 }
 
-func (c *Client) createBaseBuilder(ctx context.Context, opts CreateBuilderOptions) (*builder.Builder, error) {
-	baseImage, err := c.imageFetcher.Fetch(ctx, opts.Config.Stack.BuildImage, !opts.Publish, opts.PullPolicy)
+func (c *Client) createBaseBuilder(ctx context.Context, opts CreateBuilderOptions, platform *v1.Platform) (*builder.Builder, error) {
+	baseImage, err := c.fetchForPlatform(ctx, opts.Config.Stack.BuildImage, !opts.Publish, opts.PullPolicy, platform)
 	if err != nil {
 		return nil, errors.Wrap(err, "fetch build image")
 	}
@@ -150,7 +161,12 @@ func (c *Client) createBaseBuilder(ctx context.Context, opts CreateBuilderOption
 		)
 	}
 
-	lifecycle, err := c.fetchLifecycle(ctx, opts.Config.Lifecycle, os)
+	var arch string
+	if platform != nil {
+		arch = platform.Architecture
+	}
+
+	lifecycle, err := c.fetchLifecycle(ctx, opts.Config.Lifecycle, os, arch)
 	if err != nil {
 		return nil, errors.Wrap(err, "fetch lifecycle")
 	}
@@ -160,7 +176,7 @@ func (c *Client) createBaseBuilder(ctx context.Context, opts CreateBuilderOption
 	return bldr, nil
 }
 
-func (c *Client) fetchLifecycle(ctx context.Context, config pubbldr.LifecycleConfig, os string) (builder.Lifecycle, error) {
+func (c *Client) fetchLifecycle(ctx context.Context, config pubbldr.LifecycleConfig, os, arch string) (builder.Lifecycle, error) {
 	if config.Version != "" && config.URI != "" {
 		return nil, errors.Errorf(
 			"%s can only declare %s or %s, not both",
@@ -176,11 +192,16 @@ func (c *Client) fetchLifecycle(ctx context.Context, config pubbldr.LifecycleCon
 			return nil, errors.Wrapf(err, "%s must be a valid semver", style.Symbol("lifecycle.version"))
 		}
 
-		uri = uriFromLifecycleVersion(*v, os)
+		if uri, err = uriFromLifecycleVersion(*v, os, arch); err != nil {
+			return nil, err
+		}
 	case config.URI != "":
 		uri = config.URI
 	default:
-		uri = uriFromLifecycleVersion(*semver.MustParse(builder.DefaultLifecycleVersion), os)
+		var err error
+		if uri, err = uriFromLifecycleVersion(*semver.MustParse(builder.DefaultLifecycleVersion), os, arch); err != nil {
+			return nil, err
+		}
 	}
 
 	b, err := c.downloader.Download(ctx, uri)
@@ -325,10 +346,29 @@ func validateBuildpack(bp dist.Buildpack, source, expectedID, expectedBPVersion
 	return nil
 }
 
-func uriFromLifecycleVersion(version semver.Version, os string) string {
-	if os == "windows" {
-		return fmt.Sprintf("https://github.com/buildpacks/lifecycle/releases/download/v%s/lifecycle-v%s+windows.x86-64.tgz", version.String(), version.String())
+// lifecycleArchs maps the architectures the lifecycle is released for to the name they have in its releases.
+var lifecycleArchs = map[string]string{
+	"amd64": "x86-64",
+	"arm64": "arm64",
+}
+
+// uriFromLifecycleVersion returns the URI of the lifecycle release of version for the platform of the builder,
+// images with no architecture being taken as amd64.
+func uriFromLifecycleVersion(version semver.Version, os, arch string) (string, error) {
+	if arch == "" {
+		arch = "amd64"
 	}
 
-	return fmt.Sprintf("https://github.com/buildpacks/lifecycle/releases/download/v%s/lifecycle-v%s+linux.x86-64.tgz", version.String(), version.String())
+	archName, ok := lifecycleArchs[arch]
+	if !ok || (os == "windows" && arch != "amd64") {
+		return "", errors.Errorf(
+			"no lifecycle is released for platform %s, provide one with %s",
+			style.Symbol(os+"/"+arch), style.Symbol("lifecycle.uri"),
+		)
+	}
+
+	if os != "windows" {
+		os = "linux"
+	}
+	return fmt.Sprintf("https://github.com/buildpacks/lifecycle/releases/download/v%s/lifecycle-v%s+%s.%s.tgz", version.String(), version.String(), os, archName), nil
 }
@@ -443,6 +443,59 @@ func testCreateBuilder(t *testing.T, when spec.G, it spec.S) {
 				h.AssertNil(t, err)
 			})
 
+			when("platform is arm64", func() {
+				it("should download the arm64 lifecycle", func() {
+					prepareFetcherWithBuildImage()
+					prepareFetcherWithRunImages()
+					opts.Config.Lifecycle.URI = ""
+					opts.Config.Lifecycle.Version = "3.4.5"
+					opts.Platform = "linux/arm64"
+					opts.PullPolicy = config.PullIfNotPresent
+					h.AssertNil(t, fakeBuildImage.SetArchitecture("arm64"))
+					h.AssertNil(t, fakeRunImage.SetArchitecture("arm64"))
+					h.AssertNil(t, fakeRunImageMirror.SetArchitecture("arm64"))
+
+					mockDownloader.EXPECT().Download(
+						gomock.Any(),
+						"https://github.com/buildpacks/lifecycle/releases/download/v3.4.5/lifecycle-v3.4.5+linux.arm64.tgz",
+					).Return(
+						blob.NewBlob(filepath.Join("testdata", "lifecycle", "platform-0.4")), nil,
+					)
+
+					err := subject.CreateBuilder(context.TODO(), opts)
+					h.AssertNil(t, err)
+				})
+
+				it("errors when the local build image is for another platform", func() {
+					prepareFetcherWithBuildImage()
+					prepareFetcherWithRunImages()
+					opts.Platform = "linux/arm64"
+					opts.PullPolicy = config.PullNever
+					h.AssertNil(t, fakeRunImage.SetArchitecture("arm64"))
+					h.AssertNil(t, fakeRunImageMirror.SetArchitecture("arm64"))
+
+					err := subject.CreateBuilder(context.TODO(), opts)
+					h.AssertError(t, err, "image 'some/build-image' is for platform 'linux/amd64', not 'linux/arm64'")
+				})
+			})
+
+			when("platform has no lifecycle release", func() {
+				it("errors rather than downloading the lifecycle of another architecture", func() {
+					prepareFetcherWithBuildImage()
+					prepareFetcherWithRunImages()
+					opts.Config.Lifecycle.URI = ""
+					opts.Config.Lifecycle.Version = "3.4.5"
+					opts.Platform = "linux/ppc64le"
+					opts.PullPolicy = config.PullIfNotPresent
+					h.AssertNil(t, fakeBuildImage.SetArchitecture("ppc64le"))
+					h.AssertNil(t, fakeRunImage.SetArchitecture("ppc64le"))
+					h.AssertNil(t, fakeRunImageMirror.SetArchitecture("ppc64le"))
+
+					err := subject.CreateBuilder(context.TODO(), opts)
+					h.AssertError(t, err, "no lifecycle is released for platform 'linux/ppc64le', provide one with 'lifecycle.uri'")
+				})
+			})
+
 			when("windows", func() {
 				it("should download from predetermined uri", func() {
 					packClientWithExperimental, err := pack.NewClient(
@@ -0,0 +1,159 @@
+package pack
+
+import (
+	"context"
+	"strings"
+
+	"github.com/buildpacks/lifecycle"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/config"
+	"github.com/buildpacks/pack/internal/build"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// DetectOptions defines configuration settings for Detect.
+type DetectOptions struct {
+	// required. Builder image name.
+	Builder string
+
+	// Name of the buildpack registry. Used to
+	// add buildpacks to the detection.
+	Registry string
+
+	// Path to the application directory.
+	// If unset it defaults to current working directory.
+	AppPath string
+
+	// User provided environment variables to the buildpacks.
+	Env map[string]string
+
+	// List of buildpack images or archives to add to the builder,
+	// replacing the order of the builder, as for a build.
+	Buildpacks []string
+
+	// Buildpacks synthesized from scripts and added to the builder, for the stack of the builder.
+	// Each is used where Buildpacks names it by '<id>' or '<id>@<version>'.
+	InlineBuildpacks []InlineBuildpack
+
+	// Filter files from the application source.
+	// If true include file, otherwise exclude.
+	FileFilter func(string) bool
+
+	// Strategy for updating local images before detection.
+	PullPolicy config.PullPolicy
+
+	// Network the detector container is connected to.
+	Network string
+}
+
+// DetectedBuildpack is a buildpack of a group tried during detection.
+type DetectedBuildpack struct {
+	ID      string `json:"id"`
+	Version string `json:"version,omitempty"`
+
+	// Status of the buildpack: 'pass', 'fail', 'skip' for an optional buildpack that failed, or 'error'.
+	Status string `json:"status"`
+}
+
+// DetectedGroup is a group of buildpacks tried during detection.
+type DetectedGroup struct {
+	Buildpacks []DetectedBuildpack `json:"buildpacks"`
+}
+
+// DetectResult is the outcome of Detect.
+type DetectResult struct {
+	// Buildpacks of the group selected for a build, in order.
+	// Empty if no group passed detection.
+	Group []lifecycle.GroupBuildpack `json:"group"`
+
+	// Groups tried during detection, in order, until one passed.
+	Groups []DetectedGroup `json:"groups"`
+}
+
+// Detect runs only the detection of a build of an app, reporting the group of buildpacks selected for it
+// and the results of each group tried. When no group passes detection, it returns the results along with the error.
+func (c *Client) Detect(ctx context.Context, opts DetectOptions) (*DetectResult, error) {
+	appPath, err := c.processAppPath(opts.AppPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid app path '%s'", opts.AppPath)
+	}
+
+	fileFilter := opts.FileFilter
+	if fileFilter == nil {
+		if fileFilter, err = projectFileFilter(appPath, c.logger); err != nil {
+			return nil, err
+		}
+	}
+
+	builderRef, err := c.processBuilderName(opts.Builder)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid builder '%s'", opts.Builder)
+	}
+
+	rawBuilderImage, err := c.imageFetcher.Fetch(ctx, builderRef.Name(), true, opts.PullPolicy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch builder image '%s'", builderRef.Name())
+	}
+
+	bldr, err := c.getBuilder(rawBuilderImage)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid builder %s", style.Symbol(opts.Builder))
+	}
+
+	inlineBPs, err := createInlineBuildpacks(opts.InlineBuildpacks, bldr.Image(), bldr.StackID)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchedBPs, order, err := c.processBuildpacks(ctx, bldr.Image(), bldr.Buildpacks(), bldr.Order(), opts.Buildpacks, inlineBPs, opts.PullPolicy, false, opts.Registry)
+	if err != nil {
+		return nil, err
+	}
+
+	ephemeralBuilder, _, err := c.ephemeralBuilder(ctx, rawBuilderImage, opts.Env, order, fetchedBPs)
+	if err != nil {
+		return nil, err
+	}
+	defer c.releaseEphemeralBuilder(context.Background(), ephemeralBuilder.Name(), opts.Env)
+
+	builderPlatformAPIs := append(
+		ephemeralBuilder.LifecycleDescriptor().APIs.Platform.Deprecated,
+		ephemeralBuilder.LifecycleDescriptor().APIs.Platform.Supported...,
+	)
+
+	if !supportsPlatformAPI(builderPlatformAPIs) {
+		c.logger.Debugf("pack %s supports Platform API(s): %s", Version, strings.Join(build.SupportedPlatformAPIVersions.AsStrings(), ", "))
+		c.logger.Debugf("Builder %s supports Platform API(s): %s", style.Symbol(opts.Builder), strings.Join(builderPlatformAPIs.AsStrings(), ", "))
+		return nil, errors.Errorf("Builder %s is incompatible with this version of pack", style.Symbol(opts.Builder))
+	}
+
+	detectResult, err := c.lifecycleExecutor.Detect(ctx, build.LifecycleOptions{
+		AppPath:        appPath,
+		Builder:        ephemeralBuilder,
+		LifecycleImage: ephemeralBuilder.Name(),
+		Network:        opts.Network,
+		FileFilter:     fileFilter,
+	})
+
+	result := &DetectResult{
+		Group:  detectResult.Group,
+		Groups: []DetectedGroup{},
+	}
+	for _, group := range detectResult.Groups {
+		detectedGroup := DetectedGroup{Buildpacks: []DetectedBuildpack{}}
+		for _, bp := range group.Buildpacks {
+			detectedGroup.Buildpacks = append(detectedGroup.Buildpacks, DetectedBuildpack{
+				ID:      bp.ID,
+				Version: bp.Version,
+				Status:  string(bp.Status),
+			})
+		}
+		result.Groups = append(result.Groups, detectedGroup)
+	}
+
+	if err != nil {
+		return result, errors.Wrap(err, "detecting buildpack group")
+	}
+	return result, nil
+}
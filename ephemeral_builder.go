@@ -0,0 +1,325 @@
+package pack
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	dockerClient "github.com/docker/docker/client"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/config"
+	"github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/internal/cache"
+	cfg "github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/dist"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+const (
+	// The number of ephemeral builders kept in the docker daemon for reuse by later builds.
+	maxEphemeralBuilders = 5
+
+	// How long after its last use an ephemeral builder may still be used by a build of another process,
+	// during which it is not evicted.
+	ephemeralBuilderGracePeriod = 24 * time.Hour
+)
+
+// ephemeralBuilder returns a builder with env, order and buildpacks added to the raw builder image,
+// and whether it was saved by a previous build with the same content.
+// The builder is in use until released with releaseEphemeralBuilder.
+func (c *Client) ephemeralBuilder(ctx context.Context, rawBuilderImage imgutil.Image, env map[string]string, order dist.Order, buildpacks []dist.Buildpack) (*builder.Builder, bool, error) {
+	builderName, err := ephemeralBuilderName(rawBuilderImage, env, order, buildpacks)
+	if err != nil {
+		return nil, false, err
+	}
+	c.useEphemeralBuilder(builderName)
+
+	if c.ephemeralBuilderExists(ctx, builderName) {
+		if img, err := c.imageFetcher.Fetch(ctx, builderName, true, config.PullNever); err == nil {
+			if bldr, err := builder.FromImage(img); err == nil {
+				c.logger.Debugf("Reusing ephemeral builder %s", style.Symbol(builderName))
+				return bldr, true, nil
+			}
+		}
+	}
+
+	bldr, err := c.createEphemeralBuilder(rawBuilderImage, builderName, env, order, buildpacks)
+	if err != nil {
+		c.releaseEphemeralBuilder(ctx, builderName, nil)
+		return nil, false, err
+	}
+	return bldr, false, nil
+}
+
+// ephemeralBuilderExists returns whether an ephemeral builder was saved to the daemon by a previous build.
+func (c *Client) ephemeralBuilderExists(ctx context.Context, builderName string) bool {
+	if _, _, err := c.docker.ImageInspectWithRaw(ctx, builderName); err != nil {
+		if !dockerClient.IsErrNotFound(err) {
+			c.logger.Debugf("Unable to inspect ephemeral builder %s: %s", style.Symbol(builderName), err)
+		}
+		return false
+	}
+	return true
+}
+
+// builderUsage counts the builds using each ephemeral builder.
+// It is shared by the copies of a client, such as those of build-all, so that none removes a builder another one uses.
+type builderUsage struct {
+	sync.Mutex
+	counts map[string]int
+
+	// when the use of each builder in use was last recorded by this client
+	recorded map[string]time.Time
+}
+
+func newBuilderUsage() *builderUsage {
+	return &builderUsage{counts: map[string]int{}, recorded: map[string]time.Time{}}
+}
+
+// useEphemeralBuilder notes that a build uses an ephemeral builder, on disk for builds of other processes.
+func (c *Client) useEphemeralBuilder(builderName string) {
+	if c.buildersInUse == nil {
+		c.recordBuilderUse(builderName)
+		return
+	}
+
+	c.buildersInUse.Lock()
+	defer c.buildersInUse.Unlock()
+	c.buildersInUse.counts[builderName]++
+	c.buildersInUse.recorded[builderName] = c.recordBuilderUse(builderName)
+}
+
+// releaseEphemeralBuilder notes that a build no longer uses an ephemeral builder, and evicts builders beyond the limit.
+// A builder with env is removed once no build uses it, as the env values, often credentials, are stored in its layers.
+// Builds of other processes are taken to use it if they recorded a use of it since this client last did.
+func (c *Client) releaseEphemeralBuilder(ctx context.Context, builderName string, env map[string]string) {
+	if c.buildersInUse != nil {
+		c.buildersInUse.Lock()
+		c.buildersInUse.counts[builderName]--
+		if c.buildersInUse.counts[builderName] <= 0 {
+			recorded := c.buildersInUse.recorded[builderName]
+			delete(c.buildersInUse.counts, builderName)
+			delete(c.buildersInUse.recorded, builderName)
+
+			if len(env) > 0 && !c.builderUsedSince(builderName, recorded) {
+				c.removeEphemeralBuilder(ctx, builderName)
+			} else {
+				// the grace period of the builder starts at the end of the build
+				c.recordBuilderUse(builderName)
+			}
+		}
+		c.buildersInUse.Unlock()
+	}
+
+	c.evictEphemeralBuilders(ctx)
+}
+
+// removeEphemeralBuilder removes an ephemeral builder, unless containers still use it.
+// Failing to do so is not fatal to the build.
+func (c *Client) removeEphemeralBuilder(ctx context.Context, builderName string) {
+	if _, err := c.docker.ImageRemove(ctx, builderName, types.ImageRemoveOptions{PruneChildren: true}); err != nil {
+		c.logger.Debugf("Unable to remove ephemeral builder %s: %s", style.Symbol(builderName), err)
+		return
+	}
+
+	c.logger.Debugf("Removed ephemeral builder %s", style.Symbol(builderName))
+	c.forgetBuilderUse(builderName)
+}
+
+// ephemeralBuilderName returns the name of the ephemeral builder created from the raw builder image,
+// derived from everything that makes up its content.
+func ephemeralBuilderName(rawBuilderImage imgutil.Image, env map[string]string, order dist.Order, buildpacks []dist.Buildpack) (string, error) {
+	identifier, err := rawBuilderImage.Identifier()
+	if err != nil {
+		return "", errors.Wrapf(err, "getting identifier of builder %s", style.Symbol(rawBuilderImage.Name()))
+	}
+
+	content := struct {
+		Builder    string            `json:"builder"`
+		Pack       string            `json:"pack"`
+		Env        map[string]string `json:"env"`
+		Order      dist.Order        `json:"order"`
+		Buildpacks []string          `json:"buildpacks"`
+	}{
+		Builder: rawBuilderImage.Name(),
+		Pack:    Version,
+		Env:     env,
+	}
+
+	if identifier != nil {
+		content.Builder = identifier.String()
+	}
+
+	// the default order only has an empty group, and leaves the order of the builder in place
+	if len(order) > 0 && len(order[0].Group) > 0 {
+		content.Order = order
+	}
+
+	for _, bp := range buildpacks {
+		digest, err := buildpackBlobDigest(bp)
+		if err != nil {
+			bpInfo := bp.Descriptor().Info
+			return "", errors.Wrapf(err, "reading buildpack %s", style.Symbol(bpInfo.FullName()))
+		}
+		content.Buildpacks = append(content.Buildpacks, digest)
+	}
+
+	contentJSON, err := json.Marshal(content)
+	if err != nil {
+		return "", errors.Wrap(err, "marshalling ephemeral builder content")
+	}
+	return fmt.Sprintf("%s/%x:latest", ephemeralBuilderRepo, sha256.Sum256(contentJSON)), nil
+}
+
+// buildpackBlobDigest returns the sha256 digest of the blob of a buildpack.
+func buildpackBlobDigest(bp dist.Buildpack) (string, error) {
+	rc, err := bp.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, rc); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256:%x", hash.Sum(nil)), nil
+}
+
+// evictEphemeralBuilders removes the least recently used ephemeral builders,
+// keeping at most maxEphemeralBuilders of them, and any in use by builds of this client.
+// Failing to do so is not fatal to the build.
+func (c *Client) evictEphemeralBuilders(ctx context.Context) {
+	// builds wait for the eviction to end before using a builder, so that it is not removed from under them
+	if c.buildersInUse != nil {
+		c.buildersInUse.Lock()
+		defer c.buildersInUse.Unlock()
+	}
+
+	usageDir, err := builderUsageDir()
+	if err != nil {
+		c.logger.Debugf("Unable to evict ephemeral builders: %s", err)
+		return
+	}
+
+	images, err := c.docker.ImageList(ctx, types.ImageListOptions{
+		Filters: filters.NewArgs(filters.Arg("reference", ephemeralBuilderRepo+"/*")),
+	})
+	if err != nil {
+		c.logger.Debugf("Unable to list ephemeral builders: %s", err)
+		return
+	}
+
+	type usedBuilder struct {
+		name     string
+		lastUsed time.Time
+	}
+
+	var builders []usedBuilder
+	for _, img := range images {
+		for _, tag := range img.RepoTags {
+			if !strings.HasPrefix(tag, ephemeralBuilderRepo+"/") {
+				continue
+			}
+
+			lastUsed, err := cache.LastUse(usageDir, builderUsageKey(tag))
+			if err != nil || lastUsed.IsZero() {
+				// builders saved by older versions of pack have no recorded use
+				lastUsed = time.Unix(img.Created, 0)
+			}
+			builders = append(builders, usedBuilder{name: tag, lastUsed: lastUsed})
+		}
+	}
+
+	if len(builders) <= maxEphemeralBuilders {
+		return
+	}
+
+	sort.Slice(builders, func(i, j int) bool {
+		return builders[i].lastUsed.After(builders[j].lastUsed)
+	})
+
+	gracePeriodStart := time.Now().Add(-ephemeralBuilderGracePeriod)
+	for _, bldr := range builders[maxEphemeralBuilders:] {
+		if c.buildersInUse != nil && c.buildersInUse.counts[bldr.name] > 0 {
+			continue
+		}
+
+		// builds of other processes may be using builders used recently, between two phases, when no container holds them
+		if bldr.lastUsed.After(gracePeriodStart) {
+			continue
+		}
+		c.removeEphemeralBuilder(ctx, bldr.name)
+	}
+}
+
+// recordBuilderUse notes that an ephemeral builder is used by a build, and returns the time of use recorded.
+// Failing to do so is not fatal to the build.
+func (c *Client) recordBuilderUse(builderName string) time.Time {
+	usageDir, err := builderUsageDir()
+	if err == nil {
+		err = cache.RecordUse(usageDir, builderUsageKey(builderName))
+	}
+
+	var recorded time.Time
+	if err == nil {
+		recorded, err = cache.LastUse(usageDir, builderUsageKey(builderName))
+	}
+
+	if err != nil {
+		c.logger.Debugf("Unable to record builder use: %s", err)
+	}
+	return recorded
+}
+
+// builderUsedSince returns whether a use of an ephemeral builder was recorded after the time given,
+// as by a build of another process. An unknown use is taken as recent.
+func (c *Client) builderUsedSince(builderName string, since time.Time) bool {
+	if since.IsZero() {
+		return true
+	}
+
+	usageDir, err := builderUsageDir()
+	if err != nil {
+		return true
+	}
+
+	lastUsed, err := cache.LastUse(usageDir, builderUsageKey(builderName))
+	return err != nil || lastUsed.After(since)
+}
+
+func (c *Client) forgetBuilderUse(builderName string) {
+	usageDir, err := builderUsageDir()
+	if err == nil {
+		err = cache.ForgetUse(usageDir, builderUsageKey(builderName))
+	}
+
+	if err != nil {
+		c.logger.Debugf("Unable to forget builder use: %s", err)
+	}
+}
+
+// builderUsageKey returns the content hash of an ephemeral builder name, which is safe to use as a file name.
+func builderUsageKey(builderName string) string {
+	key := strings.TrimPrefix(builderName, ephemeralBuilderRepo+"/")
+	return strings.SplitN(key, ":", 2)[0]
+}
+
+func builderUsageDir() (string, error) {
+	home, err := cfg.PackHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "builder-usage"), nil
+}
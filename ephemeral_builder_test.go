@@ -0,0 +1,274 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/buildpacks/imgutil/fakes"
+	"github.com/buildpacks/imgutil/local"
+	"github.com/buildpacks/lifecycle/api"
+	"github.com/docker/docker/api/types"
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/cache"
+	"github.com/buildpacks/pack/internal/dist"
+	ifakes "github.com/buildpacks/pack/internal/fakes"
+	"github.com/buildpacks/pack/internal/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+	"github.com/buildpacks/pack/testmocks"
+)
+
+func TestEphemeralBuilder(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "EphemeralBuilder", testEphemeralBuilder, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+func testEphemeralBuilder(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		mockDockerClient *testmocks.MockCommonAPIClient
+		mockController   *gomock.Controller
+		out              bytes.Buffer
+		packHome         string
+	)
+
+	it.Before(func() {
+		var err error
+		packHome, err = ioutil.TempDir("", "ephemeral-builder-test")
+		h.AssertNil(t, err)
+		h.AssertNil(t, os.Setenv("PACK_HOME", packHome))
+
+		mockController = gomock.NewController(t)
+		mockDockerClient = testmocks.NewMockCommonAPIClient(mockController)
+
+		subject, err = NewClient(WithLogger(logging.NewLogWithWriters(&out, &out)), WithDockerClient(mockDockerClient))
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+		os.Unsetenv("PACK_HOME")
+		h.AssertNil(t, os.RemoveAll(packHome))
+	})
+
+	when("#ephemeralBuilderName", func() {
+		var (
+			rawBuilderImage *fakes.Image
+			bp              dist.Buildpack
+			order           dist.Order
+		)
+
+		it.Before(func() {
+			var err error
+			rawBuilderImage = fakes.NewImage("some/builder", "", local.IDIdentifier{ImageID: "some-image-id"})
+
+			bp, err = ifakes.NewFakeBuildpack(dist.BuildpackDescriptor{
+				API:    api.MustParse("0.3"),
+				Info:   dist.BuildpackInfo{ID: "some-bp", Version: "1.2.3"},
+				Stacks: []dist.Stack{{ID: "some.stack.id"}},
+			}, 0644)
+			h.AssertNil(t, err)
+
+			order = dist.Order{{Group: []dist.BuildpackRef{{BuildpackInfo: dist.BuildpackInfo{ID: "some-bp", Version: "1.2.3"}}}}}
+		})
+
+		it("is stable for the same content", func() {
+			first, err := ephemeralBuilderName(rawBuilderImage, map[string]string{"A": "1", "B": "2"}, order, []dist.Buildpack{bp})
+			h.AssertNil(t, err)
+			second, err := ephemeralBuilderName(rawBuilderImage, map[string]string{"B": "2", "A": "1"}, order, []dist.Buildpack{bp})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, first, second)
+			h.AssertContains(t, first, "pack.local/builder/")
+		})
+
+		it("changes with the base builder", func() {
+			first, err := ephemeralBuilderName(rawBuilderImage, nil, nil, nil)
+			h.AssertNil(t, err)
+			second, err := ephemeralBuilderName(fakes.NewImage("some/builder", "", local.IDIdentifier{ImageID: "other-image-id"}), nil, nil, nil)
+			h.AssertNil(t, err)
+
+			h.AssertNotEq(t, first, second)
+		})
+
+		it("changes with the env", func() {
+			first, err := ephemeralBuilderName(rawBuilderImage, map[string]string{"A": "1"}, nil, nil)
+			h.AssertNil(t, err)
+			second, err := ephemeralBuilderName(rawBuilderImage, map[string]string{"A": "2"}, nil, nil)
+			h.AssertNil(t, err)
+
+			h.AssertNotEq(t, first, second)
+		})
+
+		it("changes with the order", func() {
+			first, err := ephemeralBuilderName(rawBuilderImage, nil, nil, nil)
+			h.AssertNil(t, err)
+			second, err := ephemeralBuilderName(rawBuilderImage, nil, order, nil)
+			h.AssertNil(t, err)
+
+			h.AssertNotEq(t, first, second)
+		})
+
+		it("changes with the added buildpacks", func() {
+			first, err := ephemeralBuilderName(rawBuilderImage, nil, nil, nil)
+			h.AssertNil(t, err)
+			second, err := ephemeralBuilderName(rawBuilderImage, nil, nil, []dist.Buildpack{bp})
+			h.AssertNil(t, err)
+
+			h.AssertNotEq(t, first, second)
+		})
+	})
+
+	when("#evictEphemeralBuilders", func() {
+		var usageDir string
+
+		builderNamed := func(key string) string {
+			return "pack.local/builder/" + key + ":latest"
+		}
+
+		useBuilder := func(key string, at time.Time) {
+			h.AssertNil(t, cache.RecordUse(usageDir, key))
+			h.AssertNil(t, os.Chtimes(filepath.Join(usageDir, key), at, at))
+		}
+
+		it.Before(func() {
+			usageDir = filepath.Join(packHome, "builder-usage")
+		})
+
+		it("removes the least recently used builders beyond the limit", func() {
+			now := time.Now()
+			var images []types.ImageSummary
+			for i, key := range []string{"a", "b", "c", "d", "e", "f"} {
+				useBuilder(key, now.Add(-ephemeralBuilderGracePeriod-time.Duration(i+1)*time.Hour))
+				images = append(images, types.ImageSummary{RepoTags: []string{builderNamed(key)}})
+			}
+			// no recorded use, so the oldest
+			images = append(images, types.ImageSummary{RepoTags: []string{builderNamed("g")}, Created: now.Add(-48 * time.Hour).Unix()})
+
+			mockDockerClient.EXPECT().ImageList(gomock.Any(), gomock.Any()).Return(images, nil)
+			mockDockerClient.EXPECT().ImageRemove(gomock.Any(), builderNamed("f"), types.ImageRemoveOptions{PruneChildren: true}).Return(nil, nil)
+			mockDockerClient.EXPECT().ImageRemove(gomock.Any(), builderNamed("g"), types.ImageRemoveOptions{PruneChildren: true}).Return(nil, nil)
+
+			subject.evictEphemeralBuilders(context.TODO())
+
+			lastUse, err := cache.LastUse(usageDir, "f")
+			h.AssertNil(t, err)
+			h.AssertEq(t, lastUse.IsZero(), true)
+		})
+
+		it("keeps builders in use beyond the limit", func() {
+			now := time.Now()
+			var images []types.ImageSummary
+			for i, key := range []string{"a", "b", "c", "d", "e", "f"} {
+				useBuilder(key, now.Add(-ephemeralBuilderGracePeriod-time.Duration(i+1)*time.Hour))
+				images = append(images, types.ImageSummary{RepoTags: []string{builderNamed(key)}})
+			}
+			subject.useEphemeralBuilder(builderNamed("f"))
+			useBuilder("f", now.Add(-48*time.Hour))
+
+			mockDockerClient.EXPECT().ImageList(gomock.Any(), gomock.Any()).Return(images, nil)
+
+			subject.evictEphemeralBuilders(context.TODO())
+		})
+
+		it("keeps builders in use by copies of the client, as those of build-all", func() {
+			now := time.Now()
+			var images []types.ImageSummary
+			for i, key := range []string{"a", "b", "c", "d", "e", "f"} {
+				useBuilder(key, now.Add(-ephemeralBuilderGracePeriod-time.Duration(i+1)*time.Hour))
+				images = append(images, types.ImageSummary{RepoTags: []string{builderNamed(key)}})
+			}
+			subject.withLogger(logging.NewLogWithWriters(&out, &out)).useEphemeralBuilder(builderNamed("f"))
+			useBuilder("f", now.Add(-48*time.Hour))
+
+			mockDockerClient.EXPECT().ImageList(gomock.Any(), gomock.Any()).Return(images, nil)
+
+			subject.evictEphemeralBuilders(context.TODO())
+		})
+
+		it("keeps builders used within the grace period beyond the limit, as builds of other processes may use them", func() {
+			now := time.Now()
+			var images []types.ImageSummary
+			for i, key := range []string{"a", "b", "c", "d", "e", "f"} {
+				useBuilder(key, now.Add(-time.Duration(i)*time.Hour))
+				images = append(images, types.ImageSummary{RepoTags: []string{builderNamed(key)}})
+			}
+
+			mockDockerClient.EXPECT().ImageList(gomock.Any(), gomock.Any()).Return(images, nil)
+
+			subject.evictEphemeralBuilders(context.TODO())
+		})
+
+		it("keeps builders within the limit", func() {
+			mockDockerClient.EXPECT().ImageList(gomock.Any(), gomock.Any()).Return([]types.ImageSummary{
+				{RepoTags: []string{builderNamed("a")}},
+				{RepoTags: []string{builderNamed("b")}},
+			}, nil)
+
+			subject.evictEphemeralBuilders(context.TODO())
+		})
+
+		it("keeps the use of builders that cannot be removed", func() {
+			now := time.Now()
+			var images []types.ImageSummary
+			for i, key := range []string{"a", "b", "c", "d", "e", "f"} {
+				useBuilder(key, now.Add(-ephemeralBuilderGracePeriod-time.Duration(i+1)*time.Hour))
+				images = append(images, types.ImageSummary{RepoTags: []string{builderNamed(key)}})
+			}
+
+			mockDockerClient.EXPECT().ImageList(gomock.Any(), gomock.Any()).Return(images, nil)
+			mockDockerClient.EXPECT().ImageRemove(gomock.Any(), builderNamed("f"), gomock.Any()).Return(nil, errors.New("image is in use"))
+
+			subject.evictEphemeralBuilders(context.TODO())
+
+			lastUse, err := cache.LastUse(usageDir, "f")
+			h.AssertNil(t, err)
+			h.AssertEq(t, lastUse.IsZero(), false)
+		})
+	})
+
+	when("#releaseEphemeralBuilder", func() {
+		builderName := "pack.local/builder/some-key:latest"
+
+		it.Before(func() {
+			mockDockerClient.EXPECT().ImageList(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+		})
+
+		it("keeps a builder without env for reuse", func() {
+			subject.useEphemeralBuilder(builderName)
+
+			subject.releaseEphemeralBuilder(context.TODO(), builderName, nil)
+		})
+
+		it("removes a builder with env once no build uses it", func() {
+			env := map[string]string{"SOME_TOKEN": "some-value"}
+			subject.useEphemeralBuilder(builderName)
+			subject.useEphemeralBuilder(builderName)
+
+			subject.releaseEphemeralBuilder(context.TODO(), builderName, env)
+
+			mockDockerClient.EXPECT().ImageRemove(gomock.Any(), builderName, types.ImageRemoveOptions{PruneChildren: true}).Return(nil, nil)
+			subject.releaseEphemeralBuilder(context.TODO(), builderName, env)
+		})
+
+		it("keeps a builder with env that a build of another process used since", func() {
+			env := map[string]string{"SOME_TOKEN": "some-value"}
+			subject.useEphemeralBuilder(builderName)
+
+			later := time.Now().Add(time.Minute)
+			h.AssertNil(t, os.Chtimes(filepath.Join(packHome, "builder-usage", "some-key"), later, later))
+
+			subject.releaseEphemeralBuilder(context.TODO(), builderName, env)
+		})
+	})
+}
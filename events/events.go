@@ -0,0 +1,122 @@
+// Package events defines the typed events reported while building an app image.
+//
+// Library consumers can receive these events by setting BuildOptions.EventHandler,
+// for instance to display build progress without parsing log output.
+package events
+
+import "time"
+
+// Event is implemented by every event reported during a build.
+type Event interface {
+	// Kind is a short, stable name for the type of the event.
+	Kind() string
+}
+
+// Handler receives events as they happen.
+// Handlers are called synchronously from the build, and should return quickly.
+// Log line events may be reported from multiple goroutines.
+type Handler func(Event)
+
+// Stream identifies the output stream of a phase container.
+type Stream string
+
+const (
+	Stdout Stream = "stdout"
+	Stderr Stream = "stderr"
+)
+
+// ImageFetchStarted is reported before an image is fetched.
+type ImageFetchStarted struct {
+	// Name of the image being fetched.
+	Image string
+}
+
+func (ImageFetchStarted) Kind() string { return "image-fetch-started" }
+
+// ImageFetchFinished is reported after an image fetch completes, whether or not it succeeded.
+type ImageFetchFinished struct {
+	// Name of the image that was fetched.
+	Image string
+
+	// Err is the reason the fetch failed, or nil on success.
+	Err error
+}
+
+func (ImageFetchFinished) Kind() string { return "image-fetch-finished" }
+
+// EphemeralBuilderCreated is reported once the builder used for the build has been created,
+// or found among the builders saved by previous builds.
+type EphemeralBuilderCreated struct {
+	// Name of the ephemeral builder image.
+	Name string
+
+	// Name of the builder it was created from.
+	Builder string
+
+	// Whether the ephemeral builder was saved by a previous build with the same content.
+	Reused bool
+}
+
+func (EphemeralBuilderCreated) Kind() string { return "ephemeral-builder-created" }
+
+// PhaseStarted is reported when a lifecycle phase starts.
+type PhaseStarted struct {
+	// Name of the phase, e.g. 'detector' or 'creator'.
+	Phase string
+}
+
+func (PhaseStarted) Kind() string { return "phase-started" }
+
+// PhaseFinished is reported when a lifecycle phase ends, whether or not it succeeded.
+type PhaseFinished struct {
+	// Name of the phase, e.g. 'detector' or 'creator'.
+	Phase string
+
+	// Exit code of the phase container.
+	// It is -1 if the phase failed without the container exiting.
+	ExitCode int
+
+	// Time taken by the phase.
+	Duration time.Duration
+
+	// Err is the reason the phase failed, or nil on success.
+	Err error
+}
+
+func (PhaseFinished) Kind() string { return "phase-finished" }
+
+// ContainerCreated is reported when the container for a phase has been created.
+type ContainerCreated struct {
+	// Name of the phase the container runs.
+	Phase string
+
+	// ID of the container.
+	ContainerID string
+}
+
+func (ContainerCreated) Kind() string { return "container-created" }
+
+// ContainerRemoved is reported when the container for a phase has been removed.
+type ContainerRemoved struct {
+	// Name of the phase the container ran.
+	Phase string
+
+	// ID of the container.
+	ContainerID string
+}
+
+func (ContainerRemoved) Kind() string { return "container-removed" }
+
+// PhaseLog is reported for each line of output written by a phase container.
+type PhaseLog struct {
+	// Name of the phase that wrote the line.
+	Phase string
+
+	// Stream the line was written to.
+	Stream Stream
+
+	// The line, without a trailing newline.
+	Line string
+}
+
+func (PhaseLog) Kind() string { return "phase-log" }
@@ -0,0 +1,112 @@
+package pack
+
+import (
+	"context"
+
+	"github.com/buildpacks/lifecycle"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/config"
+	"github.com/buildpacks/pack/internal/dist"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// ImageOutdatedOptions configures ImageOutdated.
+type ImageOutdatedOptions struct {
+	// Name of the app image to check.
+	RepoName string
+
+	// Whether the app image and run image are in a registry, rather than the docker daemon.
+	Publish bool
+
+	// Strategy for pulling images.
+	PullPolicy config.PullPolicy
+
+	// Run image to compare the app image against.
+	// If unset, the run image is resolved from the app image and AdditionalMirrors, as Rebase does.
+	RunImage string
+
+	// Mirrors of run images, from the pack config.
+	AdditionalMirrors map[string][]string
+}
+
+// ImageOutdatedReport compares the run image an app image was built on with the current run image.
+type ImageOutdatedReport struct {
+	// Name of the app image.
+	Image string `json:"image"`
+
+	// Name of the current run image.
+	RunImage string `json:"runImage"`
+
+	// Run image the app image is based on.
+	CurrentBase RebaseBase `json:"currentBase"`
+
+	// Current run image, which a rebase would base the app image on.
+	LatestBase RebaseBase `json:"latestBase"`
+
+	// Whether the current run image differs from the run image the app image is based on.
+	Outdated bool `json:"outdated"`
+}
+
+// ImageOutdated reports whether the run image an app image was built on has been updated since,
+// by comparing the top layer of the current run image with the run image top layer recorded on the app image.
+// The current run image is the one for the platform of the app image, when the run image is a multi-platform index.
+func (c *Client) ImageOutdated(ctx context.Context, opts ImageOutdatedOptions) (*ImageOutdatedReport, error) {
+	imageRef, err := c.parseTagReference(opts.RepoName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid image name '%s'", opts.RepoName)
+	}
+
+	appImage, err := c.imageFetcher.Fetch(ctx, opts.RepoName, !opts.Publish, opts.PullPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	var md lifecycle.LayersMetadataCompat
+	if ok, err := dist.GetLabel(appImage, lifecycle.LayerMetadataLabel, &md); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, errors.Errorf("could not find label %s on image", style.Symbol(lifecycle.LayerMetadataLabel))
+	}
+
+	runImageName := c.resolveAppRunImage(imageRef, md, opts.RunImage, opts.AdditionalMirrors, opts.Publish)
+	if runImageName == "" {
+		return nil, errors.New("run image must be specified")
+	}
+
+	// the run image of another platform, such as the default one of an index, would always differ
+	platform, err := imagePlatform(appImage)
+	if err != nil {
+		return nil, err
+	}
+
+	runImage, err := c.fetchForPlatform(ctx, runImageName, !opts.Publish, opts.PullPolicy, platform)
+	if err != nil {
+		return nil, err
+	}
+
+	topLayer, err := runImage.TopLayer()
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading top layer of %s", style.Symbol(runImageName))
+	}
+	identifier, err := runImage.Identifier()
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading identifier of %s", style.Symbol(runImageName))
+	}
+
+	return &ImageOutdatedReport{
+		Image:    appImage.Name(),
+		RunImage: runImageName,
+		CurrentBase: RebaseBase{
+			TopLayer:  md.RunImage.TopLayer,
+			Reference: md.RunImage.Reference,
+			Digest:    referenceDigest(md.RunImage.Reference),
+		},
+		LatestBase: RebaseBase{
+			TopLayer:  topLayer,
+			Reference: identifier.String(),
+			Digest:    referenceDigest(identifier.String()),
+		},
+		Outdated: topLayer != md.RunImage.TopLayer,
+	}, nil
+}
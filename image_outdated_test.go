@@ -0,0 +1,232 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/buildpacks/imgutil/fakes"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/config"
+	ifakes "github.com/buildpacks/pack/internal/fakes"
+	"github.com/buildpacks/pack/internal/image"
+	"github.com/buildpacks/pack/internal/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestImageOutdated(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ImageOutdated", testImageOutdated, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testImageOutdated(t *testing.T, when spec.G, it spec.S) {
+	when("#ImageOutdated", func() {
+		var (
+			fakeImageFetcher   *ifakes.FakeImageFetcher
+			fakeAppImage       *fakes.Image
+			fakeRunImage       *fakes.Image
+			fakeRunImageMirror *fakes.Image
+			out                bytes.Buffer
+			subject            *Client
+		)
+
+		it.Before(func() {
+			fakeImageFetcher = ifakes.NewFakeImageFetcher()
+
+			fakeAppImage = fakes.NewImage("some/app", "", &fakeIdentifier{name: "app-image"})
+			h.AssertNil(t, fakeAppImage.SetLabel("io.buildpacks.lifecycle.metadata",
+				`{"runImage":{"topLayer":"old-top-layer","reference":"some/run@sha256:old-digest"},"stack":{"runImage":{"image":"some/run","mirrors":["example.com/some/run"]}}}`))
+			fakeImageFetcher.LocalImages["some/app"] = fakeAppImage
+
+			fakeRunImage = fakes.NewImage("some/run", "run-image-top-layer-sha", &fakeIdentifier{name: "some/run@sha256:new-digest"})
+			fakeImageFetcher.LocalImages["some/run"] = fakeRunImage
+
+			fakeRunImageMirror = fakes.NewImage("example.com/some/run", "mirror-top-layer-sha", &fakeIdentifier{name: "example.com/some/run@sha256:mirror-digest"})
+
+			subject = &Client{
+				logger:       logging.NewLogWithWriters(&out, &out),
+				imageFetcher: fakeImageFetcher,
+			}
+		})
+
+		it.After(func() {
+			fakeAppImage.Cleanup()
+			fakeRunImage.Cleanup()
+			fakeRunImageMirror.Cleanup()
+		})
+
+		it("reports an image whose run image has been updated", func() {
+			report, err := subject.ImageOutdated(context.TODO(), ImageOutdatedOptions{
+				RepoName:   "some/app",
+				PullPolicy: config.PullNever,
+			})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, report, &ImageOutdatedReport{
+				Image:    "some/app",
+				RunImage: "some/run",
+				CurrentBase: RebaseBase{
+					TopLayer:  "old-top-layer",
+					Reference: "some/run@sha256:old-digest",
+					Digest:    "sha256:old-digest",
+				},
+				LatestBase: RebaseBase{
+					TopLayer:  "run-image-top-layer-sha",
+					Reference: "some/run@sha256:new-digest",
+					Digest:    "sha256:new-digest",
+				},
+				Outdated: true,
+			})
+			h.AssertEq(t, fakeAppImage.IsSaved(), false)
+		})
+
+		it("reports an image on the current run image as up to date", func() {
+			h.AssertNil(t, fakeAppImage.SetLabel("io.buildpacks.lifecycle.metadata",
+				`{"runImage":{"topLayer":"run-image-top-layer-sha"},"stack":{"runImage":{"image":"some/run"}}}`))
+
+			report, err := subject.ImageOutdated(context.TODO(), ImageOutdatedOptions{
+				RepoName:   "some/app",
+				PullPolicy: config.PullNever,
+			})
+			h.AssertNil(t, err)
+			h.AssertEq(t, report.Outdated, false)
+		})
+
+		when("the image is in a registry", func() {
+			var (
+				server       *httptest.Server
+				registryHost string
+			)
+
+			writeRunImage := func(platforms ...v1.Platform) map[string]v1.Hash {
+				ref, err := name.ParseReference(registryHost + "/some/run")
+				h.AssertNil(t, err)
+
+				digests := map[string]v1.Hash{}
+				index := v1.ImageIndex(empty.Index)
+				for _, platform := range platforms {
+					img, err := random.Image(1024, 1)
+					h.AssertNil(t, err)
+					digests[image.PlatformString(platform)], err = img.Digest()
+					h.AssertNil(t, err)
+
+					p := platform
+					index = mutate.AppendManifests(index, mutate.IndexAddendum{Add: img, Descriptor: v1.Descriptor{Platform: &p}})
+				}
+				h.AssertNil(t, remote.WriteIndex(ref, index))
+				return digests
+			}
+
+			it.Before(func() {
+				server = httptest.NewServer(registry.New())
+				registryHost = strings.TrimPrefix(server.URL, "http://")
+
+				h.AssertNil(t, fakeAppImage.SetLabel("io.buildpacks.lifecycle.metadata",
+					`{"runImage":{"topLayer":"old-top-layer"},"stack":{"runImage":{"image":"some/run","mirrors":["`+registryHost+`/some/run"]}}}`))
+				fakeImageFetcher.RemoteImages[registryHost+"/some/app"] = fakeAppImage
+			})
+
+			it.After(func() {
+				server.Close()
+			})
+
+			it("compares against the mirror on the registry of the image", func() {
+				ref, err := name.ParseReference(registryHost + "/some/run")
+				h.AssertNil(t, err)
+				img, err := random.Image(1024, 1)
+				h.AssertNil(t, err)
+				h.AssertNil(t, remote.Write(ref, img))
+				fakeImageFetcher.RemoteImages[registryHost+"/some/run"] = fakeRunImageMirror
+
+				report, err := subject.ImageOutdated(context.TODO(), ImageOutdatedOptions{
+					RepoName:   registryHost + "/some/app",
+					Publish:    true,
+					PullPolicy: config.PullAlways,
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, report.RunImage, registryHost+"/some/run")
+				h.AssertEq(t, report.LatestBase.Digest, "sha256:mirror-digest")
+			})
+
+			it("compares against the run image for the platform of the image", func() {
+				h.AssertNil(t, fakeAppImage.SetArchitecture("arm64"))
+				digests := writeRunImage(v1.Platform{OS: "linux", Architecture: "amd64"}, v1.Platform{OS: "linux", Architecture: "arm64"})
+
+				arm64RunImage := fakes.NewImage("some/run-arm64", "arm64-top-layer-sha", &fakeIdentifier{name: "some/run@sha256:arm64-digest"})
+				defer arm64RunImage.Cleanup()
+				h.AssertNil(t, arm64RunImage.SetArchitecture("arm64"))
+				fakeImageFetcher.RemoteImages[registryHost+"/some/run@"+digests["linux/arm64"].String()] = arm64RunImage
+
+				report, err := subject.ImageOutdated(context.TODO(), ImageOutdatedOptions{
+					RepoName:   registryHost + "/some/app",
+					Publish:    true,
+					PullPolicy: config.PullAlways,
+				})
+				h.AssertNil(t, err)
+				h.AssertEq(t, report.LatestBase.TopLayer, "arm64-top-layer-sha")
+			})
+		})
+
+		it("errors when the run image in the daemon is for another platform than the image", func() {
+			h.AssertNil(t, fakeAppImage.SetArchitecture("arm64"))
+
+			_, err := subject.ImageOutdated(context.TODO(), ImageOutdatedOptions{
+				RepoName:   "some/app",
+				PullPolicy: config.PullNever,
+			})
+			h.AssertError(t, err, "image 'some/run' is for platform 'linux/amd64', not 'linux/arm64'")
+		})
+
+		it("compares against a mirror from the config", func() {
+			fakeConfigMirror := fakes.NewImage("index.docker.io/some/other-run", "config-mirror-top-layer-sha", &fakeIdentifier{name: "config-mirror-digest"})
+			defer fakeConfigMirror.Cleanup()
+			fakeImageFetcher.LocalImages["index.docker.io/some/other-run"] = fakeConfigMirror
+
+			report, err := subject.ImageOutdated(context.TODO(), ImageOutdatedOptions{
+				RepoName:   "some/app",
+				PullPolicy: config.PullNever,
+				AdditionalMirrors: map[string][]string{
+					"some/run": {"index.docker.io/some/other-run"},
+				},
+			})
+			h.AssertNil(t, err)
+			h.AssertEq(t, report.RunImage, "index.docker.io/some/other-run")
+			h.AssertEq(t, report.LatestBase.TopLayer, "config-mirror-top-layer-sha")
+		})
+
+		it("compares against the run image given", func() {
+			report, err := subject.ImageOutdated(context.TODO(), ImageOutdatedOptions{
+				RepoName:   "some/app",
+				PullPolicy: config.PullNever,
+				RunImage:   "some/run",
+			})
+			h.AssertNil(t, err)
+			h.AssertEq(t, report.RunImage, "some/run")
+		})
+
+		when("the image has no lifecycle metadata", func() {
+			it("errors", func() {
+				fakeImageFetcher.LocalImages["some/other-app"] = fakes.NewImage("some/other-app", "", nil)
+
+				_, err := subject.ImageOutdated(context.TODO(), ImageOutdatedOptions{
+					RepoName:   "some/other-app",
+					PullPolicy: config.PullNever,
+				})
+				h.AssertError(t, err, "could not find label 'io.buildpacks.lifecycle.metadata' on image")
+			})
+		})
+	})
+}
@@ -0,0 +1,125 @@
+package pack
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/archive"
+	"github.com/buildpacks/pack/internal/buildpack"
+	"github.com/buildpacks/pack/internal/dist"
+	"github.com/buildpacks/pack/internal/layer"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+const (
+	defaultInlineBuildpackShell   = "/bin/sh"
+	defaultInlineBuildpackVersion = "0.0.0"
+)
+
+// InlineBuildpack is a buildpack whose build is a script, such as one defined in a project descriptor.
+// It always passes detection.
+type InlineBuildpack struct {
+	// ID of the buildpack.
+	ID string
+
+	// Version of the buildpack. Defaults to 0.0.0.
+	Version string
+
+	// Buildpack API the script is written against, such as 0.4.
+	API string
+
+	// Absolute path of the shell running the script. Defaults to /bin/sh.
+	Shell string
+
+	// The script, run as bin/build of the buildpack.
+	Script string
+}
+
+func (b InlineBuildpack) version() string {
+	if b.Version == "" {
+		return defaultInlineBuildpackVersion
+	}
+	return b.Version
+}
+
+func (b InlineBuildpack) shell() string {
+	if b.Shell == "" {
+		return defaultInlineBuildpackShell
+	}
+	return b.Shell
+}
+
+// buildpackToml returns the descriptor of the buildpack, supporting only stackID.
+func (b InlineBuildpack) buildpackToml(stackID string) string {
+	return fmt.Sprintf("api = %q\n\n[buildpack]\nid = %q\nversion = %q\n\n[[stacks]]\nid = %q\n",
+		b.API, b.ID, b.version(), stackID)
+}
+
+// inlineBlob is the root blob of an inline buildpack, generated on each open.
+type inlineBlob struct {
+	tarBuilder archive.TarBuilder
+}
+
+func (b *inlineBlob) Open() (io.ReadCloser, error) {
+	return b.tarBuilder.Reader(archive.DefaultTarWriterFactory()), nil
+}
+
+// createInlineBuildpacks synthesizes inline buildpacks for the stack of a builder.
+func createInlineBuildpacks(inlineBPs []InlineBuildpack, builderImage imgutil.Image, stackID string) ([]dist.Buildpack, error) {
+	if len(inlineBPs) == 0 {
+		return nil, nil
+	}
+
+	builderOS, err := builderImage.OS()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting builder OS")
+	}
+
+	if builderOS == "windows" {
+		return nil, errors.New("inline buildpacks are not supported on Windows builders")
+	}
+
+	layerWriterFactory, err := layer.NewWriterFactory(builderOS)
+	if err != nil {
+		return nil, err
+	}
+
+	var bps []dist.Buildpack
+	for _, inlineBP := range inlineBPs {
+		if inlineBP.ID == "" {
+			return nil, errors.New("inline buildpacks must have an id")
+		}
+		if inlineBP.API == "" {
+			return nil, errors.Errorf("inline buildpack %s must have an API version", style.Symbol(inlineBP.ID))
+		}
+
+		blob := &inlineBlob{}
+		ts := archive.NormalizedDateTime
+		blob.tarBuilder.AddFile("buildpack.toml", 0644, ts, []byte(inlineBP.buildpackToml(stackID)))
+		blob.tarBuilder.AddDir("bin", 0755, ts)
+		blob.tarBuilder.AddFile("bin/detect", 0755, ts, []byte(fmt.Sprintf("#!%s\nexit 0\n", inlineBP.shell())))
+		blob.tarBuilder.AddFile("bin/build", 0755, ts, []byte(fmt.Sprintf("#!%s\n%s\n", inlineBP.shell(), inlineBP.Script)))
+
+		bp, err := dist.BuildpackFromRootBlob(blob, layerWriterFactory)
+		if err != nil {
+			return nil, errors.Wrapf(err, "creating inline buildpack %s", style.Symbol(inlineBP.ID))
+		}
+		bps = append(bps, bp)
+	}
+	return bps, nil
+}
+
+// findInlineBuildpack returns the inline buildpack a declared buildpack names by '<id>' or '<id>@<version>', if any.
+func findInlineBuildpack(declaredBP string, inlineBPs []dist.Buildpack) (dist.Buildpack, bool) {
+	id, version := buildpack.ParseIDLocator(declaredBP)
+	for _, bp := range inlineBPs {
+		info := bp.Descriptor().Info
+		if info.ID == id && (version == "" || info.Version == version) {
+			return bp, true
+		}
+	}
+	return nil, false
+}
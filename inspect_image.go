@@ -2,6 +2,7 @@ package pack
 
 import (
 	"context"
+	"sort"
 	"strings"
 
 	"github.com/buildpacks/pack/config"
@@ -51,6 +52,58 @@ type ImageInfo struct {
 
 	// Processes lists all processes contributed by buildpacks.
 	Processes ProcessDetails
+
+	// Layers lists the layers of the image, bottom-most first, and what added each of them.
+	// Only set when the image is inspected WithImageLayers.
+	Layers []ImageLayer
+}
+
+// ImageLayerOrigin is what added a layer to an app image.
+type ImageLayerOrigin string
+
+const (
+	LayerOriginRunImage     ImageLayerOrigin = "run-image"
+	LayerOriginBuildpack    ImageLayerOrigin = "buildpack"
+	LayerOriginApp          ImageLayerOrigin = "app"
+	LayerOriginLauncher     ImageLayerOrigin = "launcher"
+	LayerOriginConfig       ImageLayerOrigin = "config"
+	LayerOriginProcessTypes ImageLayerOrigin = "process-types"
+	LayerOriginUnknown      ImageLayerOrigin = "unknown"
+)
+
+// ImageLayer is a layer of an app image.
+type ImageLayer struct {
+	// Diff ID of the layer, the digest of its uncompressed contents.
+	DiffID string
+
+	// Size of the uncompressed contents of the layer in bytes, or -1 if unknown.
+	Size int64
+
+	// Size of the compressed layer in bytes, or -1 if unknown, as for images in the daemon.
+	CompressedSize int64
+
+	// What added the layer.
+	Origin ImageLayerOrigin
+
+	// ID of the buildpack that contributed the layer, for buildpack layers.
+	Buildpack string
+
+	// Name of the buildpack layer, for buildpack layers.
+	Name string
+}
+
+type ImageInspectionConfig struct {
+	Layers bool
+}
+
+type ImageInspectionModifier func(config *ImageInspectionConfig)
+
+// WithImageLayers lists the layers of the image, with their sizes and origins, in the ImageInfo.
+// Sizes are measured by downloading the layers of remote images.
+func WithImageLayers() ImageInspectionModifier {
+	return func(config *ImageInspectionConfig) {
+		config.Layers = true
+	}
 }
 
 // ProcessDetails is a collection of all start command metadata
@@ -65,8 +118,13 @@ type ProcessDetails struct {
 
 // Deserialize just the subset of fields we need to avoid breaking changes
 type layersMetadata struct {
-	RunImage lifecycle.RunImageMetadata `json:"runImage" toml:"run-image"`
-	Stack    lifecycle.StackMetadata    `json:"stack" toml:"stack"`
+	RunImage     lifecycle.RunImageMetadata          `json:"runImage" toml:"run-image"`
+	Stack        lifecycle.StackMetadata             `json:"stack" toml:"stack"`
+	App          []lifecycle.LayerMetadata           `json:"app" toml:"app"`
+	Buildpacks   []lifecycle.BuildpackLayersMetadata `json:"buildpacks" toml:"buildpacks"`
+	Config       lifecycle.LayerMetadata             `json:"config" toml:"config"`
+	Launcher     lifecycle.LayerMetadata             `json:"launcher" toml:"launcher"`
+	ProcessTypes lifecycle.LayerMetadata             `json:"process-types" toml:"process-types"`
 }
 
 const (
@@ -85,7 +143,12 @@ const (
 // using this metadata, and returns it.
 // If daemon is true, first the local registry will be searched for the image.
 // Otherwise it assumes the image is remote.
-func (c *Client) InspectImage(name string, daemon bool) (*ImageInfo, error) {
+func (c *Client) InspectImage(name string, daemon bool, modifiers ...ImageInspectionModifier) (*ImageInfo, error) {
+	var inspectionConfig ImageInspectionConfig
+	for _, mod := range modifiers {
+		mod(&inspectionConfig)
+	}
+
 	img, err := c.imageFetcher.Fetch(context.Background(), name, daemon, config.PullNever)
 	if err != nil {
 		if errors.Cause(err) == image.ErrNotFound {
@@ -166,6 +229,15 @@ func (c *Client) InspectImage(name string, daemon bool) (*ImageInfo, error) {
 		processDetails.OtherProcesses = append(processDetails.OtherProcesses, proc)
 	}
 
+	var layers []ImageLayer
+	if inspectionConfig.Layers {
+		imageLayers, err := c.layerReader.LayersWithSizes(context.TODO(), img)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading layers")
+		}
+		layers = describeLayers(imageLayers, layersMd)
+	}
+
 	return &ImageInfo{
 		StackID:    stackID,
 		Stack:      layersMd.Stack,
@@ -173,5 +245,59 @@ func (c *Client) InspectImage(name string, daemon bool) (*ImageInfo, error) {
 		BOM:        buildMD.BOM,
 		Buildpacks: buildMD.Buildpacks,
 		Processes:  processDetails,
+		Layers:     layers,
 	}, nil
 }
+
+// describeLayers tells what added each layer of an app image, from its layers metadata.
+// The layers up to the top layer of the run image belong to the run image, and the others are matched by diff ID
+// with the layers the metadata records.
+func describeLayers(imageLayers []image.Layer, md layersMetadata) []ImageLayer {
+	origins := map[string]ImageLayer{}
+	addOrigin := func(sha string, layer ImageLayer) {
+		if _, ok := origins[sha]; sha != "" && !ok {
+			origins[sha] = layer
+		}
+	}
+
+	for _, bp := range md.Buildpacks {
+		names := make([]string, 0, len(bp.Layers))
+		for name := range bp.Layers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			addOrigin(bp.Layers[name].SHA, ImageLayer{Origin: LayerOriginBuildpack, Buildpack: bp.ID, Name: name})
+		}
+	}
+	for _, app := range md.App {
+		addOrigin(app.SHA, ImageLayer{Origin: LayerOriginApp})
+	}
+	addOrigin(md.Launcher.SHA, ImageLayer{Origin: LayerOriginLauncher})
+	addOrigin(md.Config.SHA, ImageLayer{Origin: LayerOriginConfig})
+	addOrigin(md.ProcessTypes.SHA, ImageLayer{Origin: LayerOriginProcessTypes})
+
+	runImageLayers := 0
+	for i, layer := range imageLayers {
+		if layer.DiffID == md.RunImage.TopLayer {
+			runImageLayers = i + 1
+			break
+		}
+	}
+
+	var layers []ImageLayer
+	for i, layer := range imageLayers {
+		described, ok := origins[layer.DiffID]
+		switch {
+		case i < runImageLayers:
+			described = ImageLayer{Origin: LayerOriginRunImage}
+		case !ok:
+			described = ImageLayer{Origin: LayerOriginUnknown}
+		}
+		described.DiffID = layer.DiffID
+		described.Size = layer.Size
+		described.CompressedSize = layer.CompressedSize
+		layers = append(layers, described)
+	}
+	return layers
+}
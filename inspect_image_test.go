@@ -22,6 +22,7 @@ import (
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
+	ifakes "github.com/buildpacks/pack/internal/fakes"
 	"github.com/buildpacks/pack/internal/image"
 	h "github.com/buildpacks/pack/testhelpers"
 	"github.com/buildpacks/pack/testmocks"
@@ -551,6 +552,78 @@ func testInspectImage(t *testing.T, when spec.G, it spec.S) {
 		}
 	})
 
+	when("#WithImageLayers", func() {
+		var fakeLayerReader *ifakes.FakeLayerReader
+
+		it.Before(func() {
+			fakeLayerReader = ifakes.NewFakeLayerReader()
+
+			var err error
+			subject, err = NewClient(
+				WithLogger(logging.NewLogWithWriters(&out, &out)),
+				WithFetcher(mockImageFetcher),
+				WithDockerClient(mockDockerClient),
+				WithLayerReader(fakeLayerReader),
+			)
+			h.AssertNil(t, err)
+
+			h.AssertNil(t, fakeImage.SetLabel(
+				"io.buildpacks.lifecycle.metadata",
+				`{
+  "runImage": {"topLayer": "sha256:run-top"},
+  "app": [{"sha": "sha256:app"}],
+  "buildpacks": [
+    {"key": "some-buildpack", "layers": {"some-layer": {"sha": "sha256:some-layer"}, "other-layer": {"sha": "sha256:other-layer"}}}
+  ],
+  "launcher": {"sha": "sha256:launcher"},
+  "config": {"sha": "sha256:config"}
+}`,
+			))
+			fakeLayerReader.ImageLayers["some/image"] = []image.Layer{
+				{DiffID: "sha256:run-base", Size: 100, CompressedSize: 40},
+				{DiffID: "sha256:run-top", Size: 10, CompressedSize: 4},
+				{DiffID: "sha256:launcher", Size: 20, CompressedSize: 8},
+				{DiffID: "sha256:other-layer", Size: 30, CompressedSize: 12},
+				{DiffID: "sha256:some-layer", Size: 40, CompressedSize: 16},
+				{DiffID: "sha256:app", Size: 50, CompressedSize: -1},
+				{DiffID: "sha256:config", Size: 1, CompressedSize: 1},
+				{DiffID: "sha256:mystery", Size: 2, CompressedSize: 2},
+			}
+
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/image", true, config.PullNever).Return(fakeImage, nil)
+		})
+
+		it("describes the layers of the image", func() {
+			info, err := subject.InspectImage("some/image", true, WithImageLayers())
+			h.AssertNil(t, err)
+			h.AssertEq(t, info.Layers, []ImageLayer{
+				{DiffID: "sha256:run-base", Size: 100, CompressedSize: 40, Origin: LayerOriginRunImage},
+				{DiffID: "sha256:run-top", Size: 10, CompressedSize: 4, Origin: LayerOriginRunImage},
+				{DiffID: "sha256:launcher", Size: 20, CompressedSize: 8, Origin: LayerOriginLauncher},
+				{DiffID: "sha256:other-layer", Size: 30, CompressedSize: 12, Origin: LayerOriginBuildpack, Buildpack: "some-buildpack", Name: "other-layer"},
+				{DiffID: "sha256:some-layer", Size: 40, CompressedSize: 16, Origin: LayerOriginBuildpack, Buildpack: "some-buildpack", Name: "some-layer"},
+				{DiffID: "sha256:app", Size: 50, CompressedSize: -1, Origin: LayerOriginApp},
+				{DiffID: "sha256:config", Size: 1, CompressedSize: 1, Origin: LayerOriginConfig},
+				{DiffID: "sha256:mystery", Size: 2, CompressedSize: 2, Origin: LayerOriginUnknown},
+			})
+		})
+
+		it("leaves the layers out without it", func() {
+			info, err := subject.InspectImage("some/image", true)
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(info.Layers), 0)
+		})
+
+		when("the layers cannot be read", func() {
+			it("errors", func() {
+				delete(fakeLayerReader.ImageLayers, "some/image")
+
+				_, err := subject.InspectImage("some/image", true, WithImageLayers())
+				h.AssertError(t, err, "reading layers")
+			})
+		})
+	})
+
 	when("the image doesn't exist", func() {
 		it("returns nil", func() {
 			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "not/some-image", true, config.PullNever).Return(nil, image.ErrNotFound)
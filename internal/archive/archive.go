@@ -3,12 +3,15 @@ package archive
 import (
 	"archive/tar"
 	"archive/zip"
+	"bufio"
 	"bytes"
+	"compress/gzip"
 	"io"
 	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/docker/docker/pkg/ioutils"
@@ -17,6 +20,8 @@ import (
 
 var NormalizedDateTime time.Time
 
+var gzipMagic = []byte{0x1f, 0x8b}
+
 func init() {
 	NormalizedDateTime = time.Date(1980, time.January, 1, 0, 0, 1, 0, time.UTC)
 }
@@ -53,6 +58,12 @@ func ReadZipAsTar(srcPath, basePath string, uid, gid int, mode int64, normalizeM
 	})
 }
 
+func ReadTarAsTar(srcPath, basePath string, uid, gid int, mode int64, normalizeModTime bool, fileFilter func(string) bool) io.ReadCloser {
+	return GenerateTar(func(tw TarWriter) error {
+		return WriteTarToTar(tw, srcPath, basePath, uid, gid, mode, normalizeModTime, fileFilter)
+	})
+}
+
 func GenerateTar(genFn func(TarWriter) error) io.ReadCloser {
 	return GenerateTarWithWriter(genFn, DefaultTarWriterFactory())
 }
@@ -306,6 +317,93 @@ func WriteZipToTar(tw TarWriter, srcZip, basePath string, uid, gid int, mode int
 	return nil
 }
 
+// WriteTarToTar writes the contents of a tar file, which may be gzip compressed, to a tar writer.
+func WriteTarToTar(tw TarWriter, srcTar, basePath string, uid, gid int, mode int64, normalizeModTime bool, fileFilter func(string) bool) error {
+	file, err := os.Open(srcTar)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader, err := decompressedReader(file)
+	if err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to read tar entry")
+		}
+
+		if header.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+
+		name, err := entryName(header.Name)
+		if err != nil {
+			return err
+		} else if name == "." {
+			continue
+		}
+
+		if fileFilter != nil && !fileFilter(name) {
+			continue
+		}
+
+		header.Name = path.Join(filepath.ToSlash(basePath), name)
+		if header.Typeflag == tar.TypeLink {
+			linkName, err := entryName(header.Linkname)
+			if err != nil {
+				return err
+			}
+			header.Linkname = path.Join(filepath.ToSlash(basePath), linkName)
+		}
+		finalizeHeader(header, uid, gid, mode, normalizeModTime)
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if header.Typeflag == tar.TypeReg {
+			if _, err := io.Copy(tw, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// entryName returns the name of a tar entry relative to the root of the archive,
+// rejecting entries that would be written outside of it.
+func entryName(name string) (string, error) {
+	cleaned := path.Clean("/" + name)[1:]
+	if cleaned == "" {
+		return ".", nil
+	}
+	if path.Clean(name) == ".." || strings.HasPrefix(path.Clean(name), "../") {
+		return "", errors.Errorf("tar entry '%s' is outside of the archive", name)
+	}
+	return cleaned, nil
+}
+
+// decompressedReader returns a reader to the contents of file, decompressing it if it is gzip compressed.
+func decompressedReader(file io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(file)
+	magic, err := buffered.Peek(2)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if bytes.Equal(magic, gzipMagic) {
+		return gzip.NewReader(buffered)
+	}
+	return buffered, nil
+}
+
 func isFatFile(header zip.FileHeader) bool {
 	var (
 		creatorFAT  uint16 = 0
@@ -344,6 +442,25 @@ func NormalizeHeader(header *tar.Header, normalizeModTime bool) {
 	header.Gname = ""
 }
 
+// IsTar detects whether or not a File is a tar archive, which may be gzip compressed
+func IsTar(file io.Reader) (bool, error) {
+	reader, err := decompressedReader(file)
+	if err != nil {
+		return false, nil
+	}
+
+	b := make([]byte, 512)
+	if _, err := io.ReadFull(reader, b); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return false, nil
+		}
+		return false, err
+	}
+
+	// the magic of ustar, pax and gnu formats
+	return bytes.HasPrefix(b[257:], []byte("ustar")), nil
+}
+
 // IsZip detects whether or not a File is a zip directory
 func IsZip(file io.Reader) (bool, error) {
 	b := make([]byte, 4)
@@ -98,6 +98,30 @@ func testArchive(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("#ReadTarAsTar", func() {
+		for _, src := range []string{"tar-to-tar.tar", "tar-to-tar.tar.gz"} {
+			src := filepath.Join("testdata", src)
+
+			it("returns a TarReader of the "+filepath.Base(src), func() {
+				rc := archive.ReadTarAsTar(src, "/nested/dir/dir-in-archive", 1234, 2345, -1, true, nil)
+
+				tr := tar.NewReader(rc)
+				verify := h.NewTarVerifier(t, tr, 1234, 2345)
+				verify.NextFile("/nested/dir/dir-in-archive/some-file.txt", "some-content", 0644)
+				verify.NextDirectory("/nested/dir/dir-in-archive/sub-dir", 0755)
+				verify.NextSymLink("/nested/dir/dir-in-archive/sub-dir/link-file", "../some-file.txt")
+
+				header, err := tr.Next()
+				h.AssertNil(t, err)
+				h.AssertEq(t, header.Name, "/nested/dir/dir-in-archive/sub-dir/hard-link-file")
+				h.AssertEq(t, header.Linkname, "/nested/dir/dir-in-archive/some-file.txt")
+
+				verify.NoMoreFilesExist()
+				h.AssertNil(t, rc.Close())
+			})
+		}
+	})
+
 	when("#ReadTarEntry", func() {
 		var (
 			err     error
@@ -511,6 +535,104 @@ func testArchive(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("#WriteTarToTar", func() {
+		var src string
+		it.Before(func() {
+			src = filepath.Join("testdata", "tar-to-tar.tar.gz")
+		})
+
+		writeTar := func(fileFilter func(string) bool) (*tar.Reader, func()) {
+			fh, err := os.Create(filepath.Join(tmpDir, "some.tar"))
+			h.AssertNil(t, err)
+
+			tw := tar.NewWriter(fh)
+			h.AssertNil(t, archive.WriteTarToTar(tw, src, "/nested/dir/dir-in-archive", 1234, 2345, 0777, true, fileFilter))
+			h.AssertNil(t, tw.Close())
+			h.AssertNil(t, fh.Close())
+
+			file, err := os.Open(filepath.Join(tmpDir, "some.tar"))
+			h.AssertNil(t, err)
+			return tar.NewReader(file), func() { file.Close() }
+		}
+
+		when("mode is set to 0777", func() {
+			it("writes a tar to the dest dir with 0777", func() {
+				tr, done := writeTar(nil)
+				defer done()
+
+				verify := h.NewTarVerifier(t, tr, 1234, 2345)
+				verify.NextFile("/nested/dir/dir-in-archive/some-file.txt", "some-content", 0777)
+				verify.NextDirectory("/nested/dir/dir-in-archive/sub-dir", 0777)
+				verify.NextSymLink("/nested/dir/dir-in-archive/sub-dir/link-file", "../some-file.txt")
+			})
+		})
+
+		when("has file filter", func() {
+			it("filters entries by their path within the archive", func() {
+				tr, done := writeTar(func(path string) bool {
+					return !strings.HasPrefix(path, "sub-dir")
+				})
+				defer done()
+
+				verify := h.NewTarVerifier(t, tr, 1234, 2345)
+				verify.NextFile("/nested/dir/dir-in-archive/some-file.txt", "some-content", 0777)
+				verify.NoMoreFilesExist()
+			})
+		})
+
+		when("an entry is outside of the archive", func() {
+			it("errors", func() {
+				src = filepath.Join(tmpDir, "escaping.tar")
+				fh, err := os.Create(src)
+				h.AssertNil(t, err)
+				tw := tar.NewWriter(fh)
+				h.AssertNil(t, tw.WriteHeader(&tar.Header{Name: "../outside.txt", Typeflag: tar.TypeReg, Mode: 0644}))
+				h.AssertNil(t, tw.Close())
+				h.AssertNil(t, fh.Close())
+
+				tw = tar.NewWriter(ioutil.Discard)
+				err = archive.WriteTarToTar(tw, src, "/nested/dir/dir-in-archive", 1234, 2345, -1, true, nil)
+				h.AssertError(t, err, "tar entry '../outside.txt' is outside of the archive")
+			})
+		})
+	})
+
+	when("#IsTar", func() {
+		for _, path := range []string{"tar-to-tar.tar", "tar-to-tar.tar.gz"} {
+			path := filepath.Join("testdata", path)
+
+			it("returns true for "+filepath.Base(path), func() {
+				file, err := os.Open(path)
+				h.AssertNil(t, err)
+				defer file.Close()
+
+				isTar, err := archive.IsTar(file)
+				h.AssertNil(t, err)
+				h.AssertTrue(t, isTar)
+			})
+		}
+
+		it("returns false for a zip file", func() {
+			file, err := os.Open(filepath.Join("testdata", "zip-to-tar.zip"))
+			h.AssertNil(t, err)
+			defer file.Close()
+
+			isTar, err := archive.IsTar(file)
+			h.AssertNil(t, err)
+			h.AssertFalse(t, isTar)
+		})
+
+		it("returns false for a file without content", func() {
+			file, err := ioutil.TempFile(tmpDir, "file.txt")
+			h.AssertNil(t, err)
+			defer file.Close()
+
+			isTar, err := archive.IsTar(file)
+			h.AssertNil(t, err)
+			h.AssertFalse(t, isTar)
+		})
+	})
+
 	when("#IsZip", func() {
 		when("file is a zip file", func() {
 			it("returns true", func() {
@@ -10,6 +10,7 @@ import (
 	"runtime"
 
 	"github.com/BurntSushi/toml"
+	"github.com/buildpacks/lifecycle"
 	"github.com/docker/docker/api/types"
 	dcontainer "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
@@ -146,24 +147,58 @@ func WriteStackToml(dstPath string, stack builder.StackMetadata, os string) Cont
 			return errors.Wrap(err, "marshaling stack metadata")
 		}
 
-		tarBuilder := archive.TarBuilder{}
+		return writeFile(ctx, ctrClient, containerID, dstPath, buf.Bytes(), os, stdout, stderr)
+	}
+}
 
-		tarPath := dstPath
-		if os == "windows" {
-			tarPath = paths.WindowsToSlash(dstPath)
+// WriteProjectMetadata writes a `project-metadata.toml` based on the ProjectMetadata provided to the destination path.
+func WriteProjectMetadata(dstPath string, metadata lifecycle.ProjectMetadata, os string) ContainerOperation {
+	return func(ctrClient client.CommonAPIClient, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
+		buf := &bytes.Buffer{}
+		err := toml.NewEncoder(buf).Encode(metadata)
+		if err != nil {
+			return errors.Wrap(err, "marshaling project metadata")
 		}
 
-		tarBuilder.AddFile(tarPath, 0755, archive.NormalizedDateTime, buf.Bytes())
-		reader := tarBuilder.Reader(archive.DefaultTarWriterFactory())
+		return writeFile(ctx, ctrClient, containerID, dstPath, buf.Bytes(), os, stdout, stderr)
+	}
+}
+
+// CopyOut reads the file at srcPath out of the container, once it has run, passing its contents to handler.
+func CopyOut(srcPath string, handler func(contents []byte) error) ContainerOperation {
+	return func(ctrClient client.CommonAPIClient, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
+		reader, stat, err := ctrClient.CopyFromContainer(ctx, containerID, srcPath)
+		if err != nil {
+			return errors.Wrapf(err, "copying '%s' out of container", srcPath)
+		}
 		defer reader.Close()
 
-		if os == "windows" {
-			dirName := paths.WindowsDir(dstPath)
-			return copyDirWindows(ctx, ctrClient, containerID, reader, dirName, stdout, stderr)
+		_, contents, err := archive.ReadTarEntry(reader, stat.Name)
+		if err != nil {
+			return errors.Wrapf(err, "reading '%s'", srcPath)
 		}
+		return handler(contents)
+	}
+}
 
-		return ctrClient.CopyToContainer(ctx, containerID, "/", reader, types.CopyToContainerOptions{})
+func writeFile(ctx context.Context, ctrClient client.CommonAPIClient, containerID string, dstPath string, contents []byte, os string, stdout, stderr io.Writer) error {
+	tarBuilder := archive.TarBuilder{}
+
+	tarPath := dstPath
+	if os == "windows" {
+		tarPath = paths.WindowsToSlash(dstPath)
+	}
+
+	tarBuilder.AddFile(tarPath, 0755, archive.NormalizedDateTime, contents)
+	reader := tarBuilder.Reader(archive.DefaultTarWriterFactory())
+	defer reader.Close()
+
+	if os == "windows" {
+		dirName := paths.WindowsDir(dstPath)
+		return copyDirWindows(ctx, ctrClient, containerID, reader, dirName, stdout, stderr)
 	}
+
+	return ctrClient.CopyToContainer(ctx, containerID, "/", reader, types.CopyToContainerOptions{})
 }
 
 func createReader(src, dst string, uid, gid int, fileFilter func(string) bool) (io.ReadCloser, error) {
@@ -181,7 +216,25 @@ func createReader(src, dst string, uid, gid int, fileFilter func(string) bool) (
 		return archive.ReadDirAsTar(src, dst, uid, gid, mode, false, fileFilter), nil
 	}
 
-	return archive.ReadZipAsTar(src, dst, uid, gid, -1, false, fileFilter), nil
+	isZip, err := isZipFile(src)
+	if err != nil {
+		return nil, err
+	}
+	if isZip {
+		return archive.ReadZipAsTar(src, dst, uid, gid, -1, false, fileFilter), nil
+	}
+
+	return archive.ReadTarAsTar(src, dst, uid, gid, -1, false, fileFilter), nil
+}
+
+func isZipFile(src string) (bool, error) {
+	file, err := os.Open(src)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	return archive.IsZip(file)
 }
 
 //EnsureVolumeAccess grants full access permissions to volumes for UID/GID-based user
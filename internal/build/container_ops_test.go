@@ -189,6 +189,45 @@ lrwxrwxrwx    1 123      456 (.*) fake-app-symlink -> fake-app-file
 			} else {
 				h.AssertContainsMatch(t, outBuf.String(), `
 -rw-r--r--    1 123      456 (.*) fake-app-file
+`)
+			}
+		})
+
+		it("writes contents from tar.gz file", func() {
+			containerDir := "/some-vol"
+			if osType == "windows" {
+				containerDir = `c:\some-vol`
+			}
+
+			ctrCmd := []string{"ls", "-al", "/some-vol"}
+			if osType == "windows" {
+				ctrCmd = []string{"cmd", "/c", `dir /q /s /n c:\some-vol`}
+			}
+
+			ctx := context.Background()
+			ctr, err := createContainer(ctx, imageName, containerDir, osType, ctrCmd...)
+			h.AssertNil(t, err)
+			defer cleanupContainer(ctx, ctr.ID)
+
+			copyDirOp := build.CopyDir(filepath.Join("testdata", "fake-app.tar.gz"), containerDir, 123, 456, osType, nil)
+
+			var outBuf, errBuf bytes.Buffer
+			err = copyDirOp(ctrClient, ctx, ctr.ID, &outBuf, &errBuf)
+			h.AssertNil(t, err)
+
+			err = container.Run(ctx, ctrClient, ctr.ID, &outBuf, &errBuf)
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, errBuf.String(), "")
+			if osType == "windows" {
+				h.AssertContainsMatch(t, strings.ReplaceAll(outBuf.String(), "\r", ""), `
+(.*)    <DIR>          ...                    .
+(.*)    <DIR>          ...                    ..
+(.*)                17 ...                    fake-app-file
+`)
+			} else {
+				h.AssertContainsMatch(t, outBuf.String(), `
+-rw-r--r--    1 123      456 (.*) fake-app-file
 `)
 			}
 		})
@@ -0,0 +1,171 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/buildpacks/lifecycle"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/logging"
+)
+
+// DetectStatus is the outcome of the detection of a buildpack of a group.
+type DetectStatus string
+
+const (
+	// DetectPass is the status of a buildpack that passed detection.
+	DetectPass DetectStatus = "pass"
+
+	// DetectFail is the status of a required buildpack that failed detection, failing its group.
+	DetectFail DetectStatus = "fail"
+
+	// DetectSkip is the status of an optional buildpack that failed detection, leaving it out of its group.
+	DetectSkip DetectStatus = "skip"
+
+	// DetectError is the status of a buildpack whose detection errored.
+	DetectError DetectStatus = "error"
+)
+
+// DetectedBuildpack is a buildpack of a group tried by the detector.
+type DetectedBuildpack struct {
+	ID      string
+	Version string
+	Status  DetectStatus
+}
+
+// DetectedGroup is a group of buildpacks tried by the detector.
+type DetectedGroup struct {
+	Buildpacks []DetectedBuildpack
+}
+
+// DetectResult is the outcome of the detector phase.
+type DetectResult struct {
+	// Buildpacks of the group selected for the build, in order.
+	// Empty if no group passed detection.
+	Group []lifecycle.GroupBuildpack
+
+	// Groups the detector tried, in order, until one passed.
+	Groups []DetectedGroup
+}
+
+// Detect runs only the detector phase, reporting the groups it tried and the group it selected.
+func (l *LifecycleExecutor) Detect(ctx context.Context, opts LifecycleOptions) (DetectResult, error) {
+	lifecycleExec, err := NewLifecycleExecution(l.logger, l.docker, opts)
+	if err != nil {
+		return DetectResult{}, err
+	}
+	defer lifecycleExec.Cleanup()
+	return lifecycleExec.DetectGroups(ctx, NewDefaultPhaseFactory)
+}
+
+// DetectGroups runs the detector at debug log level, whose output reports the result of each group it tries.
+// The output is only shown when the logger is verbose. The groups are reported even when no group passes detection.
+func (l *LifecycleExecution) DetectGroups(ctx context.Context, phaseFactoryCreator PhaseFactoryCreator) (DetectResult, error) {
+	output := &detectOutput{}
+	result := DetectResult{}
+
+	detect := l.newDetect(l.opts.Network, l.opts.Volumes, phaseFactoryCreator(l),
+		WithArgs("-log-level", "debug"),
+		withDetectOutput(output, logging.GetWriterForLevel(l.logger, logging.DebugLevel)),
+		WithPostRunOperations(CopyOut(l.mountPaths.join(l.mountPaths.layersDir(), "group.toml"), func(contents []byte) error {
+			var group lifecycle.BuildpackGroup
+			if _, err := toml.Decode(string(contents), &group); err != nil {
+				return errors.Wrap(err, "decoding group.toml")
+			}
+			result.Group = group.Group
+			return nil
+		})),
+	)
+	defer detect.Cleanup()
+
+	err := l.runPhase("detector", func() error {
+		return detect.Run(ctx)
+	})
+	result.Groups = output.groups()
+	return result, err
+}
+
+// withDetectOutput parses the output of the detector, passing it on to w.
+func withDetectOutput(output *detectOutput, w io.Writer) PhaseConfigProviderOperation {
+	return func(provider *PhaseConfigProvider) {
+		provider.infoWriter = io.MultiWriter(output, logging.NewPrefixWriter(w, "detector"))
+	}
+}
+
+// detectOutput collects the results the detector logs for each group it tries, which look like:
+//
+//	======== Results ========
+//	pass: some/buildpack@1.0.0
+//	skip: some/optional-buildpack@1.0.0
+//	fail: some/other-buildpack@1.0.0
+type detectOutput struct {
+	buf       bytes.Buffer
+	results   []DetectedGroup
+	inResults bool
+}
+
+func (d *detectOutput) Write(p []byte) (int, error) {
+	d.buf.Write(p)
+	for {
+		line, err := d.buf.ReadString('\n')
+		if err != nil {
+			// keep the partial line until the rest of it is written
+			d.buf.WriteString(line)
+			return len(p), nil
+		}
+		d.parseLine(strings.TrimSpace(line))
+	}
+}
+
+func (d *detectOutput) parseLine(line string) {
+	if line == "======== Results ========" {
+		d.results = append(d.results, DetectedGroup{Buildpacks: []DetectedBuildpack{}})
+		d.inResults = true
+		return
+	}
+	if !d.inResults {
+		return
+	}
+
+	statuses := map[string]DetectStatus{
+		"pass:": DetectPass,
+		"fail:": DetectFail,
+		"skip:": DetectSkip,
+		"err:":  DetectError,
+	}
+	fields := strings.Fields(line)
+	status, ok := statuses[firstField(fields)]
+	if !ok || len(fields) < 2 || (status != DetectError && len(fields) != 2) {
+		// plan resolution follows the results, with lines such as 'fail: some/buildpack@1.0.0 requires some-dependency'
+		d.inResults = false
+		return
+	}
+
+	parts := strings.SplitN(fields[1], "@", 2)
+	bp := DetectedBuildpack{ID: parts[0], Status: status}
+	if len(parts) == 2 {
+		bp.Version = parts[1]
+	}
+
+	group := &d.results[len(d.results)-1]
+	group.Buildpacks = append(group.Buildpacks, bp)
+}
+
+func (d *detectOutput) groups() []DetectedGroup {
+	if d.buf.Len() > 0 {
+		d.parseLine(strings.TrimSpace(d.buf.String()))
+		d.buf.Reset()
+	}
+	return d.results
+}
+
+func firstField(fields []string) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
@@ -0,0 +1,68 @@
+package build
+
+import (
+	"io"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestDetectOutput(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "detectOutput", testDetectOutput, spec.Report(report.Terminal{}), spec.Sequential())
+}
+
+func testDetectOutput(t *testing.T, when spec.G, it spec.S) {
+	when("#groups", func() {
+		it("returns the results of each group", func() {
+			output := &detectOutput{}
+			_, err := io.WriteString(output, `======== Output: some/buildpack@1.0.0 ========
+no package.json
+======== Results ========
+fail: some/buildpack@1.0.0
+======== Results ========
+skip: some/optional-buildpack@1.0.0
+err:  some/erroring-buildpack@2.0.0 (1)
+pass: some/other-buildpack@2.0.0
+Resolving plan... (try #1)
+fail: some/other-buildpack@2.0.0 requires some-dependency
+======== Results ========
+pass: some/unversioned-buildpack`)
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, output.groups(), []DetectedGroup{
+				{Buildpacks: []DetectedBuildpack{
+					{ID: "some/buildpack", Version: "1.0.0", Status: DetectFail},
+				}},
+				{Buildpacks: []DetectedBuildpack{
+					{ID: "some/optional-buildpack", Version: "1.0.0", Status: DetectSkip},
+					{ID: "some/erroring-buildpack", Version: "2.0.0", Status: DetectError},
+					{ID: "some/other-buildpack", Version: "2.0.0", Status: DetectPass},
+				}},
+				{Buildpacks: []DetectedBuildpack{
+					{ID: "some/unversioned-buildpack", Status: DetectPass},
+				}},
+			})
+		})
+
+		it("parses lines split across writes", func() {
+			output := &detectOutput{}
+			for _, chunk := range []string{"======== Res", "ults ========\npa", "ss: some/buildpack@1.0.0\n"} {
+				_, err := io.WriteString(output, chunk)
+				h.AssertNil(t, err)
+			}
+
+			h.AssertEq(t, output.groups(), []DetectedGroup{
+				{Buildpacks: []DetectedBuildpack{
+					{ID: "some/buildpack", Version: "1.0.0", Status: DetectPass},
+				}},
+			})
+		})
+	})
+}
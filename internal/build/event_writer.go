@@ -0,0 +1,69 @@
+package build
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/buildpacks/pack/events"
+)
+
+// eventWriter passes output through to another writer, and reports each complete line as a PhaseLog event.
+// Close should be called to report any trailing partial line.
+type eventWriter struct {
+	out     io.Writer
+	handler events.Handler
+	phase   string
+	stream  events.Stream
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func newEventWriter(out io.Writer, handler events.Handler, phase string, stream events.Stream) *eventWriter {
+	return &eventWriter{
+		out:     out,
+		handler: handler,
+		phase:   phase,
+		stream:  stream,
+	}
+}
+
+func (w *eventWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	n, err := w.out.Write(data)
+
+	w.buf.Write(data[:n])
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+		line := w.buf.Next(i + 1)
+		w.emit(line)
+	}
+
+	return n, err
+}
+
+func (w *eventWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.buf.Len() > 0 {
+		w.emit(w.buf.Next(w.buf.Len()))
+	}
+
+	if closer, ok := w.out.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (w *eventWriter) emit(line []byte) {
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	w.handler(events.PhaseLog{Phase: w.phase, Stream: w.stream, Line: string(line)})
+}
@@ -0,0 +1,85 @@
+package build
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/events"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestEventWriter(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "eventWriter", testEventWriter, spec.Report(report.Terminal{}), spec.Sequential())
+}
+
+type closingBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *closingBuffer) Close() error {
+	b.closed = true
+	return nil
+}
+
+func testEventWriter(t *testing.T, when spec.G, it spec.S) {
+	var (
+		out      *closingBuffer
+		received []events.Event
+		writer   *eventWriter
+	)
+
+	it.Before(func() {
+		out = &closingBuffer{}
+		received = nil
+		writer = newEventWriter(out, func(e events.Event) {
+			received = append(received, e)
+		}, "some-phase", events.Stderr)
+	})
+
+	it("passes output through", func() {
+		_, err := writer.Write([]byte("some\noutput"))
+		h.AssertNil(t, err)
+
+		h.AssertEq(t, out.String(), "some\noutput")
+	})
+
+	it("reports each complete line", func() {
+		_, err := writer.Write([]byte("first line\nsecond "))
+		h.AssertNil(t, err)
+		_, err = writer.Write([]byte("line\r\nthird"))
+		h.AssertNil(t, err)
+
+		h.AssertEq(t, received, []events.Event{
+			events.PhaseLog{Phase: "some-phase", Stream: events.Stderr, Line: "first line"},
+			events.PhaseLog{Phase: "some-phase", Stream: events.Stderr, Line: "second line"},
+		})
+	})
+
+	when("#Close", func() {
+		it("reports the trailing partial line", func() {
+			_, err := writer.Write([]byte("first line\nlast line"))
+			h.AssertNil(t, err)
+
+			h.AssertNil(t, writer.Close())
+
+			h.AssertEq(t, received, []events.Event{
+				events.PhaseLog{Phase: "some-phase", Stream: events.Stderr, Line: "first line"},
+				events.PhaseLog{Phase: "some-phase", Stream: events.Stderr, Line: "last line"},
+			})
+		})
+
+		it("closes the underlying writer", func() {
+			h.AssertNil(t, writer.Close())
+
+			h.AssertEq(t, out.closed, true)
+		})
+	})
+}
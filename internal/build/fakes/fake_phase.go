@@ -5,6 +5,7 @@ import "context"
 type FakePhase struct {
 	CleanupCallCount int
 	RunCallCount     int
+	ReturnForRun     error
 }
 
 func (p *FakePhase) Cleanup() error {
@@ -16,5 +17,5 @@ func (p *FakePhase) Cleanup() error {
 func (p *FakePhase) Run(ctx context.Context) error {
 	p.RunCallCount++
 
-	return nil
+	return p.ReturnForRun
 }
@@ -4,15 +4,21 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/buildpacks/lifecycle/api"
 	"github.com/buildpacks/lifecycle/auth"
 	"github.com/docker/docker/client"
 	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 
+	"github.com/buildpacks/pack/events"
 	"github.com/buildpacks/pack/internal/builder"
 	"github.com/buildpacks/pack/internal/cache"
+	"github.com/buildpacks/pack/internal/container"
 	"github.com/buildpacks/pack/internal/paths"
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/logging"
@@ -20,6 +26,11 @@ import (
 
 const (
 	defaultProcessType = "web"
+
+	// LayersVolumePrefix is the prefix of the name of the volume holding the layers of a build.
+	LayersVolumePrefix = "pack-layers-"
+	// AppVolumePrefix is the prefix of the name of the volume holding the app source of a build.
+	AppVolumePrefix = "pack-app-"
 )
 
 type LifecycleExecution struct {
@@ -28,13 +39,15 @@ type LifecycleExecution struct {
 	platformAPI  *api.Version
 	layersVolume string
 	appVolume    string
+	secretsDir   string
+	sshProxy     *sshAgentProxy
 	os           string
 	mountPaths   mountPaths
 	opts         LifecycleOptions
 }
 
 func NewLifecycleExecution(logger logging.Logger, docker client.CommonAPIClient, opts LifecycleOptions) (*LifecycleExecution, error) {
-	latestSupportedPlatformAPI, err := findLatestSupported(append(
+	latestSupportedPlatformAPI, err := FindLatestSupported(append(
 		opts.Builder.LifecycleDescriptor().APIs.Platform.Deprecated,
 		opts.Builder.LifecycleDescriptor().APIs.Platform.Supported...,
 	))
@@ -47,21 +60,42 @@ func NewLifecycleExecution(logger logging.Logger, docker client.CommonAPIClient,
 		return nil, err
 	}
 
+	if opts.EventHandler == nil {
+		opts.EventHandler = func(events.Event) {}
+	}
+
 	exec := &LifecycleExecution{
 		logger:       logger,
 		docker:       docker,
-		layersVolume: paths.FilterReservedNames("pack-layers-" + randString(10)),
-		appVolume:    paths.FilterReservedNames("pack-app-" + randString(10)),
+		layersVolume: paths.FilterReservedNames(LayersVolumePrefix + randString(10)),
+		appVolume:    paths.FilterReservedNames(AppVolumePrefix + randString(10)),
 		platformAPI:  latestSupportedPlatformAPI,
 		opts:         opts,
 		os:           osType,
 		mountPaths:   mountPathsForOS(osType),
 	}
 
+	if len(opts.Secrets) > 0 {
+		if exec.secretsDir, err = writeSecrets(opts.Secrets); err != nil {
+			return nil, err
+		}
+	}
+
+	// agent sockets are usually only accessible to the user running pack, which root alone can bypass
+	if len(opts.SSHAgents) > 0 && opts.Builder.UID() != 0 {
+		if exec.sshProxy, exec.opts.SSHAgents, err = proxySSHAgents(logger, opts.SSHAgents); err != nil {
+			if exec.secretsDir != "" {
+				os.RemoveAll(exec.secretsDir)
+			}
+			return nil, err
+		}
+	}
+
 	return exec, nil
 }
 
-func findLatestSupported(apis []*api.Version) (*api.Version, error) {
+// FindLatestSupported returns the latest Platform API version supported by both pack and the provided set of versions.
+func FindLatestSupported(apis []*api.Version) (*api.Version, error) {
 	for i := len(SupportedPlatformAPIVersions) - 1; i >= 0; i-- {
 		for _, version := range apis {
 			if SupportedPlatformAPIVersions[i].Equal(version) {
@@ -102,11 +136,37 @@ func (l *LifecycleExecution) PlatformAPI() *api.Version {
 }
 
 func (l *LifecycleExecution) Run(ctx context.Context, phaseFactoryCreator PhaseFactoryCreator) error {
+	if l.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.opts.Timeout)
+		defer cancel()
+	}
+
+	if err := l.run(ctx, phaseFactoryCreator); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return errors.Wrapf(err, "build exceeded timeout of %s", l.opts.Timeout)
+		}
+		return err
+	}
+	return nil
+}
+
+func (l *LifecycleExecution) run(ctx context.Context, phaseFactoryCreator PhaseFactoryCreator) error {
 	phaseFactory := phaseFactoryCreator(l)
 
-	buildCache := cache.NewVolumeCache(l.opts.Image, "build", l.docker)
+	var buildCache Cache
+	if l.opts.CacheImage != "" {
+		cacheImage, err := name.ParseReference(l.opts.CacheImage, name.WeakValidation)
+		if err != nil {
+			return errors.Wrapf(err, "invalid cache image name '%s'", l.opts.CacheImage)
+		}
+		buildCache = cache.NewImageCache(cacheImage, l.docker)
+		l.logger.Debugf("Using build cache image %s", style.Symbol(buildCache.Name()))
+	} else {
+		buildCache = cache.NewVolumeCache(l.opts.Image, "build", l.docker)
+		l.logger.Debugf("Using build cache volume %s", style.Symbol(buildCache.Name()))
+	}
 
-	l.logger.Debugf("Using build cache volume %s", style.Symbol(buildCache.Name()))
 	if l.opts.ClearCache {
 		if err := buildCache.Clear(ctx); err != nil {
 			return errors.Wrap(err, "clearing build cache")
@@ -118,45 +178,84 @@ func (l *LifecycleExecution) Run(ctx context.Context, phaseFactoryCreator PhaseF
 
 	if !l.opts.UseCreator {
 		l.logger.Info(style.Step("DETECTING"))
-		if err := l.Detect(ctx, l.opts.Network, l.opts.Volumes, phaseFactory); err != nil {
+		if err := l.runPhase("detector", func() error {
+			return l.Detect(ctx, l.opts.Network, l.opts.Volumes, phaseFactory)
+		}); err != nil {
 			return err
 		}
 
 		l.logger.Info(style.Step("ANALYZING"))
-		if err := l.Analyze(ctx, l.opts.Image.String(), buildCache.Name(), l.opts.Network, l.opts.Publish, l.opts.ClearCache, phaseFactory); err != nil {
+		if err := l.runPhase("analyzer", func() error {
+			return l.Analyze(ctx, l.opts.Image.String(), buildCache, l.opts.Network, l.opts.Publish, l.opts.ClearCache, phaseFactory)
+		}); err != nil {
 			return err
 		}
 
 		l.logger.Info(style.Step("RESTORING"))
 		if l.opts.ClearCache {
 			l.logger.Info("Skipping 'restore' due to clearing cache")
-		} else if err := l.Restore(ctx, buildCache.Name(), l.opts.Network, phaseFactory); err != nil {
+		} else if err := l.runPhase("restorer", func() error {
+			return l.Restore(ctx, buildCache, l.opts.Network, phaseFactory)
+		}); err != nil {
 			return err
 		}
 
 		l.logger.Info(style.Step("BUILDING"))
 
-		if err := l.Build(ctx, l.opts.Network, l.opts.Volumes, phaseFactory); err != nil {
+		if err := l.runPhase("builder", func() error {
+			return l.Build(ctx, l.opts.Network, l.opts.Volumes, phaseFactory)
+		}); err != nil {
 			return err
 		}
 
 		l.logger.Info(style.Step("EXPORTING"))
-		return l.Export(ctx, l.opts.Image.String(), l.opts.AdditionalTags, l.opts.RunImage, l.opts.Publish, launchCache.Name(), buildCache.Name(), l.opts.Network, phaseFactory)
-	}
-
-	return l.Create(
-		ctx,
-		l.opts.Publish,
-		l.opts.ClearCache,
-		l.opts.RunImage,
-		launchCache.Name(),
-		buildCache.Name(),
-		l.opts.Image.String(),
-		l.opts.Network,
-		l.opts.AdditionalTags,
-		l.opts.Volumes,
-		phaseFactory,
-	)
+		return l.runPhase("exporter", func() error {
+			return l.Export(ctx, l.opts.Image.String(), l.opts.AdditionalTags, l.opts.RunImage, l.opts.Publish, launchCache, buildCache, l.opts.Network, phaseFactory)
+		})
+	}
+
+	return l.runPhase("creator", func() error {
+		return l.Create(
+			ctx,
+			l.opts.Publish,
+			l.opts.ClearCache,
+			l.opts.RunImage,
+			launchCache,
+			buildCache,
+			l.opts.Image.String(),
+			l.opts.Network,
+			l.opts.AdditionalTags,
+			l.opts.Volumes,
+			phaseFactory,
+		)
+	})
+}
+
+// runPhase runs a phase, reporting when it starts and finishes to the event handler.
+func (l *LifecycleExecution) runPhase(name string, run func() error) error {
+	l.opts.EventHandler(events.PhaseStarted{Phase: name})
+
+	start := time.Now()
+	err := run()
+	l.opts.EventHandler(events.PhaseFinished{
+		Phase:    name,
+		ExitCode: exitCode(err),
+		Duration: time.Since(start),
+		Err:      err,
+	})
+	return err
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *container.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.StatusCode
+	}
+	return -1
 }
 
 func (l *LifecycleExecution) Cleanup() error {
@@ -167,21 +266,33 @@ func (l *LifecycleExecution) Cleanup() error {
 	if err := l.docker.VolumeRemove(context.Background(), l.appVolume, true); err != nil {
 		reterr = errors.Wrapf(err, "failed to clean up app volume %s", l.appVolume)
 	}
+	if l.secretsDir != "" {
+		if err := os.RemoveAll(l.secretsDir); err != nil {
+			reterr = errors.Wrap(err, "failed to clean up secrets")
+		}
+	}
+	if l.sshProxy != nil {
+		if err := l.sshProxy.Close(); err != nil {
+			reterr = errors.Wrap(err, "failed to clean up SSH agents")
+		}
+	}
 	return reterr
 }
 
 func (l *LifecycleExecution) Create(
 	ctx context.Context,
 	publish, clearCache bool,
-	runImage, launchCacheName, cacheName, repoName, networkMode string,
+	runImage string,
+	launchCache, buildCache Cache,
+	repoName, networkMode string,
 	additionalTags []string,
 	volumes []string,
 	phaseFactory PhaseFactory,
 ) error {
-	flags := addTags([]string{
-		"-cache-dir", l.mountPaths.cacheDir(),
+	flags := addTags(append(
+		l.cacheArgs(buildCache),
 		"-run-image", runImage,
-	}, additionalTags)
+	), additionalTags)
 
 	if clearCache {
 		flags = append(flags, "-skip-restore")
@@ -196,12 +307,16 @@ func (l *LifecycleExecution) Create(
 		WithFlags(l.withLogLevel(flags...)...),
 		WithArgs(repoName),
 		WithNetwork(networkMode),
-		WithBinds(append(volumes, fmt.Sprintf("%s:%s", cacheName, l.mountPaths.cacheDir()))...),
+		WithBinds(volumes...),
+		l.withBuildCache(buildCache),
 		WithContainerOperations(CopyDir(l.opts.AppPath, l.mountPaths.appDir(), l.opts.Builder.UID(), l.opts.Builder.GID(), l.os, l.opts.FileFilter)),
+		l.withProjectMetadata(),
+		l.withSecrets(),
+		l.withSSHAgents(),
 	}
 
 	if publish {
-		authConfig, err := auth.BuildEnvVar(authn.DefaultKeychain, repoName)
+		authConfig, err := auth.BuildEnvVar(authn.DefaultKeychain, registryImages(buildCache, repoName)...)
 		if err != nil {
 			return err
 		}
@@ -211,7 +326,7 @@ func (l *LifecycleExecution) Create(
 		opts = append(opts,
 			WithDaemonAccess(),
 			WithFlags("-daemon", "-launch-cache", l.mountPaths.launchCacheDir()),
-			WithBinds(fmt.Sprintf("%s:%s", launchCacheName, l.mountPaths.launchCacheDir())),
+			withCacheVolume(launchCache, l.mountPaths.launchCacheDir()),
 		)
 	}
 
@@ -221,9 +336,13 @@ func (l *LifecycleExecution) Create(
 }
 
 func (l *LifecycleExecution) Detect(ctx context.Context, networkMode string, volumes []string, phaseFactory PhaseFactory) error {
-	configProvider := NewPhaseConfigProvider(
-		"detector",
-		l,
+	detect := l.newDetect(networkMode, volumes, phaseFactory)
+	defer detect.Cleanup()
+	return detect.Run(ctx)
+}
+
+func (l *LifecycleExecution) newDetect(networkMode string, volumes []string, phaseFactory PhaseFactory, ops ...PhaseConfigProviderOperation) RunnerCleaner {
+	ops = append([]PhaseConfigProviderOperation{
 		WithLogPrefix("detector"),
 		WithArgs(
 			l.withLogLevel(
@@ -233,42 +352,50 @@ func (l *LifecycleExecution) Detect(ctx context.Context, networkMode string, vol
 		),
 		WithNetwork(networkMode),
 		WithBinds(volumes...),
+		l.withSSHAgents(),
 		WithContainerOperations(
 			EnsureVolumeAccess(l.opts.Builder.UID(), l.opts.Builder.GID(), l.os, l.layersVolume, l.appVolume),
 			CopyDir(l.opts.AppPath, l.mountPaths.appDir(), l.opts.Builder.UID(), l.opts.Builder.GID(), l.os, l.opts.FileFilter),
 		),
-	)
+	}, ops...)
 
-	detect := phaseFactory.New(configProvider)
-	defer detect.Cleanup()
-	return detect.Run(ctx)
+	return phaseFactory.New(NewPhaseConfigProvider("detector", l, ops...))
 }
 
-func (l *LifecycleExecution) Restore(ctx context.Context, cacheName, networkMode string, phaseFactory PhaseFactory) error {
-	configProvider := NewPhaseConfigProvider(
-		"restorer",
-		l,
+func (l *LifecycleExecution) Restore(ctx context.Context, buildCache Cache, networkMode string, phaseFactory PhaseFactory) error {
+	opts := []PhaseConfigProviderOperation{
 		WithLogPrefix("restorer"),
 		WithImage(l.opts.LifecycleImage),
 		WithEnv(fmt.Sprintf("%s=%d", builder.EnvUID, l.opts.Builder.UID()), fmt.Sprintf("%s=%d", builder.EnvGID, l.opts.Builder.GID())),
 		WithRoot(), // remove after platform API 0.2 is no longer supported
 		WithArgs(
 			l.withLogLevel(
-				"-cache-dir", l.mountPaths.cacheDir(),
-				"-layers", l.mountPaths.layersDir(),
+				append(
+					l.cacheArgs(buildCache),
+					"-layers", l.mountPaths.layersDir(),
+				)...,
 			)...,
 		),
 		WithNetwork(networkMode),
-		WithBinds(fmt.Sprintf("%s:%s", cacheName, l.mountPaths.cacheDir())),
-	)
+		l.withBuildCache(buildCache),
+	}
+
+	if buildCache.Type() == cache.Image {
+		authConfig, err := auth.BuildEnvVar(authn.DefaultKeychain, buildCache.Name())
+		if err != nil {
+			return err
+		}
 
-	restore := phaseFactory.New(configProvider)
+		opts = append(opts, WithRegistryAccess(authConfig))
+	}
+
+	restore := phaseFactory.New(NewPhaseConfigProvider("restorer", l, opts...))
 	defer restore.Cleanup()
 	return restore.Run(ctx)
 }
 
-func (l *LifecycleExecution) Analyze(ctx context.Context, repoName, cacheName, networkMode string, publish, clearCache bool, phaseFactory PhaseFactory) error {
-	analyze, err := l.newAnalyze(repoName, cacheName, networkMode, publish, clearCache, phaseFactory)
+func (l *LifecycleExecution) Analyze(ctx context.Context, repoName string, buildCache Cache, networkMode string, publish, clearCache bool, phaseFactory PhaseFactory) error {
+	analyze, err := l.newAnalyze(repoName, buildCache, networkMode, publish, clearCache, phaseFactory)
 	if err != nil {
 		return err
 	}
@@ -276,7 +403,7 @@ func (l *LifecycleExecution) Analyze(ctx context.Context, repoName, cacheName, n
 	return analyze.Run(ctx)
 }
 
-func (l *LifecycleExecution) newAnalyze(repoName, cacheName, networkMode string, publish, clearCache bool, phaseFactory PhaseFactory) (RunnerCleaner, error) {
+func (l *LifecycleExecution) newAnalyze(repoName string, buildCache Cache, networkMode string, publish, clearCache bool, phaseFactory PhaseFactory) (RunnerCleaner, error) {
 	args := []string{
 		"-layers", l.mountPaths.layersDir(),
 		repoName,
@@ -284,11 +411,11 @@ func (l *LifecycleExecution) newAnalyze(repoName, cacheName, networkMode string,
 	if clearCache {
 		args = prependArg("-skip-layers", args)
 	} else {
-		args = append([]string{"-cache-dir", l.mountPaths.cacheDir()}, args...)
+		args = append(l.cacheArgs(buildCache), args...)
 	}
 
 	if publish {
-		authConfig, err := auth.BuildEnvVar(authn.DefaultKeychain, repoName)
+		authConfig, err := auth.BuildEnvVar(authn.DefaultKeychain, registryImages(buildCache, repoName)...)
 		if err != nil {
 			return nil, err
 		}
@@ -303,7 +430,7 @@ func (l *LifecycleExecution) newAnalyze(repoName, cacheName, networkMode string,
 			WithRoot(),
 			WithArgs(l.withLogLevel(args...)...),
 			WithNetwork(networkMode),
-			WithBinds(fmt.Sprintf("%s:%s", cacheName, l.mountPaths.cacheDir())),
+			l.withBuildCache(buildCache),
 		)
 
 		return phaseFactory.New(configProvider), nil
@@ -329,7 +456,7 @@ func (l *LifecycleExecution) newAnalyze(repoName, cacheName, networkMode string,
 			)...,
 		),
 		WithNetwork(networkMode),
-		WithBinds(fmt.Sprintf("%s:%s", cacheName, l.mountPaths.cacheDir())),
+		l.withBuildCache(buildCache),
 	)
 
 	return phaseFactory.New(configProvider), nil
@@ -349,6 +476,8 @@ func (l *LifecycleExecution) Build(ctx context.Context, networkMode string, volu
 		WithArgs(l.withLogLevel(args...)...),
 		WithNetwork(networkMode),
 		WithBinds(volumes...),
+		l.withSecrets(),
+		l.withSSHAgents(),
 	)
 
 	build := phaseFactory.New(configProvider)
@@ -365,14 +494,14 @@ func determineDefaultProcessType(platformAPI *api.Version, providedValue string)
 	return providedValue
 }
 
-func (l *LifecycleExecution) newExport(repoName string, additionalTags []string, runImage string, publish bool, launchCacheName, cacheName, networkMode string, phaseFactory PhaseFactory) (RunnerCleaner, error) {
-	flags := []string{
-		"-cache-dir", l.mountPaths.cacheDir(),
+func (l *LifecycleExecution) newExport(repoName string, additionalTags []string, runImage string, publish bool, launchCache, buildCache Cache, networkMode string, phaseFactory PhaseFactory) (RunnerCleaner, error) {
+	flags := append(
+		l.cacheArgs(buildCache),
 		"-layers", l.mountPaths.layersDir(),
 		"-stack", l.mountPaths.stackPath(),
 		"-app", l.mountPaths.appDir(),
 		"-run-image", runImage,
-	}
+	)
 
 	processType := determineDefaultProcessType(l.platformAPI, l.opts.DefaultProcessType)
 	if processType != "" {
@@ -392,12 +521,13 @@ func (l *LifecycleExecution) newExport(repoName string, additionalTags []string,
 		WithArgs(append([]string{repoName}, additionalTags...)...),
 		WithRoot(),
 		WithNetwork(networkMode),
-		WithBinds(fmt.Sprintf("%s:%s", cacheName, l.mountPaths.cacheDir())),
+		l.withBuildCache(buildCache),
 		WithContainerOperations(WriteStackToml(l.mountPaths.stackPath(), l.opts.Builder.Stack(), l.os)),
+		l.withProjectMetadata(),
 	}
 
 	if publish {
-		authConfig, err := auth.BuildEnvVar(authn.DefaultKeychain, repoName, runImage)
+		authConfig, err := auth.BuildEnvVar(authn.DefaultKeychain, registryImages(buildCache, repoName, runImage)...)
 		if err != nil {
 			return nil, err
 		}
@@ -412,15 +542,15 @@ func (l *LifecycleExecution) newExport(repoName string, additionalTags []string,
 			opts,
 			WithDaemonAccess(),
 			WithFlags("-daemon", "-launch-cache", l.mountPaths.launchCacheDir()),
-			WithBinds(fmt.Sprintf("%s:%s", launchCacheName, l.mountPaths.launchCacheDir())),
+			withCacheVolume(launchCache, l.mountPaths.launchCacheDir()),
 		)
 	}
 
 	return phaseFactory.New(NewPhaseConfigProvider("exporter", l, opts...)), nil
 }
 
-func (l *LifecycleExecution) Export(ctx context.Context, repoName string, additionalTags []string, runImage string, publish bool, launchCacheName, cacheName, networkMode string, phaseFactory PhaseFactory) error {
-	export, err := l.newExport(repoName, additionalTags, runImage, publish, launchCacheName, cacheName, networkMode, phaseFactory)
+func (l *LifecycleExecution) Export(ctx context.Context, repoName string, additionalTags []string, runImage string, publish bool, launchCache, buildCache Cache, networkMode string, phaseFactory PhaseFactory) error {
+	export, err := l.newExport(repoName, additionalTags, runImage, publish, launchCache, buildCache, networkMode, phaseFactory)
 	if err != nil {
 		return err
 	}
@@ -435,6 +565,95 @@ func (l *LifecycleExecution) withLogLevel(args ...string) []string {
 	return args
 }
 
+// cacheArgs returns the lifecycle flags that point a phase at the build cache.
+func (l *LifecycleExecution) cacheArgs(buildCache Cache) []string {
+	if buildCache.Type() == cache.Image {
+		return []string{"-cache-image", buildCache.Name()}
+	}
+	return []string{"-cache-dir", l.mountPaths.cacheDir()}
+}
+
+// withBuildCache mounts the build cache, if it is stored in a volume.
+func (l *LifecycleExecution) withBuildCache(buildCache Cache) PhaseConfigProviderOperation {
+	if buildCache.Type() == cache.Image {
+		return func(*PhaseConfigProvider) {}
+	}
+	return withCacheVolume(buildCache, l.mountPaths.cacheDir())
+}
+
+// withProjectMetadata writes the project metadata, if any, to the layers volume
+// for the exporter to add to the app image. Lifecycles that do not support it ignore it.
+func (l *LifecycleExecution) withProjectMetadata() PhaseConfigProviderOperation {
+	if l.opts.ProjectMetadata.Source == nil {
+		return func(*PhaseConfigProvider) {}
+	}
+	return func(provider *PhaseConfigProvider) {
+		WithEnv(fmt.Sprintf("CNB_PROJECT_METADATA_PATH=%s", l.mountPaths.projectPath()))(provider)
+		WithContainerOperations(WriteProjectMetadata(l.mountPaths.projectPath(), l.opts.ProjectMetadata, l.os))(provider)
+	}
+}
+
+// withSecrets mounts the secrets, if any, read-only under the platform dir, so that they are never written
+// to the filesystem of the container, and masks their values in the output of the phase.
+// Only phases running buildpacks should be given access to secrets.
+func (l *LifecycleExecution) withSecrets() PhaseConfigProviderOperation {
+	if l.secretsDir == "" {
+		return func(*PhaseConfigProvider) {}
+	}
+	return func(provider *PhaseConfigProvider) {
+		WithBinds(fmt.Sprintf("%s:%s:ro", filepath.Join(l.secretsDir, "secrets"), l.mountPaths.secretsDir()))(provider)
+
+		provider.masks = append(provider.masks, secretValues(l.opts.Secrets)...)
+	}
+}
+
+// withSSHAgents mounts the SSH agent sockets, if any, under the platform dir,
+// pointing SSH_AUTH_SOCK at the default agent, or else the first one.
+// Unless the builder user is root, the sockets mounted are those of a proxy the builder user can connect to.
+// Only phases running buildpacks should be given access to SSH agents.
+func (l *LifecycleExecution) withSSHAgents() PhaseConfigProviderOperation {
+	if len(l.opts.SSHAgents) == 0 {
+		return func(*PhaseConfigProvider) {}
+	}
+	return func(provider *PhaseConfigProvider) {
+		authSock := l.mountPaths.join(l.mountPaths.sshDir(), l.opts.SSHAgents[0].ID)
+		for _, agent := range l.opts.SSHAgents {
+			target := l.mountPaths.join(l.mountPaths.sshDir(), agent.ID)
+			WithBinds(fmt.Sprintf("%s:%s", agent.Socket, target))(provider)
+
+			if agent.ID == DefaultSSHAgentID {
+				authSock = target
+			}
+		}
+		WithEnv("SSH_AUTH_SOCK=" + authSock)(provider)
+	}
+}
+
+// labeledCache is implemented by caches whose volumes are created with labels.
+type labeledCache interface {
+	Labels() map[string]string
+}
+
+// withCacheVolume mounts the volume of a cache at target, labelling it if the cache provides labels
+// so that the volume can be traced back to the image it was created for.
+func withCacheVolume(volumeCache Cache, target string) PhaseConfigProviderOperation {
+	return func(provider *PhaseConfigProvider) {
+		WithBinds(fmt.Sprintf("%s:%s", volumeCache.Name(), target))(provider)
+		if labeled, ok := volumeCache.(labeledCache); ok {
+			WithVolumeLabels(volumeCache.Name(), labeled.Labels())(provider)
+		}
+	}
+}
+
+// registryImages returns the images a phase needs registry credentials for,
+// including the build cache if it is stored in a registry.
+func registryImages(buildCache Cache, images ...string) []string {
+	if buildCache.Type() == cache.Image {
+		return append(images, buildCache.Name())
+	}
+	return images
+}
+
 func prependArg(arg string, args []string) []string {
 	return append([]string{arg}, args...)
 }
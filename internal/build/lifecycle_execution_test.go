@@ -3,24 +3,33 @@ package build_test
 import (
 	"bytes"
 	"context"
+	"io"
 	"io/ioutil"
 	"math/rand"
+	"net"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-containerregistry/pkg/name"
 
 	"github.com/apex/log"
+	"github.com/buildpacks/lifecycle"
 	"github.com/buildpacks/lifecycle/api"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
 	"github.com/heroku/color"
+	"github.com/pkg/errors"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
+	"github.com/buildpacks/pack/events"
 	"github.com/buildpacks/pack/internal/build"
 	"github.com/buildpacks/pack/internal/build/fakes"
+	"github.com/buildpacks/pack/internal/cache"
+	icontainer "github.com/buildpacks/pack/internal/container"
 	ilogging "github.com/buildpacks/pack/internal/logging"
 	h "github.com/buildpacks/pack/testhelpers"
 )
@@ -39,13 +48,18 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 	// Avoid contaminating tests with existing docker configuration.
 	// GGCR resolves the default keychain by inspecting DOCKER_CONFIG - this is used by the Analyze step
 	// when constructing the auth config (see `auth.BuildEnvVar` in phases.go).
-	var dockerConfigDir string
+	var (
+		dockerConfigDir string
+		fakeBuildCache  *fakes.FakeCache
+	)
 	it.Before(func() {
 		var err error
 		dockerConfigDir, err = ioutil.TempDir("", "empty-docker-config-dir")
 		h.AssertNil(t, err)
 
 		h.AssertNil(t, os.Setenv("DOCKER_CONFIG", dockerConfigDir))
+
+		fakeBuildCache = newFakeVolumeCache("some-cache")
 	})
 
 	it.After(func() {
@@ -147,6 +161,148 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				}
 			})
 		})
+		when("Run with a timeout", func() {
+			it("fails naming the timeout once it is exceeded", func() {
+				opts := build.LifecycleOptions{
+					RunImage:   "test",
+					Image:      imageName,
+					Builder:    fakeBuilder,
+					UseCreator: true,
+					Timeout:    10 * time.Millisecond,
+				}
+
+				lifecycle, err := build.NewLifecycleExecution(logger, docker, opts)
+				h.AssertNil(t, err)
+
+				fakePhaseFactory = fakes.NewFakePhaseFactory(fakes.WhichReturnsForNew(&blockingPhase{}))
+				err = lifecycle.Run(context.Background(), func(execution *build.LifecycleExecution) build.PhaseFactory {
+					return fakePhaseFactory
+				})
+				h.AssertError(t, err, "build exceeded timeout of 10ms: timed out in phase 'creator'")
+			})
+		})
+
+		when("Run with an event handler", func() {
+			var (
+				received []events.Event
+				opts     build.LifecycleOptions
+			)
+
+			it.Before(func() {
+				received = nil
+				opts = build.LifecycleOptions{
+					RunImage: "test",
+					Image:    imageName,
+					Builder:  fakeBuilder,
+					EventHandler: func(e events.Event) {
+						received = append(received, e)
+					},
+				}
+			})
+
+			it("reports when each phase starts and finishes", func() {
+				lifecycle, err := build.NewLifecycleExecution(logger, docker, opts)
+				h.AssertNil(t, err)
+
+				err = lifecycle.Run(context.Background(), func(execution *build.LifecycleExecution) build.PhaseFactory {
+					return fakePhaseFactory
+				})
+				h.AssertNil(t, err)
+
+				var reported []string
+				for _, e := range received {
+					switch event := e.(type) {
+					case events.PhaseStarted:
+						reported = append(reported, "started "+event.Phase)
+					case events.PhaseFinished:
+						h.AssertEq(t, event.ExitCode, 0)
+						h.AssertNil(t, event.Err)
+						reported = append(reported, "finished "+event.Phase)
+					}
+				}
+				h.AssertEq(t, reported, []string{
+					"started detector", "finished detector",
+					"started analyzer", "finished analyzer",
+					"started restorer", "finished restorer",
+					"started builder", "finished builder",
+					"started exporter", "finished exporter",
+				})
+			})
+
+			it("reports the exit code of a failed phase", func() {
+				fakePhaseFactory = fakes.NewFakePhaseFactory(fakes.WhichReturnsForNew(&fakes.FakePhase{
+					ReturnForRun: &icontainer.ExitError{StatusCode: 3},
+				}))
+
+				lifecycle, err := build.NewLifecycleExecution(logger, docker, opts)
+				h.AssertNil(t, err)
+
+				err = lifecycle.Run(context.Background(), func(execution *build.LifecycleExecution) build.PhaseFactory {
+					return fakePhaseFactory
+				})
+				h.AssertError(t, err, "failed with status code: 3")
+
+				h.AssertEq(t, len(received), 2)
+				finished, ok := received[1].(events.PhaseFinished)
+				h.AssertEq(t, ok, true)
+				h.AssertEq(t, finished.Phase, "detector")
+				h.AssertEq(t, finished.ExitCode, 3)
+				h.AssertNotNil(t, finished.Err)
+			})
+		})
+
+		when("Run with a cache image", func() {
+			it("uses the cache image for each phase that accesses the cache", func() {
+				opts := build.LifecycleOptions{
+					Publish:    true,
+					ClearCache: false,
+					RunImage:   "test",
+					Image:      imageName,
+					Builder:    fakeBuilder,
+					CacheImage: "some-registry.io/some/cache-image",
+				}
+
+				lifecycle, err := build.NewLifecycleExecution(logger, docker, opts)
+				h.AssertNil(t, err)
+
+				err = lifecycle.Run(context.Background(), func(execution *build.LifecycleExecution) build.PhaseFactory {
+					return fakePhaseFactory
+				})
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, len(fakePhaseFactory.NewCalledWithProvider), 5)
+
+				for _, entry := range fakePhaseFactory.NewCalledWithProvider {
+					switch entry.Name() {
+					case "analyzer", "restorer", "exporter":
+						h.AssertIncludeAllExpectedPatterns(t,
+							entry.ContainerConfig().Cmd,
+							[]string{"-cache-image", "some-registry.io/some/cache-image:latest"},
+						)
+						h.AssertSliceNotContains(t, entry.ContainerConfig().Cmd, "-cache-dir")
+					}
+				}
+			})
+
+			it("fails for an invalid cache image name", func() {
+				opts := build.LifecycleOptions{
+					Publish:    true,
+					RunImage:   "test",
+					Image:      imageName,
+					Builder:    fakeBuilder,
+					CacheImage: "%%%",
+				}
+
+				lifecycle, err := build.NewLifecycleExecution(logger, docker, opts)
+				h.AssertNil(t, err)
+
+				err = lifecycle.Run(context.Background(), func(execution *build.LifecycleExecution) build.PhaseFactory {
+					return fakePhaseFactory
+				})
+				h.AssertError(t, err, "invalid cache image name")
+			})
+		})
+
 		when("Run without using creator", func() {
 			it("succeeds", func() {
 				opts := build.LifecycleOptions{
@@ -192,8 +348,8 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				false,
 				false,
 				"test",
-				"test",
-				"test",
+				newFakeVolumeCache("test"),
+				fakeBuildCache,
 				"test",
 				"test",
 				[]string{},
@@ -217,8 +373,8 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				false,
 				false,
 				expectedRunImage,
-				"test",
-				"test",
+				newFakeVolumeCache("test"),
+				fakeBuildCache,
 				expectedRepoName,
 				"test",
 				[]string{},
@@ -240,6 +396,59 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			)
 		})
 
+		when("project metadata is provided", func() {
+			it("writes it for the exporter", func() {
+				lifecycleExec := newTestLifecycleExec(t, false, func(opts *build.LifecycleOptions) {
+					opts.ProjectMetadata = lifecycle.ProjectMetadata{Source: &lifecycle.ProjectSource{Type: "git"}}
+				})
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycleExec.Create(context.Background(), false, false, "test", newFakeVolumeCache("test"), fakeBuildCache, "test", "test", []string{}, []string{}, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[len(fakePhaseFactory.NewCalledWithProvider)-1]
+				h.AssertSliceContains(t, configProvider.ContainerConfig().Env, "CNB_PROJECT_METADATA_PATH=/layers/project-metadata.toml")
+				h.AssertFunctionName(t, configProvider.ContainerOps()[len(configProvider.ContainerOps())-1], "WriteProjectMetadata")
+			})
+		})
+
+		when("secrets are provided", func() {
+			it("mounts them read-only", func() {
+				lifecycleExec := newTestLifecycleExec(t, false, func(opts *build.LifecycleOptions) {
+					opts.Secrets = []build.Secret{{ID: "some-secret", Value: []byte("some-value")}}
+				})
+				defer lifecycleExec.Cleanup()
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycleExec.Create(context.Background(), false, false, "test", newFakeVolumeCache("test"), fakeBuildCache, "test", "test", []string{}, []string{}, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[len(fakePhaseFactory.NewCalledWithProvider)-1]
+				h.AssertSliceContainsMatch(t, configProvider.HostConfig().Binds, `/secrets:/platform/secrets:ro$`)
+			})
+		})
+
+		when("SSH agents are provided", func() {
+			it("mounts them and points SSH_AUTH_SOCK at the default agent", func() {
+				lifecycleExec := newTestLifecycleExec(t, false, func(opts *build.LifecycleOptions) {
+					opts.Builder = newRootBuilder(t)
+					opts.SSHAgents = []build.SSHAgent{
+						{ID: "some-agent", Socket: "/some/agent.sock"},
+						{ID: "default", Socket: "/default/agent.sock"},
+					}
+				})
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycleExec.Create(context.Background(), false, false, "test", newFakeVolumeCache("test"), fakeBuildCache, "test", "test", []string{}, []string{}, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[len(fakePhaseFactory.NewCalledWithProvider)-1]
+				h.AssertSliceContains(t, configProvider.HostConfig().Binds, "/some/agent.sock:/platform/ssh/some-agent")
+				h.AssertSliceContains(t, configProvider.HostConfig().Binds, "/default/agent.sock:/platform/ssh/default")
+				h.AssertSliceContains(t, configProvider.ContainerConfig().Env, "SSH_AUTH_SOCK=/platform/ssh/default")
+			})
+		})
+
 		it("configures the phase with the expected network mode", func() {
 			lifecycle := newTestLifecycleExec(t, false)
 			fakePhaseFactory := fakes.NewFakePhaseFactory()
@@ -250,8 +459,8 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				false,
 				false,
 				"test",
-				"test",
-				"test",
+				newFakeVolumeCache("test"),
+				fakeBuildCache,
 				"test",
 				expectedNetworkMode,
 				[]string{},
@@ -277,8 +486,8 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 					false,
 					true,
 					"test",
-					"test",
-					"test",
+					newFakeVolumeCache("test"),
+					fakeBuildCache,
 					"test",
 					"test",
 					[]string{},
@@ -309,8 +518,8 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 					false,
 					false,
 					"test",
-					"test",
-					"test",
+					newFakeVolumeCache("test"),
+					fakeBuildCache,
 					"test",
 					"test",
 					[]string{},
@@ -342,8 +551,8 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 					false,
 					false,
 					"test",
-					"test",
-					"test",
+					newFakeVolumeCache("test"),
+					fakeBuildCache,
 					"test",
 					"test",
 					additionalTags,
@@ -375,8 +584,8 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 					true,
 					false,
 					"test",
-					"test",
-					"some-cache",
+					newFakeVolumeCache("test"),
+					fakeBuildCache,
 					"test",
 					"test",
 					[]string{},
@@ -401,8 +610,8 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 					true,
 					false,
 					"test",
-					"test",
-					"test",
+					newFakeVolumeCache("test"),
+					fakeBuildCache,
 					"test",
 					"test",
 					[]string{},
@@ -428,8 +637,8 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 					true,
 					false,
 					"test",
-					"test",
-					"test",
+					newFakeVolumeCache("test"),
+					fakeBuildCache,
 					expectedRepos,
 					"test",
 					[]string{},
@@ -452,7 +661,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 					lifecycle := newTestLifecycleExec(t, true, fakes.WithBuilder(fakeBuilder))
 					fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-					err = lifecycle.Export(context.Background(), "test", []string{}, "test", true, "test", "test", "test", fakePhaseFactory)
+					err = lifecycle.Export(context.Background(), "test", []string{}, "test", true, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 					h.AssertNil(t, err)
 
 					lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -470,7 +679,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 					lifecycle := newTestLifecycleExec(t, true, fakes.WithBuilder(fakeBuilder))
 					fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-					err = lifecycle.Export(context.Background(), "test", []string{}, "test", true, "test", "test", "test", fakePhaseFactory)
+					err = lifecycle.Export(context.Background(), "test", []string{}, "test", true, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 					h.AssertNil(t, err)
 
 					lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -492,8 +701,8 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 					false,
 					false,
 					"test",
-					"test",
-					"test",
+					newFakeVolumeCache("test"),
+					fakeBuildCache,
 					"test",
 					"test",
 					[]string{},
@@ -523,8 +732,8 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 					false,
 					false,
 					"test",
-					"some-launch-cache",
-					"some-cache",
+					newFakeVolumeCache("some-launch-cache"),
+					fakeBuildCache,
 					"test",
 					"test",
 					[]string{},
@@ -552,8 +761,8 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 					false,
 					false,
 					"test",
-					"some-launch-cache",
-					"some-cache",
+					newFakeVolumeCache("some-launch-cache"),
+					fakeBuildCache,
 					"test",
 					"test",
 					[]string{},
@@ -576,7 +785,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 					lifecycle := newTestLifecycleExec(t, true, fakes.WithBuilder(fakeBuilder))
 					fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-					err = lifecycle.Export(context.Background(), "test", []string{}, "test", false, "test", "test", "test", fakePhaseFactory)
+					err = lifecycle.Export(context.Background(), "test", []string{}, "test", false, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 					h.AssertNil(t, err)
 
 					lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -594,7 +803,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 					lifecycle := newTestLifecycleExec(t, true, fakes.WithBuilder(fakeBuilder))
 					fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-					err = lifecycle.Export(context.Background(), "test", []string{}, "test", false, "test", "test", "test", fakePhaseFactory)
+					err = lifecycle.Export(context.Background(), "test", []string{}, "test", false, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 					h.AssertNil(t, err)
 
 					lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -605,6 +814,39 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 		})
+
+		when("using a cache image", func() {
+			it("configures the phase with the cache image and without a cache bind", func() {
+				lifecycle := newTestLifecycleExec(t, false)
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycle.Create(
+					context.Background(),
+					true,
+					false,
+					"test",
+					newFakeVolumeCache("test"),
+					newFakeImageCache("some-cache-image"),
+					"test",
+					"test",
+					[]string{},
+					[]string{},
+					fakePhaseFactory,
+				)
+				h.AssertNil(t, err)
+
+				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
+				h.AssertNotEq(t, lastCallIndex, -1)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[lastCallIndex]
+				h.AssertIncludeAllExpectedPatterns(t,
+					configProvider.ContainerConfig().Cmd,
+					[]string{"-cache-image", "some-cache-image"},
+				)
+				h.AssertSliceNotContains(t, configProvider.ContainerConfig().Cmd, "-cache-dir")
+				h.AssertSliceNotContains(t, configProvider.HostConfig().Binds, "some-cache-image:/cache")
+			})
+		})
 	})
 
 	when("#Detect", func() {
@@ -681,7 +923,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			fakePhase := &fakes.FakePhase{}
 			fakePhaseFactory := fakes.NewFakePhaseFactory(fakes.WhichReturnsForNew(fakePhase))
 
-			err := lifecycle.Analyze(context.Background(), "test", "test", "test", false, false, fakePhaseFactory)
+			err := lifecycle.Analyze(context.Background(), "test", fakeBuildCache, "test", false, false, fakePhaseFactory)
 			h.AssertNil(t, err)
 
 			h.AssertEq(t, fakePhase.CleanupCallCount, 1)
@@ -694,7 +936,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 				expectedRepoName := "some-repo-name"
 
-				err := lifecycle.Analyze(context.Background(), expectedRepoName, "test", "test", false, true, fakePhaseFactory)
+				err := lifecycle.Analyze(context.Background(), expectedRepoName, fakeBuildCache, "test", false, true, fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -712,7 +954,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 				expectedRepoName := "some-repo-name"
 
-				err := lifecycle.Analyze(context.Background(), expectedRepoName, "test", "test", false, false, fakePhaseFactory)
+				err := lifecycle.Analyze(context.Background(), expectedRepoName, fakeBuildCache, "test", false, false, fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -734,7 +976,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				})
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-				err := lifecycle.Analyze(context.Background(), "test", "test", "test", true, false, fakePhaseFactory)
+				err := lifecycle.Analyze(context.Background(), "test", fakeBuildCache, "test", true, false, fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -750,7 +992,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				lifecycle := newTestLifecycleExec(t, false, fakes.WithBuilder(fakeBuilder))
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-				err = lifecycle.Analyze(context.Background(), "test", "test", "test", true, false, fakePhaseFactory)
+				err = lifecycle.Analyze(context.Background(), "test", fakeBuildCache, "test", true, false, fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -767,7 +1009,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				expectedRepos := "some-repo-name"
 				expectedNetworkMode := "some-network-mode"
 
-				err := lifecycle.Analyze(context.Background(), expectedRepos, "test", expectedNetworkMode, true, false, fakePhaseFactory)
+				err := lifecycle.Analyze(context.Background(), expectedRepos, fakeBuildCache, expectedNetworkMode, true, false, fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -782,7 +1024,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				lifecycle := newTestLifecycleExec(t, false)
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-				err := lifecycle.Analyze(context.Background(), "test", "test", "test", true, false, fakePhaseFactory)
+				err := lifecycle.Analyze(context.Background(), "test", fakeBuildCache, "test", true, false, fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -797,7 +1039,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 				expectedRepoName := "some-repo-name"
 
-				err := verboseLifecycle.Analyze(context.Background(), expectedRepoName, "test", "test", true, false, fakePhaseFactory)
+				err := verboseLifecycle.Analyze(context.Background(), expectedRepoName, fakeBuildCache, "test", true, false, fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -818,7 +1060,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 				expectedBind := "some-cache:/cache"
 
-				err := lifecycle.Analyze(context.Background(), "test", "some-cache", "test", true, false, fakePhaseFactory)
+				err := lifecycle.Analyze(context.Background(), "test", fakeBuildCache, "test", true, false, fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -836,7 +1078,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				})
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-				err := lifecycle.Analyze(context.Background(), "test", "test", "test", false, false, fakePhaseFactory)
+				err := lifecycle.Analyze(context.Background(), "test", fakeBuildCache, "test", false, false, fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -852,7 +1094,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				lifecycle := newTestLifecycleExec(t, false, fakes.WithBuilder(fakeBuilder))
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-				err = lifecycle.Analyze(context.Background(), "test", "test", "test", false, false, fakePhaseFactory)
+				err = lifecycle.Analyze(context.Background(), "test", fakeBuildCache, "test", false, false, fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -867,7 +1109,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				lifecycle := newTestLifecycleExec(t, false)
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-				err := lifecycle.Analyze(context.Background(), "test", "test", "test", false, false, fakePhaseFactory)
+				err := lifecycle.Analyze(context.Background(), "test", fakeBuildCache, "test", false, false, fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -883,7 +1125,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 				expectedRepoName := "some-repo-name"
 
-				err := verboseLifecycle.Analyze(context.Background(), expectedRepoName, "test", "test", false, true, fakePhaseFactory)
+				err := verboseLifecycle.Analyze(context.Background(), expectedRepoName, fakeBuildCache, "test", false, true, fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -905,7 +1147,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 				expectedNetworkMode := "some-network-mode"
 
-				err := lifecycle.Analyze(context.Background(), "test", "test", expectedNetworkMode, false, false, fakePhaseFactory)
+				err := lifecycle.Analyze(context.Background(), "test", fakeBuildCache, expectedNetworkMode, false, false, fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -920,7 +1162,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 				expectedBind := "some-cache:/cache"
 
-				err := lifecycle.Analyze(context.Background(), "test", "some-cache", "test", false, true, fakePhaseFactory)
+				err := lifecycle.Analyze(context.Background(), "test", fakeBuildCache, "test", false, true, fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -930,6 +1172,27 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				h.AssertSliceContains(t, configProvider.HostConfig().Binds, expectedBind)
 			})
 		})
+
+		when("using a cache image", func() {
+			it("configures the phase with the cache image and registry access", func() {
+				lifecycle := newTestLifecycleExec(t, false)
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycle.Analyze(context.Background(), "test", newFakeImageCache("some-cache-image"), "test", true, false, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
+				h.AssertNotEq(t, lastCallIndex, -1)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[lastCallIndex]
+				h.AssertIncludeAllExpectedPatterns(t,
+					configProvider.ContainerConfig().Cmd,
+					[]string{"-cache-image", "some-cache-image"},
+				)
+				h.AssertSliceContains(t, configProvider.ContainerConfig().Env, "CNB_REGISTRY_AUTH={}")
+				h.AssertSliceNotContains(t, configProvider.HostConfig().Binds, "some-cache-image:/cache")
+			})
+		})
 	})
 
 	when("#Restore", func() {
@@ -939,7 +1202,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			})
 			fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-			err := lifecycle.Restore(context.Background(), "test", "test", fakePhaseFactory)
+			err := lifecycle.Restore(context.Background(), fakeBuildCache, "test", fakePhaseFactory)
 			h.AssertNil(t, err)
 
 			lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -955,7 +1218,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			lifecycle := newTestLifecycleExec(t, false, fakes.WithBuilder(fakeBuilder))
 			fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-			err = lifecycle.Restore(context.Background(), "test", "test", fakePhaseFactory)
+			err = lifecycle.Restore(context.Background(), fakeBuildCache, "test", fakePhaseFactory)
 			h.AssertNil(t, err)
 
 			lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -971,7 +1234,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			fakePhase := &fakes.FakePhase{}
 			fakePhaseFactory := fakes.NewFakePhaseFactory(fakes.WhichReturnsForNew(fakePhase))
 
-			err := lifecycle.Restore(context.Background(), "test", "test", fakePhaseFactory)
+			err := lifecycle.Restore(context.Background(), fakeBuildCache, "test", fakePhaseFactory)
 			h.AssertNil(t, err)
 
 			h.AssertEq(t, fakePhase.CleanupCallCount, 1)
@@ -982,7 +1245,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			lifecycle := newTestLifecycleExec(t, false)
 			fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-			err := lifecycle.Restore(context.Background(), "test", "test", fakePhaseFactory)
+			err := lifecycle.Restore(context.Background(), fakeBuildCache, "test", fakePhaseFactory)
 			h.AssertNil(t, err)
 
 			lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -996,7 +1259,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			verboseLifecycle := newTestLifecycleExec(t, true)
 			fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-			err := verboseLifecycle.Restore(context.Background(), "test", "test", fakePhaseFactory)
+			err := verboseLifecycle.Restore(context.Background(), fakeBuildCache, "test", fakePhaseFactory)
 			h.AssertNil(t, err)
 
 			lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1017,7 +1280,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			fakePhaseFactory := fakes.NewFakePhaseFactory()
 			expectedNetworkMode := "some-network-mode"
 
-			err := lifecycle.Restore(context.Background(), "test", expectedNetworkMode, fakePhaseFactory)
+			err := lifecycle.Restore(context.Background(), fakeBuildCache, expectedNetworkMode, fakePhaseFactory)
 			h.AssertNil(t, err)
 
 			lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1032,7 +1295,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			fakePhaseFactory := fakes.NewFakePhaseFactory()
 			expectedBind := "some-cache:/cache"
 
-			err := lifecycle.Restore(context.Background(), "some-cache", "test", fakePhaseFactory)
+			err := lifecycle.Restore(context.Background(), fakeBuildCache, "test", fakePhaseFactory)
 			h.AssertNil(t, err)
 
 			lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1041,6 +1304,28 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			configProvider := fakePhaseFactory.NewCalledWithProvider[lastCallIndex]
 			h.AssertSliceContains(t, configProvider.HostConfig().Binds, expectedBind)
 		})
+
+		when("using a cache image", func() {
+			it("configures the phase with the cache image and registry access", func() {
+				lifecycle := newTestLifecycleExec(t, false)
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycle.Restore(context.Background(), newFakeImageCache("some-cache-image"), "test", fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
+				h.AssertNotEq(t, lastCallIndex, -1)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[lastCallIndex]
+				h.AssertIncludeAllExpectedPatterns(t,
+					configProvider.ContainerConfig().Cmd,
+					[]string{"-cache-image", "some-cache-image"},
+				)
+				h.AssertSliceNotContains(t, configProvider.ContainerConfig().Cmd, "-cache-dir")
+				h.AssertSliceContains(t, configProvider.ContainerConfig().Env, "CNB_REGISTRY_AUTH={}")
+				h.AssertSliceNotContains(t, configProvider.HostConfig().Binds, "some-cache-image:/cache")
+			})
+		})
 	})
 
 	when("#Build", func() {
@@ -1108,6 +1393,186 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			configProvider := fakePhaseFactory.NewCalledWithProvider[lastCallIndex]
 			h.AssertSliceContains(t, configProvider.HostConfig().Binds, expectedBind)
 		})
+
+		when("secrets are provided", func() {
+			it("mounts them read-only until cleaned up", func() {
+				lifecycleExec := newTestLifecycleExec(t, false, func(opts *build.LifecycleOptions) {
+					opts.Secrets = []build.Secret{{ID: "some-secret", Value: []byte("some-value")}}
+				})
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycleExec.Build(context.Background(), "test", []string{}, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[len(fakePhaseFactory.NewCalledWithProvider)-1]
+				var secretsDir string
+				for _, bind := range configProvider.HostConfig().Binds {
+					if strings.HasSuffix(bind, ":/platform/secrets:ro") {
+						secretsDir = strings.TrimSuffix(bind, ":/platform/secrets:ro")
+					}
+				}
+				h.AssertNotEq(t, secretsDir, "")
+				contents, err := ioutil.ReadFile(filepath.Join(secretsDir, "some-secret"))
+				h.AssertNil(t, err)
+				h.AssertEq(t, string(contents), "some-value")
+
+				lifecycleExec.Cleanup()
+				_, err = os.Stat(secretsDir)
+				h.AssertTrue(t, os.IsNotExist(err))
+			})
+
+			it("does not mount them into other phases", func() {
+				lifecycleExec := newTestLifecycleExec(t, false, func(opts *build.LifecycleOptions) {
+					opts.Secrets = []build.Secret{{ID: "some-secret", Value: []byte("some-value")}}
+				})
+				defer lifecycleExec.Cleanup()
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycleExec.Detect(context.Background(), "test", []string{}, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[len(fakePhaseFactory.NewCalledWithProvider)-1]
+				for _, bind := range configProvider.HostConfig().Binds {
+					h.AssertNotContains(t, bind, "/platform/secrets")
+				}
+			})
+		})
+
+		when("SSH agents are provided", func() {
+			it("mounts them and points SSH_AUTH_SOCK at the first agent", func() {
+				lifecycleExec := newTestLifecycleExec(t, false, func(opts *build.LifecycleOptions) {
+					opts.Builder = newRootBuilder(t)
+					opts.SSHAgents = []build.SSHAgent{
+						{ID: "some-agent", Socket: "/some/agent.sock"},
+						{ID: "other-agent", Socket: "/other/agent.sock"},
+					}
+				})
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycleExec.Build(context.Background(), "test", []string{}, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[len(fakePhaseFactory.NewCalledWithProvider)-1]
+				h.AssertSliceContains(t, configProvider.HostConfig().Binds, "/some/agent.sock:/platform/ssh/some-agent")
+				h.AssertSliceContains(t, configProvider.HostConfig().Binds, "/other/agent.sock:/platform/ssh/other-agent")
+				h.AssertSliceContains(t, configProvider.ContainerConfig().Env, "SSH_AUTH_SOCK=/platform/ssh/some-agent")
+			})
+
+			it("mounts them into the detect phase", func() {
+				lifecycleExec := newTestLifecycleExec(t, false, func(opts *build.LifecycleOptions) {
+					opts.Builder = newRootBuilder(t)
+					opts.SSHAgents = []build.SSHAgent{{ID: "default", Socket: "/default/agent.sock"}}
+				})
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycleExec.Detect(context.Background(), "test", []string{}, fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[len(fakePhaseFactory.NewCalledWithProvider)-1]
+				h.AssertSliceContains(t, configProvider.HostConfig().Binds, "/default/agent.sock:/platform/ssh/default")
+				h.AssertSliceContains(t, configProvider.ContainerConfig().Env, "SSH_AUTH_SOCK=/platform/ssh/default")
+			})
+
+			it("does not mount them into other phases", func() {
+				lifecycleExec := newTestLifecycleExec(t, false, func(opts *build.LifecycleOptions) {
+					opts.Builder = newRootBuilder(t)
+					opts.SSHAgents = []build.SSHAgent{{ID: "default", Socket: "/default/agent.sock"}}
+				})
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycleExec.Restore(context.Background(), fakeBuildCache, "test", fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[len(fakePhaseFactory.NewCalledWithProvider)-1]
+				h.AssertSliceNotContains(t, configProvider.HostConfig().Binds, "/default/agent.sock:/platform/ssh/default")
+				h.AssertSliceNotContains(t, configProvider.ContainerConfig().Env, "SSH_AUTH_SOCK=/platform/ssh/default")
+			})
+
+			when("the builder user is not root", func() {
+				var (
+					tmpDir      string
+					agentSocket string
+					agent       net.Listener
+				)
+
+				it.Before(func() {
+					var err error
+					tmpDir, err = ioutil.TempDir("", "ssh-agent")
+					h.AssertNil(t, err)
+
+					agentSocket = filepath.Join(tmpDir, "agent.sock")
+					agent, err = net.Listen("unix", agentSocket)
+					h.AssertNil(t, err)
+
+					// echoes the requests of a connection back
+					go func() {
+						conn, err := agent.Accept()
+						if err != nil {
+							return
+						}
+						defer conn.Close()
+						io.Copy(conn, conn)
+					}()
+				})
+
+				it.After(func() {
+					agent.Close()
+					h.AssertNil(t, os.RemoveAll(tmpDir))
+				})
+
+				buildWithAgent := func() (*build.LifecycleExecution, string) {
+					fakeBuilder, err := fakes.NewFakeBuilder(fakes.WithUID(2222), fakes.WithGID(3333))
+					h.AssertNil(t, err)
+
+					lifecycleExec := newTestLifecycleExec(t, false, fakes.WithBuilder(fakeBuilder), func(opts *build.LifecycleOptions) {
+						opts.SSHAgents = []build.SSHAgent{{ID: "default", Socket: agentSocket}}
+					})
+					fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+					err = lifecycleExec.Build(context.Background(), "test", []string{}, fakePhaseFactory)
+					h.AssertNil(t, err)
+
+					configProvider := fakePhaseFactory.NewCalledWithProvider[len(fakePhaseFactory.NewCalledWithProvider)-1]
+					h.AssertSliceContains(t, configProvider.ContainerConfig().Env, "SSH_AUTH_SOCK=/platform/ssh/default")
+					for _, bind := range configProvider.HostConfig().Binds {
+						if strings.HasSuffix(bind, ":/platform/ssh/default") {
+							return lifecycleExec, strings.TrimSuffix(bind, ":/platform/ssh/default")
+						}
+					}
+					t.Fatalf("expected a bind to /platform/ssh/default in %v", configProvider.HostConfig().Binds)
+					return nil, ""
+				}
+
+				it("mounts a socket forwarding to the agent that any user can connect to", func() {
+					lifecycleExec, socket := buildWithAgent()
+					defer lifecycleExec.Cleanup()
+					h.AssertNotEq(t, socket, agentSocket)
+
+					info, err := os.Stat(socket)
+					h.AssertNil(t, err)
+					h.AssertEq(t, info.Mode().Perm(), os.FileMode(0666))
+
+					conn, err := net.Dial("unix", socket)
+					h.AssertNil(t, err)
+					defer conn.Close()
+
+					_, err = conn.Write([]byte("request-identities"))
+					h.AssertNil(t, err)
+					response := make([]byte, len("request-identities"))
+					_, err = io.ReadFull(conn, response)
+					h.AssertNil(t, err)
+					h.AssertEq(t, string(response), "request-identities")
+				})
+
+				it("removes the socket on cleanup", func() {
+					lifecycleExec, socket := buildWithAgent()
+					lifecycleExec.Cleanup()
+
+					_, err := os.Stat(socket)
+					h.AssertTrue(t, os.IsNotExist(err))
+				})
+			})
+		})
 	})
 
 	when("#Export", func() {
@@ -1116,7 +1581,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			fakePhase := &fakes.FakePhase{}
 			fakePhaseFactory := fakes.NewFakePhaseFactory(fakes.WhichReturnsForNew(fakePhase))
 
-			err := lifecycle.Export(context.Background(), "test", []string{}, "test", false, "test", "test", "test", fakePhaseFactory)
+			err := lifecycle.Export(context.Background(), "test", []string{}, "test", false, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 			h.AssertNil(t, err)
 
 			h.AssertEq(t, fakePhase.CleanupCallCount, 1)
@@ -1129,7 +1594,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			expectedRepoName := "some-repo-name"
 			expectedRunImage := "some-run-image"
 
-			err := verboseLifecycle.Export(context.Background(), expectedRepoName, []string{}, expectedRunImage, false, "test", "test", "test", fakePhaseFactory)
+			err := verboseLifecycle.Export(context.Background(), expectedRepoName, []string{}, expectedRunImage, false, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 			h.AssertNil(t, err)
 
 			lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1148,6 +1613,33 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			)
 		})
 
+		when("project metadata is provided", func() {
+			it("writes it for the exporter", func() {
+				lifecycleExec := newTestLifecycleExec(t, false, func(opts *build.LifecycleOptions) {
+					opts.ProjectMetadata = lifecycle.ProjectMetadata{Source: &lifecycle.ProjectSource{Type: "git"}}
+				})
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycleExec.Export(context.Background(), "test", []string{}, "test", false, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[len(fakePhaseFactory.NewCalledWithProvider)-1]
+				h.AssertSliceContains(t, configProvider.ContainerConfig().Env, "CNB_PROJECT_METADATA_PATH=/layers/project-metadata.toml")
+				h.AssertFunctionName(t, configProvider.ContainerOps()[len(configProvider.ContainerOps())-1], "WriteProjectMetadata")
+			})
+
+			it("is not written otherwise", func() {
+				lifecycleExec := newTestLifecycleExec(t, false)
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycleExec.Export(context.Background(), "test", []string{}, "test", false, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[len(fakePhaseFactory.NewCalledWithProvider)-1]
+				h.AssertEq(t, len(configProvider.ContainerOps()), 1)
+			})
+		})
+
 		when("additional tags are specified", func() {
 			it("passes tag arguments to the exporter", func() {
 				verboseLifecycle := newTestLifecycleExec(t, true)
@@ -1156,7 +1648,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				expectedRunImage := "some-run-image"
 				additionalTags := []string{"additional-tag-1", "additional-tag-2"}
 
-				err := verboseLifecycle.Export(context.Background(), expectedRepoName, additionalTags, expectedRunImage, false, "test", "test", "test", fakePhaseFactory)
+				err := verboseLifecycle.Export(context.Background(), expectedRepoName, additionalTags, expectedRunImage, false, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1183,7 +1675,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				})
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-				err := lifecycle.Export(context.Background(), "test", []string{}, "test", true, "test", "test", "test", fakePhaseFactory)
+				err := lifecycle.Export(context.Background(), "test", []string{}, "test", true, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1199,7 +1691,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				lifecycle := newTestLifecycleExec(t, false, fakes.WithBuilder(fakeBuilder))
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-				err = lifecycle.Export(context.Background(), "test", []string{}, "test", true, "test", "test", "test", fakePhaseFactory)
+				err = lifecycle.Export(context.Background(), "test", []string{}, "test", true, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1215,7 +1707,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 				expectedRepos := []string{"some-repo-name", "some-run-image"}
 
-				err := lifecycle.Export(context.Background(), expectedRepos[0], []string{}, expectedRepos[1], true, "test", "test", "test", fakePhaseFactory)
+				err := lifecycle.Export(context.Background(), expectedRepos[0], []string{}, expectedRepos[1], true, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1229,7 +1721,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				lifecycle := newTestLifecycleExec(t, false)
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-				err := lifecycle.Export(context.Background(), "test", []string{}, "test", true, "test", "test", "test", fakePhaseFactory)
+				err := lifecycle.Export(context.Background(), "test", []string{}, "test", true, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1244,7 +1736,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 				expectedNetworkMode := "some-network-mode"
 
-				err := lifecycle.Export(context.Background(), "test", []string{}, "test", true, "test", "test", expectedNetworkMode, fakePhaseFactory)
+				err := lifecycle.Export(context.Background(), "test", []string{}, "test", true, newFakeVolumeCache("test"), fakeBuildCache, expectedNetworkMode, fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1259,7 +1751,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 				expectedBind := "some-cache:/cache"
 
-				err := lifecycle.Export(context.Background(), "test", []string{}, "test", true, "test", "some-cache", "test", fakePhaseFactory)
+				err := lifecycle.Export(context.Background(), "test", []string{}, "test", true, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1274,7 +1766,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 				expectedBinds := []string{"some-cache:/cache", "some-launch-cache:/launch-cache"}
 
-				err := lifecycle.Export(context.Background(), "test", []string{}, "test", false, "some-launch-cache", "some-cache", "test", fakePhaseFactory)
+				err := lifecycle.Export(context.Background(), "test", []string{}, "test", false, newFakeVolumeCache("some-launch-cache"), fakeBuildCache, "test", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1294,7 +1786,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 				expectedDefaultProc := []string{"-process-type", "test-process"}
 
-				err := lifecycle.Export(context.Background(), "test", []string{}, "test", true, "test", "test", "test", fakePhaseFactory)
+				err := lifecycle.Export(context.Background(), "test", []string{}, "test", true, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1311,7 +1803,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 					lifecycle := newTestLifecycleExec(t, true, fakes.WithBuilder(fakeBuilder))
 					fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-					err = lifecycle.Export(context.Background(), "test", []string{}, "test", true, "test", "test", "test", fakePhaseFactory)
+					err = lifecycle.Export(context.Background(), "test", []string{}, "test", true, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 					h.AssertNil(t, err)
 
 					lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1329,7 +1821,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 					lifecycle := newTestLifecycleExec(t, true, fakes.WithBuilder(fakeBuilder))
 					fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-					err = lifecycle.Export(context.Background(), "test", []string{}, "test", true, "test", "test", "test", fakePhaseFactory)
+					err = lifecycle.Export(context.Background(), "test", []string{}, "test", true, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 					h.AssertNil(t, err)
 
 					lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1348,7 +1840,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				})
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-				err := lifecycle.Export(context.Background(), "test", []string{}, "test", false, "test", "test", "test", fakePhaseFactory)
+				err := lifecycle.Export(context.Background(), "test", []string{}, "test", false, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1364,7 +1856,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				lifecycle := newTestLifecycleExec(t, false, fakes.WithBuilder(fakeBuilder))
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-				err = lifecycle.Export(context.Background(), "test", []string{}, "test", false, "test", "test", "test", fakePhaseFactory)
+				err = lifecycle.Export(context.Background(), "test", []string{}, "test", false, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1379,7 +1871,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				lifecycle := newTestLifecycleExec(t, false)
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-				err := lifecycle.Export(context.Background(), "test", []string{}, "test", false, "test", "test", "test", fakePhaseFactory)
+				err := lifecycle.Export(context.Background(), "test", []string{}, "test", false, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1394,7 +1886,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				verboseLifecycle := newTestLifecycleExec(t, true)
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-				err := verboseLifecycle.Export(context.Background(), "test", []string{}, "test", false, "test", "test", "test", fakePhaseFactory)
+				err := verboseLifecycle.Export(context.Background(), "test", []string{}, "test", false, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1414,7 +1906,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 				expectedNetworkMode := "some-network-mode"
 
-				err := lifecycle.Export(context.Background(), "test", []string{}, "test", false, "test", "test", expectedNetworkMode, fakePhaseFactory)
+				err := lifecycle.Export(context.Background(), "test", []string{}, "test", false, newFakeVolumeCache("test"), fakeBuildCache, expectedNetworkMode, fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1429,7 +1921,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 				expectedBinds := []string{"some-cache:/cache", "some-launch-cache:/launch-cache"}
 
-				err := lifecycle.Export(context.Background(), "test", []string{}, "test", false, "some-launch-cache", "some-cache", "test", fakePhaseFactory)
+				err := lifecycle.Export(context.Background(), "test", []string{}, "test", false, newFakeVolumeCache("some-launch-cache"), fakeBuildCache, "test", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1439,12 +1931,33 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				h.AssertSliceContains(t, configProvider.HostConfig().Binds, expectedBinds...)
 			})
 
+			it("labels the cache volumes with the image they belong to", func() {
+				lifecycle := newTestLifecycleExec(t, false)
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+				imageRef, err := name.ParseReference("some/app", name.WeakValidation)
+				h.AssertNil(t, err)
+				buildCache := cache.NewVolumeCache(imageRef, "build", nil)
+				launchCache := cache.NewVolumeCache(imageRef, "launch", nil)
+
+				err = lifecycle.Export(context.Background(), "test", []string{}, "test", false, launchCache, buildCache, "test", fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
+				h.AssertNotEq(t, lastCallIndex, -1)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[lastCallIndex]
+				h.AssertEq(t, configProvider.VolumeLabels(), map[string]map[string]string{
+					buildCache.Name():  buildCache.Labels(),
+					launchCache.Name(): launchCache.Labels(),
+				})
+			})
+
 			it("configures the phase to write stack toml", func() {
 				lifecycle := newTestLifecycleExec(t, false)
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 				expectedBinds := []string{"some-cache:/cache", "some-launch-cache:/launch-cache"}
 
-				err := lifecycle.Export(context.Background(), "test", []string{}, "test", false, "some-launch-cache", "some-cache", "test", fakePhaseFactory)
+				err := lifecycle.Export(context.Background(), "test", []string{}, "test", false, newFakeVolumeCache("some-launch-cache"), fakeBuildCache, "test", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1464,7 +1977,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				fakePhaseFactory := fakes.NewFakePhaseFactory()
 				expectedDefaultProc := []string{"-process-type", "test-process"}
 
-				err := lifecycle.Export(context.Background(), "test", []string{}, "test", false, "test", "test", "test", fakePhaseFactory)
+				err := lifecycle.Export(context.Background(), "test", []string{}, "test", false, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 				h.AssertNil(t, err)
 
 				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1481,7 +1994,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 					lifecycle := newTestLifecycleExec(t, true, fakes.WithBuilder(fakeBuilder))
 					fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-					err = lifecycle.Export(context.Background(), "test", []string{}, "test", false, "test", "test", "test", fakePhaseFactory)
+					err = lifecycle.Export(context.Background(), "test", []string{}, "test", false, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 					h.AssertNil(t, err)
 
 					lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1499,7 +2012,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 					lifecycle := newTestLifecycleExec(t, true, fakes.WithBuilder(fakeBuilder))
 					fakePhaseFactory := fakes.NewFakePhaseFactory()
 
-					err = lifecycle.Export(context.Background(), "test", []string{}, "test", false, "test", "test", "test", fakePhaseFactory)
+					err = lifecycle.Export(context.Background(), "test", []string{}, "test", false, newFakeVolumeCache("test"), fakeBuildCache, "test", fakePhaseFactory)
 					h.AssertNil(t, err)
 
 					lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
@@ -1510,6 +2023,27 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 		})
+
+		when("using a cache image", func() {
+			it("configures the phase with the cache image and registry access", func() {
+				lifecycle := newTestLifecycleExec(t, false)
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				err := lifecycle.Export(context.Background(), "test", []string{}, "test", true, newFakeVolumeCache("test"), newFakeImageCache("some-cache-image"), "test", fakePhaseFactory)
+				h.AssertNil(t, err)
+
+				lastCallIndex := len(fakePhaseFactory.NewCalledWithProvider) - 1
+				h.AssertNotEq(t, lastCallIndex, -1)
+
+				configProvider := fakePhaseFactory.NewCalledWithProvider[lastCallIndex]
+				h.AssertIncludeAllExpectedPatterns(t,
+					configProvider.ContainerConfig().Cmd,
+					[]string{"-cache-image", "some-cache-image"},
+				)
+				h.AssertSliceNotContains(t, configProvider.ContainerConfig().Cmd, "-cache-dir")
+				h.AssertSliceNotContains(t, configProvider.HostConfig().Binds, "some-cache-image:/cache")
+			})
+		})
 	})
 }
 
@@ -1548,3 +2082,38 @@ func newTestLifecycleExec(t *testing.T, logVerbose bool, ops ...func(*build.Life
 	h.AssertNil(t, err)
 	return lifecycleExec
 }
+
+// newRootBuilder returns a builder whose user is root, to which SSH agents are mounted as they are.
+func newRootBuilder(t *testing.T) *fakes.FakeBuilder {
+	t.Helper()
+
+	fakeBuilder, err := fakes.NewFakeBuilder(fakes.WithUID(0), fakes.WithGID(0))
+	h.AssertNil(t, err)
+	return fakeBuilder
+}
+
+func newFakeVolumeCache(name string) *fakes.FakeCache {
+	c := fakes.NewFakeCache()
+	c.ReturnForType = cache.Volume
+	c.ReturnForName = name
+	return c
+}
+
+func newFakeImageCache(name string) *fakes.FakeCache {
+	c := fakes.NewFakeCache()
+	c.ReturnForType = cache.Image
+	c.ReturnForName = name
+	return c
+}
+
+// blockingPhase runs until its context is done, failing as a phase does when it times out.
+type blockingPhase struct{}
+
+func (p *blockingPhase) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return errors.New("timed out in phase 'creator'")
+}
+
+func (p *blockingPhase) Cleanup() error {
+	return nil
+}
@@ -6,11 +6,14 @@ import (
 	"time"
 
 	"github.com/buildpacks/imgutil"
+	"github.com/buildpacks/lifecycle"
 	"github.com/buildpacks/lifecycle/api"
 	"github.com/docker/docker/client"
 	"github.com/google/go-containerregistry/pkg/name"
 
+	"github.com/buildpacks/pack/events"
 	"github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/internal/cache"
 	"github.com/buildpacks/pack/logging"
 )
 
@@ -39,6 +42,7 @@ type LifecycleExecutor struct {
 type Cache interface {
 	Name() string
 	Clear(context.Context) error
+	Type() cache.Type
 }
 
 func init() {
@@ -52,6 +56,7 @@ type LifecycleOptions struct {
 	LifecycleImage     string
 	RunImage           string
 	ClearCache         bool
+	CacheImage         string
 	Publish            bool
 	TrustBuilder       bool
 	UseCreator         bool
@@ -63,6 +68,47 @@ type LifecycleOptions struct {
 	Volumes            []string
 	DefaultProcessType string
 	FileFilter         func(string) bool
+	EventHandler       events.Handler
+	ProjectMetadata    lifecycle.ProjectMetadata
+	Secrets            []Secret
+	SSHAgents          []SSHAgent
+	Resources          Resources
+	Timeout            time.Duration
+	PhaseTimeouts      map[string]time.Duration
+}
+
+// PhaseNames are the names of the lifecycle phases, which PhaseTimeouts are keyed by.
+var PhaseNames = []string{"detector", "analyzer", "restorer", "builder", "exporter", "creator"}
+
+// Resources limits the resources of the container of every phase, and configures how it resolves hosts.
+// Zero values leave the docker defaults in place.
+type Resources struct {
+	// Memory limit, in bytes.
+	Memory int64
+
+	// CPU quota, in units of 10^-9 CPUs.
+	NanoCPUs int64
+
+	// Maximum number of processes.
+	PidsLimit int64
+
+	// Additional /etc/hosts entries, in the form 'host:ip'.
+	ExtraHosts []string
+
+	// DNS servers.
+	DNS []string
+}
+
+// DefaultSSHAgentID identifies the SSH agent SSH_AUTH_SOCK points to on the host.
+const DefaultSSHAgentID = "default"
+
+// SSHAgent is an SSH agent socket on the host, forwarded to the phases running buildpacks.
+type SSHAgent struct {
+	// ID the socket is known by in the container, DefaultSSHAgentID for the agent SSH_AUTH_SOCK points to.
+	ID string
+
+	// Path to the socket on the host.
+	Socket string
 }
 
 func NewLifecycleExecutor(logger logging.Logger, docker client.CommonAPIClient) *LifecycleExecutor {
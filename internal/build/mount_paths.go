@@ -32,6 +32,10 @@ func (m mountPaths) stackPath() string {
 	return m.join(m.layersDir(), "stack.toml")
 }
 
+func (m mountPaths) projectPath() string {
+	return m.join(m.layersDir(), "project-metadata.toml")
+}
+
 func (m mountPaths) appDirName() string {
 	return "workspace"
 }
@@ -51,3 +55,11 @@ func (m mountPaths) launchCacheDir() string {
 func (m mountPaths) platformDir() string {
 	return m.join(m.volume, "platform")
 }
+
+func (m mountPaths) secretsDir() string {
+	return m.join(m.platformDir(), "secrets")
+}
+
+func (m mountPaths) sshDir() string {
+	return m.join(m.platformDir(), "ssh")
+}
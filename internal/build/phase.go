@@ -3,13 +3,17 @@ package build
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 	"github.com/pkg/errors"
 
+	"github.com/buildpacks/pack/events"
 	"github.com/buildpacks/pack/internal/container"
+	"github.com/buildpacks/pack/internal/style"
 )
 
 type Phase struct {
@@ -23,15 +27,53 @@ type Phase struct {
 	uid, gid     int
 	appPath      string
 	containerOps []ContainerOperation
+	postRunOps   []ContainerOperation
 	fileFilter   func(string) bool
+	handler      events.Handler
+	volumeLabels map[string]map[string]string
+	timeout      time.Duration
 }
 
 func (p *Phase) Run(ctx context.Context) error {
+	phaseCtx := ctx
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		phaseCtx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	err := p.run(phaseCtx)
+	if err == nil || phaseCtx.Err() != context.DeadlineExceeded {
+		return err
+	}
+
+	// the container keeps running once the context is done
+	if p.ctr.ID != "" {
+		if err := p.docker.ContainerKill(context.Background(), p.ctr.ID, "KILL"); err != nil {
+			return errors.Wrapf(err, "failed to kill '%s' container after it timed out", p.name)
+		}
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return errors.Errorf("timed out in phase %s", style.Symbol(p.name))
+	}
+	return errors.Errorf("phase %s timed out after %s", style.Symbol(p.name), p.timeout)
+}
+
+func (p *Phase) run(ctx context.Context) error {
+	// create labelled volumes ahead of the container, as docker would otherwise create them without labels
+	for name, labels := range p.volumeLabels {
+		if _, err := p.docker.VolumeCreate(ctx, volume.VolumeCreateBody{Name: name, Labels: labels}); err != nil {
+			return errors.Wrapf(err, "failed to create volume '%s'", name)
+		}
+	}
+
 	var err error
 	p.ctr, err = p.docker.ContainerCreate(ctx, p.ctrConf, p.hostConf, nil, nil, "")
 	if err != nil {
 		return errors.Wrapf(err, "failed to create '%s' container", p.name)
 	}
+	p.handler(events.ContainerCreated{Phase: p.name, ContainerID: p.ctr.ID})
 
 	for _, containerOp := range p.containerOps {
 		if err := containerOp(p.docker, ctx, p.ctr.ID, p.infoWriter, p.errorWriter); err != nil {
@@ -39,15 +81,39 @@ func (p *Phase) Run(ctx context.Context) error {
 		}
 	}
 
-	return container.Run(
+	err = container.Run(
 		ctx,
 		p.docker,
 		p.ctr.ID,
 		p.infoWriter,
 		p.errorWriter,
 	)
+	// report the last line of output when it has no trailing newline, as error messages often do
+	closeWriter(p.infoWriter)
+	closeWriter(p.errorWriter)
+	if err != nil {
+		return err
+	}
+
+	for _, postRunOp := range p.postRunOps {
+		if err := postRunOp(p.docker, ctx, p.ctr.ID, p.infoWriter, p.errorWriter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func closeWriter(w io.Writer) {
+	if closer, ok := w.(io.Closer); ok {
+		closer.Close()
+	}
 }
 
 func (p *Phase) Cleanup() error {
-	return p.docker.ContainerRemove(context.Background(), p.ctr.ID, types.ContainerRemoveOptions{Force: true})
+	if err := p.docker.ContainerRemove(context.Background(), p.ctr.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+		return err
+	}
+
+	p.handler(events.ContainerRemoved{Phase: p.name, ContainerID: p.ctr.ID})
+	return nil
 }
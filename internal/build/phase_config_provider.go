@@ -3,6 +3,7 @@ package build
 import (
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/docker/docker/api/types/container"
 
@@ -23,18 +24,23 @@ type PhaseConfigProvider struct {
 	name         string
 	os           string
 	containerOps []ContainerOperation
+	postRunOps   []ContainerOperation
 	infoWriter   io.Writer
 	errorWriter  io.Writer
+	masks        []string
+	volumeLabels map[string]map[string]string
+	timeout      time.Duration
 }
 
 func NewPhaseConfigProvider(name string, lifecycleExec *LifecycleExecution, ops ...PhaseConfigProviderOperation) *PhaseConfigProvider {
 	provider := &PhaseConfigProvider{
-		ctrConf:     new(container.Config),
-		hostConf:    new(container.HostConfig),
-		name:        name,
-		os:          lifecycleExec.os,
-		infoWriter:  logging.GetWriterForLevel(lifecycleExec.logger, logging.InfoLevel),
-		errorWriter: logging.GetWriterForLevel(lifecycleExec.logger, logging.ErrorLevel),
+		ctrConf:      new(container.Config),
+		hostConf:     new(container.HostConfig),
+		name:         name,
+		os:           lifecycleExec.os,
+		infoWriter:   logging.GetWriterForLevel(lifecycleExec.logger, logging.InfoLevel),
+		errorWriter:  logging.GetWriterForLevel(lifecycleExec.logger, logging.ErrorLevel),
+		volumeLabels: map[string]map[string]string{},
 	}
 
 	provider.ctrConf.Image = lifecycleExec.opts.Builder.Name()
@@ -47,6 +53,8 @@ func NewPhaseConfigProvider(name string, lifecycleExec *LifecycleExecution, ops
 	ops = append(ops,
 		WithEnv(fmt.Sprintf("%s=%s", platformAPIEnvVar, lifecycleExec.platformAPI.String())),
 		WithLifecycleProxy(lifecycleExec),
+		WithResources(lifecycleExec.opts.Resources),
+		WithTimeout(lifecycleExec.opts.PhaseTimeouts[name]),
 		WithBinds([]string{
 			fmt.Sprintf("%s:%s", lifecycleExec.layersVolume, lifecycleExec.mountPaths.layersDir()),
 			fmt.Sprintf("%s:%s", lifecycleExec.appVolume, lifecycleExec.mountPaths.appDir()),
@@ -70,6 +78,11 @@ func (p *PhaseConfigProvider) ContainerOps() []ContainerOperation {
 	return p.containerOps
 }
 
+// PostRunOps returns the operations on the container once the phase has run successfully.
+func (p *PhaseConfigProvider) PostRunOps() []ContainerOperation {
+	return p.postRunOps
+}
+
 func (p *PhaseConfigProvider) HostConfig() *container.HostConfig {
 	return p.hostConf
 }
@@ -78,6 +91,16 @@ func (p *PhaseConfigProvider) Name() string {
 	return p.name
 }
 
+// VolumeLabels returns the labels to create named volumes with, keyed by volume name.
+func (p *PhaseConfigProvider) VolumeLabels() map[string]map[string]string {
+	return p.volumeLabels
+}
+
+// Timeout returns how long the phase may run, or zero if it may run until the build times out.
+func (p *PhaseConfigProvider) Timeout() time.Duration {
+	return p.timeout
+}
+
 func (p *PhaseConfigProvider) ErrorWriter() io.Writer {
 	return p.errorWriter
 }
@@ -105,6 +128,13 @@ func WithBinds(binds ...string) PhaseConfigProviderOperation {
 	}
 }
 
+// WithVolumeLabels sets the labels a named volume is created with, if it does not already exist.
+func WithVolumeLabels(volume string, labels map[string]string) PhaseConfigProviderOperation {
+	return func(provider *PhaseConfigProvider) {
+		provider.volumeLabels[volume] = labels
+	}
+}
+
 func WithDaemonAccess() PhaseConfigProviderOperation {
 	return func(provider *PhaseConfigProvider) {
 		WithRoot()(provider)
@@ -163,6 +193,27 @@ func WithNetwork(networkMode string) PhaseConfigProviderOperation {
 	}
 }
 
+// WithResources limits the resources of the container, and configures how it resolves hosts.
+func WithResources(resources Resources) PhaseConfigProviderOperation {
+	return func(provider *PhaseConfigProvider) {
+		provider.hostConf.Memory = resources.Memory
+		provider.hostConf.NanoCPUs = resources.NanoCPUs
+		if resources.PidsLimit != 0 {
+			pidsLimit := resources.PidsLimit
+			provider.hostConf.PidsLimit = &pidsLimit
+		}
+		provider.hostConf.ExtraHosts = append(provider.hostConf.ExtraHosts, resources.ExtraHosts...)
+		provider.hostConf.DNS = append(provider.hostConf.DNS, resources.DNS...)
+	}
+}
+
+// WithTimeout sets how long the phase may run before its container is killed. Zero means no limit.
+func WithTimeout(timeout time.Duration) PhaseConfigProviderOperation {
+	return func(provider *PhaseConfigProvider) {
+		provider.timeout = timeout
+	}
+}
+
 func WithRegistryAccess(authConfig string) PhaseConfigProviderOperation {
 	return func(provider *PhaseConfigProvider) {
 		provider.ctrConf.Env = append(provider.ctrConf.Env, fmt.Sprintf(`CNB_REGISTRY_AUTH=%s`, authConfig))
@@ -184,3 +235,11 @@ func WithContainerOperations(operations ...ContainerOperation) PhaseConfigProvid
 		provider.containerOps = append(provider.containerOps, operations...)
 	}
 }
+
+// WithPostRunOperations adds operations on the container once the phase has run successfully,
+// such as copying its output out of the container.
+func WithPostRunOperations(operations ...ContainerOperation) PhaseConfigProviderOperation {
+	return func(provider *PhaseConfigProvider) {
+		provider.postRunOps = append(provider.postRunOps, operations...)
+	}
+}
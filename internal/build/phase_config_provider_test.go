@@ -122,6 +122,21 @@ func testPhaseConfigProvider(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("called with WithVolumeLabels", func() {
+			it("sets the labels for the volume", func() {
+				lifecycle := newTestLifecycleExec(t, false)
+				expectedLabels := map[string]string{"some-label": "some-value"}
+
+				phaseConfigProvider := build.NewPhaseConfigProvider(
+					"some-name",
+					lifecycle,
+					build.WithVolumeLabels("some-volume", expectedLabels),
+				)
+
+				h.AssertEq(t, phaseConfigProvider.VolumeLabels(), map[string]map[string]string{"some-volume": expectedLabels})
+			})
+		})
+
 		when("called with WithDaemonAccess", func() {
 			when("building for non-Windows", func() {
 				it("sets daemon access on the config", func() {
@@ -205,6 +220,41 @@ func testPhaseConfigProvider(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("the lifecycle has resources and phase timeouts", func() {
+			it("applies them to every phase", func() {
+				lifecycle := newTestLifecycleExec(t, false, func(opts *build.LifecycleOptions) {
+					opts.Resources = build.Resources{
+						Memory:     1 << 30,
+						NanoCPUs:   1500000000,
+						PidsLimit:  100,
+						ExtraHosts: []string{"some-host:10.0.0.1"},
+						DNS:        []string{"10.0.0.2"},
+					}
+					opts.PhaseTimeouts = map[string]time.Duration{"builder": time.Minute}
+				})
+
+				builder := build.NewPhaseConfigProvider("builder", lifecycle)
+				h.AssertEq(t, builder.HostConfig().Memory, int64(1<<30))
+				h.AssertEq(t, builder.HostConfig().NanoCPUs, int64(1500000000))
+				h.AssertEq(t, *builder.HostConfig().PidsLimit, int64(100))
+				h.AssertEq(t, builder.HostConfig().ExtraHosts, []string{"some-host:10.0.0.1"})
+				h.AssertEq(t, builder.HostConfig().DNS, []string{"10.0.0.2"})
+				h.AssertEq(t, builder.Timeout(), time.Minute)
+
+				detector := build.NewPhaseConfigProvider("detector", lifecycle)
+				h.AssertEq(t, detector.HostConfig().Memory, int64(1<<30))
+				h.AssertEq(t, detector.Timeout(), time.Duration(0))
+			})
+
+			it("leaves the docker defaults in place otherwise", func() {
+				phaseConfigProvider := build.NewPhaseConfigProvider("builder", newTestLifecycleExec(t, false))
+
+				h.AssertEq(t, phaseConfigProvider.HostConfig().Memory, int64(0))
+				h.AssertNil(t, phaseConfigProvider.HostConfig().PidsLimit)
+				h.AssertEq(t, phaseConfigProvider.Timeout(), time.Duration(0))
+			})
+		})
+
 		when("called with WithRegistryAccess", func() {
 			it("sets registry access on the config", func() {
 				lifecycle := newTestLifecycleExec(t, false)
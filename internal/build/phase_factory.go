@@ -1,6 +1,11 @@
 package build
 
-import "context"
+import (
+	"context"
+	"io"
+
+	"github.com/buildpacks/pack/events"
+)
 
 type RunnerCleaner interface {
 	Run(ctx context.Context) error
@@ -22,17 +27,31 @@ func NewDefaultPhaseFactory(lifecycleExec *LifecycleExecution) PhaseFactory {
 }
 
 func (m *DefaultPhaseFactory) New(provider *PhaseConfigProvider) RunnerCleaner {
+	handler := m.lifecycleExec.opts.EventHandler
+
+	// secrets are masked ahead of reporting output as events, so that neither sees them
+	var infoWriter io.Writer = newEventWriter(provider.InfoWriter(), handler, provider.Name(), events.Stdout)
+	var errorWriter io.Writer = newEventWriter(provider.ErrorWriter(), handler, provider.Name(), events.Stderr)
+	if len(provider.masks) > 0 {
+		infoWriter = newMaskingWriter(infoWriter, provider.masks)
+		errorWriter = newMaskingWriter(errorWriter, provider.masks)
+	}
+
 	return &Phase{
 		ctrConf:      provider.ContainerConfig(),
 		hostConf:     provider.HostConfig(),
 		name:         provider.Name(),
 		docker:       m.lifecycleExec.docker,
-		infoWriter:   provider.InfoWriter(),
-		errorWriter:  provider.ErrorWriter(),
+		infoWriter:   infoWriter,
+		errorWriter:  errorWriter,
 		uid:          m.lifecycleExec.opts.Builder.UID(),
 		gid:          m.lifecycleExec.opts.Builder.GID(),
 		appPath:      m.lifecycleExec.opts.AppPath,
 		containerOps: provider.containerOps,
+		postRunOps:   provider.PostRunOps(),
 		fileFilter:   m.lifecycleExec.opts.FileFilter,
+		handler:      handler,
+		volumeLabels: provider.VolumeLabels(),
+		timeout:      provider.Timeout(),
 	}
 }
@@ -1,11 +1,13 @@
 package build_test
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"fmt"
 	"io/ioutil"
 	"math/rand"
+	"net"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -16,19 +18,25 @@ import (
 
 	"github.com/buildpacks/imgutil/local"
 	"github.com/buildpacks/lifecycle/auth"
+	"github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/golang/mock/gomock"
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/heroku/color"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
+	"github.com/buildpacks/pack/events"
 	"github.com/buildpacks/pack/internal/archive"
 	"github.com/buildpacks/pack/internal/build"
 	"github.com/buildpacks/pack/internal/build/fakes"
 	ilogging "github.com/buildpacks/pack/internal/logging"
 	"github.com/buildpacks/pack/logging"
 	h "github.com/buildpacks/pack/testhelpers"
+	"github.com/buildpacks/pack/testmocks"
 )
 
 const phaseName = "phase"
@@ -422,3 +430,190 @@ func CreateFakeLifecycleExecution(logger logging.Logger, docker client.CommonAPI
 		NoProxy:    "some-no-proxy",
 	})
 }
+
+func TestPhaseTimeout(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "phase timeout", testPhaseTimeout, spec.Report(report.Terminal{}), spec.Parallel())
+}
+
+func testPhaseTimeout(t *testing.T, when spec.G, it spec.S) {
+	var (
+		mockController *gomock.Controller
+		mockDocker     *testmocks.MockCommonAPIClient
+		conn           net.Conn
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockDocker = testmocks.NewMockCommonAPIClient(mockController)
+
+		var server net.Conn
+		conn, server = net.Pipe()
+		it.After(func() { server.Close() })
+
+		// the container runs until the context it is waited on with is done
+		mockDocker.EXPECT().ContainerCreate(gomock.Any(), gomock.Any(), gomock.Any(), nil, nil, "").
+			Return(dcontainer.ContainerCreateCreatedBody{ID: "some-container-id"}, nil)
+		mockDocker.EXPECT().ContainerWait(gomock.Any(), "some-container-id", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ string, _ dcontainer.WaitCondition) (<-chan dcontainer.ContainerWaitOKBody, <-chan error) {
+				errChan := make(chan error, 1)
+				go func() {
+					<-ctx.Done()
+					errChan <- ctx.Err()
+				}()
+				return make(chan dcontainer.ContainerWaitOKBody), errChan
+			})
+		mockDocker.EXPECT().ContainerAttach(gomock.Any(), "some-container-id", gomock.Any()).
+			Return(types.HijackedResponse{Conn: conn, Reader: bufio.NewReader(conn)}, nil)
+		mockDocker.EXPECT().ContainerStart(gomock.Any(), "some-container-id", gomock.Any()).Return(nil)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	newPhase := func(ops ...func(*build.LifecycleOptions)) build.RunnerCleaner {
+		fakeBuilder, err := fakes.NewFakeBuilder()
+		h.AssertNil(t, err)
+
+		opts := build.LifecycleOptions{Builder: fakeBuilder}
+		for _, op := range ops {
+			op(&opts)
+		}
+
+		var outBuf bytes.Buffer
+		lifecycleExec, err := build.NewLifecycleExecution(ilogging.NewLogWithWriters(&outBuf, &outBuf), mockDocker, opts)
+		h.AssertNil(t, err)
+
+		return build.NewDefaultPhaseFactory(lifecycleExec).New(build.NewPhaseConfigProvider("builder", lifecycleExec))
+	}
+
+	it("kills the container and names the phase when the phase times out", func() {
+		phase := newPhase(func(opts *build.LifecycleOptions) {
+			opts.PhaseTimeouts = map[string]time.Duration{"builder": 10 * time.Millisecond}
+		})
+		mockDocker.EXPECT().ContainerKill(gomock.Any(), "some-container-id", "KILL").Return(nil)
+
+		err := phase.Run(context.Background())
+		h.AssertError(t, err, "phase 'builder' timed out after 10ms")
+	})
+
+	it("kills the container and names the phase when the build times out", func() {
+		phase := newPhase()
+		mockDocker.EXPECT().ContainerKill(gomock.Any(), "some-container-id", "KILL").Return(nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := phase.Run(ctx)
+		h.AssertError(t, err, "timed out in phase 'builder'")
+	})
+}
+
+func TestPhaseOutput(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "phase output", testPhaseOutput, spec.Report(report.Terminal{}), spec.Parallel())
+}
+
+func testPhaseOutput(t *testing.T, when spec.G, it spec.S) {
+	var (
+		mockController *gomock.Controller
+		mockDocker     *testmocks.MockCommonAPIClient
+		fakeBuilder    *fakes.FakeBuilder
+		received       []events.Event
+		handler        events.Handler
+	)
+
+	// expectContainer has the container write stdout and stderr, then exit with the status code before its output has been read
+	expectContainer := func(stdout, stderr string, statusCode int64) {
+		conn, server := net.Pipe()
+		go func() {
+			defer server.Close()
+			stdcopy.NewStdWriter(server, stdcopy.Stdout).Write([]byte(stdout))
+			stdcopy.NewStdWriter(server, stdcopy.Stderr).Write([]byte(stderr))
+		}()
+
+		mockDocker.EXPECT().ContainerCreate(gomock.Any(), gomock.Any(), gomock.Any(), nil, nil, "").
+			Return(dcontainer.ContainerCreateCreatedBody{ID: "some-container-id"}, nil)
+		mockDocker.EXPECT().ContainerWait(gomock.Any(), "some-container-id", gomock.Any()).
+			DoAndReturn(func(context.Context, string, dcontainer.WaitCondition) (<-chan dcontainer.ContainerWaitOKBody, <-chan error) {
+				bodyChan := make(chan dcontainer.ContainerWaitOKBody, 1)
+				bodyChan <- dcontainer.ContainerWaitOKBody{StatusCode: statusCode}
+				return bodyChan, make(chan error)
+			})
+		mockDocker.EXPECT().ContainerAttach(gomock.Any(), "some-container-id", gomock.Any()).
+			Return(types.HijackedResponse{Conn: conn, Reader: bufio.NewReader(conn)}, nil)
+		mockDocker.EXPECT().ContainerStart(gomock.Any(), "some-container-id", gomock.Any()).Return(nil)
+	}
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockDocker = testmocks.NewMockCommonAPIClient(mockController)
+
+		var err error
+		fakeBuilder, err = fakes.NewFakeBuilder()
+		h.AssertNil(t, err)
+
+		handler = func(e events.Event) {
+			if _, ok := e.(events.PhaseLog); ok {
+				received = append(received, e)
+			}
+		}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	it("reports every line of output, including a last line without a newline, when the phase fails", func() {
+		expectContainer("some output\n", "some error\nlast line", 1)
+
+		var outBuf bytes.Buffer
+		lifecycleExec, err := build.NewLifecycleExecution(ilogging.NewLogWithWriters(&outBuf, &outBuf), mockDocker, build.LifecycleOptions{
+			Builder:      fakeBuilder,
+			EventHandler: handler,
+		})
+		h.AssertNil(t, err)
+
+		phase := build.NewDefaultPhaseFactory(lifecycleExec).New(build.NewPhaseConfigProvider("builder", lifecycleExec))
+		err = phase.Run(context.Background())
+		h.AssertError(t, err, "failed with status code: 1")
+
+		h.AssertEq(t, received, []events.Event{
+			events.PhaseLog{Phase: "builder", Stream: events.Stdout, Line: "some output"},
+			events.PhaseLog{Phase: "builder", Stream: events.Stderr, Line: "some error"},
+			events.PhaseLog{Phase: "builder", Stream: events.Stderr, Line: "last line"},
+		})
+	})
+
+	when("secrets are provided", func() {
+		// copying the secrets into the container would be an unexpected call to CopyToContainer
+		it("mounts them rather than copying them into the container, and masks them in the output and events", func() {
+			expectContainer("using some-secret-value\n", "failed with some-secret-value", 1)
+			mockDocker.EXPECT().ContainerRemove(gomock.Any(), "some-container-id", gomock.Any()).Return(nil)
+			mockDocker.EXPECT().VolumeRemove(gomock.Any(), gomock.Any(), true).Return(nil).AnyTimes()
+
+			var outBuf bytes.Buffer
+			lifecycleExec, err := build.NewLifecycleExecution(ilogging.NewLogWithWriters(&outBuf, &outBuf), mockDocker, build.LifecycleOptions{
+				Builder:      fakeBuilder,
+				EventHandler: handler,
+				Secrets:      []build.Secret{{ID: "some-secret", Value: []byte("some-secret-value")}},
+			})
+			h.AssertNil(t, err)
+			defer lifecycleExec.Cleanup()
+
+			err = lifecycleExec.Build(context.Background(), "", nil, build.NewDefaultPhaseFactory(lifecycleExec))
+			h.AssertError(t, err, "failed with status code: 1")
+
+			h.AssertNotContains(t, outBuf.String(), "some-secret-value")
+			h.AssertEq(t, len(received), 2)
+			for _, e := range received {
+				h.AssertNotContains(t, e.(events.PhaseLog).Line, "some-secret-value")
+			}
+		})
+	})
+}
@@ -0,0 +1,137 @@
+package build
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// secretMask replaces the values of secrets in the output of a phase.
+const secretMask = "****"
+
+// minMaskedLineLength is the length below which a line of a multi-line secret is not masked on its own,
+// as short lines such as braces would otherwise mask unrelated output.
+const minMaskedLineLength = 8
+
+// Secret is made available to buildpacks as the file /platform/secrets/<ID> during the build phase only.
+type Secret struct {
+	ID    string
+	Value []byte
+}
+
+// writeSecrets writes secrets under a new temporary directory, from which they are mounted into the build phase.
+// The secrets are readable by any user, for the builder user to read them whatever its uid,
+// while the temporary directory, only accessible to the current user, keeps other users on the host away from them.
+func writeSecrets(secrets []Secret) (string, error) {
+	tmpDir, err := ioutil.TempDir("", "pack.secrets.")
+	if err != nil {
+		return "", errors.Wrap(err, "creating secrets dir")
+	}
+
+	secretsDir := filepath.Join(tmpDir, "secrets")
+	if err := os.Mkdir(secretsDir, 0755); err != nil {
+		os.RemoveAll(tmpDir)
+		return "", errors.Wrap(err, "creating secrets dir")
+	}
+	if err := os.Chmod(secretsDir, 0755); err != nil {
+		os.RemoveAll(tmpDir)
+		return "", errors.Wrap(err, "creating secrets dir")
+	}
+
+	for _, secret := range secrets {
+		path := filepath.Join(secretsDir, secret.ID)
+		if err := ioutil.WriteFile(path, secret.Value, 0644); err != nil {
+			os.RemoveAll(tmpDir)
+			return "", errors.Wrapf(err, "writing secret %s", style.Symbol(secret.ID))
+		}
+		if err := os.Chmod(path, 0644); err != nil {
+			os.RemoveAll(tmpDir)
+			return "", errors.Wrapf(err, "writing secret %s", style.Symbol(secret.ID))
+		}
+	}
+
+	return tmpDir, nil
+}
+
+// secretValues returns the values to mask in the output of a phase: each secret,
+// and each distinctive line of a secret spanning multiple lines, leaving out short lines
+// and the boundaries of PEM blocks, which are not secret.
+func secretValues(secrets []Secret) []string {
+	var values []string
+	for _, secret := range secrets {
+		value := strings.TrimSpace(string(secret.Value))
+		if value == "" {
+			continue
+		}
+		values = append(values, value)
+
+		for _, line := range strings.Split(value, "\n") {
+			line = strings.TrimSpace(line)
+			if len(line) >= minMaskedLineLength && line != value && !strings.HasPrefix(line, "-----") {
+				values = append(values, line)
+			}
+		}
+	}
+	return values
+}
+
+// maskingWriter replaces secret values with a mask before writing each line to out.
+// Lines are buffered until complete, so that values split across writes are masked as well.
+// Close writes the last line if it is incomplete.
+type maskingWriter struct {
+	out      io.Writer
+	replacer *strings.Replacer
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func newMaskingWriter(out io.Writer, values []string) *maskingWriter {
+	var oldnew []string
+	for _, value := range values {
+		oldnew = append(oldnew, value, secretMask)
+	}
+	return &maskingWriter{out: out, replacer: strings.NewReplacer(oldnew...)}
+}
+
+func (w *maskingWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf.Write(data)
+
+	if i := bytes.LastIndexByte(w.buf.Bytes(), '\n'); i >= 0 {
+		lines := string(w.buf.Next(i + 1))
+		if _, err := io.WriteString(w.out, w.replacer.Replace(lines)); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(data), nil
+}
+
+func (w *maskingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.buf.Len() > 0 {
+		line := w.buf.String()
+		w.buf.Reset()
+		if _, err := io.WriteString(w.out, w.replacer.Replace(line)); err != nil {
+			return err
+		}
+	}
+
+	if closer, ok := w.out.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package build
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/logging"
+)
+
+// sshAgentProxy forwards the connections made to its sockets to the SSH agents on the host.
+//
+// An agent socket is typically only accessible to the user running pack, so a builder user with
+// another uid could not connect to it once mounted. The sockets of the proxy can be connected to
+// by any user, and are kept away from other users on the host by the directory they are in.
+type sshAgentProxy struct {
+	dir       string
+	listeners []net.Listener
+}
+
+// proxySSHAgents starts forwarding to each agent from a socket in a new temporary directory,
+// returning the agents to mount in place of the ones given.
+func proxySSHAgents(logger logging.Logger, agents []SSHAgent) (*sshAgentProxy, []SSHAgent, error) {
+	dir, err := ioutil.TempDir("", "pack.ssh.")
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "creating SSH agents dir")
+	}
+
+	proxy := &sshAgentProxy{dir: dir}
+	var proxied []SSHAgent
+	for _, agent := range agents {
+		socket := filepath.Join(dir, agent.ID)
+		listener, err := net.Listen("unix", socket)
+		if err != nil {
+			proxy.Close()
+			return nil, nil, errors.Wrapf(err, "forwarding SSH agent %s", style.Symbol(agent.ID))
+		}
+		proxy.listeners = append(proxy.listeners, listener)
+
+		if err := os.Chmod(socket, 0666); err != nil {
+			proxy.Close()
+			return nil, nil, errors.Wrapf(err, "forwarding SSH agent %s", style.Symbol(agent.ID))
+		}
+
+		go forwardSSHAgent(logger, listener, agent.Socket)
+		proxied = append(proxied, SSHAgent{ID: agent.ID, Socket: socket})
+	}
+	return proxy, proxied, nil
+}
+
+// Close stops forwarding and removes the sockets of the proxy.
+func (p *sshAgentProxy) Close() error {
+	for _, listener := range p.listeners {
+		listener.Close()
+	}
+	return os.RemoveAll(p.dir)
+}
+
+func forwardSSHAgent(logger logging.Logger, listener net.Listener, socket string) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer conn.Close()
+
+			agent, err := net.Dial("unix", socket)
+			if err != nil {
+				logger.Warnf("Unable to connect to SSH agent %s: %s", style.Symbol(socket), err)
+				return
+			}
+			defer agent.Close()
+
+			done := make(chan struct{}, 2)
+			go func() { io.Copy(agent, conn); done <- struct{}{} }()
+			go func() { io.Copy(conn, agent); done <- struct{}{} }()
+			<-done
+		}()
+	}
+}
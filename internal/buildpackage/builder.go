@@ -12,13 +12,13 @@ import (
 	"github.com/buildpacks/imgutil"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/empty"
-	"github.com/google/go-containerregistry/pkg/v1/layout"
 	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/pkg/errors"
 
 	"github.com/buildpacks/pack/internal/archive"
 	"github.com/buildpacks/pack/internal/dist"
+	"github.com/buildpacks/pack/internal/image"
 	"github.com/buildpacks/pack/internal/stack"
 	"github.com/buildpacks/pack/internal/style"
 )
@@ -228,13 +228,8 @@ func (b *PackageBuilder) SaveAsFile(path, imageOS string) error {
 		return errors.Wrap(err, "creating oci-layout temp dir")
 	}
 
-	p, err := layout.Write(layoutDir, empty.Index)
-	if err != nil {
-		return errors.Wrap(err, "writing index")
-	}
-
-	if err := p.AppendImage(layoutImage); err != nil {
-		return errors.Wrap(err, "writing layout")
+	if err := image.WriteLayout(layoutDir, layoutImage); err != nil {
+		return err
 	}
 
 	outputFile, err := os.Create(path)
@@ -0,0 +1,21 @@
+package cache
+
+// Type identifies where a cache is stored.
+type Type int
+
+const (
+	// Image is a cache stored as an image in a registry.
+	Image Type = iota
+	// Volume is a cache stored in a docker volume.
+	Volume
+)
+
+const (
+	// ImageLabel is set on cache volumes to the name of the app image they were created for.
+	ImageLabel = "io.buildpacks.pack.cache.image"
+	// KindLabel is set on cache volumes to the kind of cache they hold, e.g. 'build' or 'launch'.
+	KindLabel = "io.buildpacks.pack.cache.kind"
+
+	// VolumePrefix is the prefix of the name of every cache volume.
+	VolumePrefix = "pack-cache-"
+)
@@ -97,6 +97,15 @@ func testImageCache(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("#Type", func() {
+		it("returns the cache type", func() {
+			ref, err := name.ParseReference("my/repo", name.WeakValidation)
+			h.AssertNil(t, err)
+			subject := cache.NewImageCache(ref, nil)
+			h.AssertEq(t, subject.Type(), cache.Image)
+		})
+	})
+
 	when("#Clear", func() {
 		var (
 			imageName    string
@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// RecordUse notes that a cache volume, or another resource, was used by a build,
+// by touching a file named after it in dir.
+func RecordUse(dir, volume string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, volume)
+	now := time.Now()
+	err := os.Chtimes(path, now, now)
+	if !os.IsNotExist(err) {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// LastUse returns when a cache volume was last used by a build,
+// or the zero time if no use was recorded.
+func LastUse(dir, volume string) (time.Time, error) {
+	info, err := os.Stat(filepath.Join(dir, volume))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+// ForgetUse removes the record of a cache volume being used.
+func ForgetUse(dir, volume string) error {
+	err := os.Remove(filepath.Join(dir, volume))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
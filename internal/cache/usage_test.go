@@ -0,0 +1,76 @@
+package cache_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/cache"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestUsage(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "Usage", testUsage, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testUsage(t *testing.T, when spec.G, it spec.S) {
+	var dir string
+
+	it.Before(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "cache-usage")
+		h.AssertNil(t, err)
+		dir = filepath.Join(dir, "usage")
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(filepath.Dir(dir)))
+	})
+
+	when("#LastUse", func() {
+		it("returns the zero time when no use was recorded", func() {
+			lastUse, err := cache.LastUse(dir, "some-volume")
+			h.AssertNil(t, err)
+			h.AssertEq(t, lastUse.IsZero(), true)
+		})
+
+		it("returns the time of the most recent use", func() {
+			h.AssertNil(t, cache.RecordUse(dir, "some-volume"))
+			h.AssertNil(t, os.Chtimes(filepath.Join(dir, "some-volume"), time.Unix(0, 0), time.Unix(0, 0)))
+
+			before := time.Now().Add(-time.Second)
+			h.AssertNil(t, cache.RecordUse(dir, "some-volume"))
+
+			lastUse, err := cache.LastUse(dir, "some-volume")
+			h.AssertNil(t, err)
+			if lastUse.Before(before) {
+				t.Fatalf("expected last use %s to be after %s", lastUse, before)
+			}
+		})
+	})
+
+	when("#ForgetUse", func() {
+		it("removes the record of use", func() {
+			h.AssertNil(t, cache.RecordUse(dir, "some-volume"))
+
+			h.AssertNil(t, cache.ForgetUse(dir, "some-volume"))
+
+			lastUse, err := cache.LastUse(dir, "some-volume")
+			h.AssertNil(t, err)
+			h.AssertEq(t, lastUse.IsZero(), true)
+		})
+
+		it("succeeds when no use was recorded", func() {
+			h.AssertNil(t, cache.ForgetUse(dir, "some-volume"))
+		})
+	})
+}
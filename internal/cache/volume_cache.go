@@ -14,6 +14,7 @@ import (
 type VolumeCache struct {
 	docker client.CommonAPIClient
 	volume string
+	labels map[string]string
 }
 
 func NewVolumeCache(imageRef name.Reference, suffix string, dockerClient client.CommonAPIClient) *VolumeCache {
@@ -21,8 +22,12 @@ func NewVolumeCache(imageRef name.Reference, suffix string, dockerClient client.
 
 	vol := paths.FilterReservedNames(fmt.Sprintf("%x", sum[:6]))
 	return &VolumeCache{
-		volume: fmt.Sprintf("pack-cache-%s.%s", vol, suffix),
+		volume: fmt.Sprintf("%s%s.%s", VolumePrefix, vol, suffix),
 		docker: dockerClient,
+		labels: map[string]string{
+			ImageLabel: imageRef.Name(),
+			KindLabel:  suffix,
+		},
 	}
 }
 
@@ -30,6 +35,15 @@ func (c *VolumeCache) Name() string {
 	return c.volume
 }
 
+// Labels returns the labels the volume should be created with.
+func (c *VolumeCache) Labels() map[string]string {
+	return c.labels
+}
+
+func (c *VolumeCache) Type() Type {
+	return Volume
+}
+
 func (c *VolumeCache) Clear(ctx context.Context) error {
 	err := c.docker.VolumeRemove(ctx, c.Name(), true)
 	if err != nil && !client.IsErrNotFound(err) {
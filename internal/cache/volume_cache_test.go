@@ -111,6 +111,27 @@ func testCache(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("#Labels", func() {
+		it("records the image and kind of cache", func() {
+			ref, err := name.ParseReference("my/repo", name.WeakValidation)
+			h.AssertNil(t, err)
+			subject := cache.NewVolumeCache(ref, "some-suffix", nil)
+			h.AssertEq(t, subject.Labels(), map[string]string{
+				"io.buildpacks.pack.cache.image": "index.docker.io/my/repo:latest",
+				"io.buildpacks.pack.cache.kind":  "some-suffix",
+			})
+		})
+	})
+
+	when("#Type", func() {
+		it("returns the cache type", func() {
+			ref, err := name.ParseReference("my/repo", name.WeakValidation)
+			h.AssertNil(t, err)
+			subject := cache.NewVolumeCache(ref, "some-suffix", nil)
+			h.AssertEq(t, subject.Type(), cache.Volume)
+		})
+	})
+
 	when("#Clear", func() {
 		var (
 			volumeName   string
@@ -1,22 +1,26 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	pubcfg "github.com/buildpacks/pack/config"
 
+	"github.com/docker/go-units"
 	"github.com/pkg/errors"
-	ignore "github.com/sabhiram/go-gitignore"
 	"github.com/spf13/cobra"
 
 	"github.com/buildpacks/pack"
 	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/dotenv"
 	"github.com/buildpacks/pack/internal/paths"
 	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/internal/watch"
 	"github.com/buildpacks/pack/logging"
 	"github.com/buildpacks/pack/project"
 )
@@ -25,18 +29,31 @@ type BuildFlags struct {
 	Publish            bool
 	ClearCache         bool
 	TrustBuilder       bool
+	Watch              bool
+	CPUs               float64
+	PidsLimit          int64
+	Timeout            time.Duration
 	AppPath            string
 	Builder            string
+	CacheImage         string
 	Registry           string
 	RunImage           string
 	Policy             string
 	Network            string
 	DescriptorPath     string
 	DefaultProcessType string
+	Output             string
+	Platform           string
+	Memory             string
 	Env                []string
 	EnvFiles           []string
 	Buildpacks         []string
 	Volumes            []string
+	Secrets            []string
+	SSH                []string
+	AddHosts           []string
+	DNS                []string
+	PhaseTimeouts      []string
 	AdditionalTags     []string
 }
 
@@ -55,19 +72,20 @@ func Build(logger logging.Logger, cfg config.Config, packClient PackClient) *cob
 			"be provided directly to build using `--builder`, or can be set using the `set-default-builder` command. For more " +
 			"on how to use `pack build`, see: https://buildpacks.io/docs/app-developer-guide/build-an-app/.",
 		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
-			if err := validateBuildFlags(&flags, cfg, packClient, logger); err != nil {
-				return err
-			}
-
 			imageName := args[0]
 
-			descriptor, actualDescriptorPath, err := parseProjectToml(flags.AppPath, flags.DescriptorPath)
+			descriptor, actualDescriptorPath, err := parseProjectToml(flags.AppPath, flags.DescriptorPath, logger)
 			if err != nil {
 				return err
 			}
 			if actualDescriptorPath != "" {
 				logger.Debugf("Using project descriptor located at %s", style.Symbol(actualDescriptorPath))
 			}
+			applyProjectSettings(cmd.Flags().Changed, &flags, descriptor, actualDescriptorPath, logger)
+
+			if err := validateBuildFlags(&flags, cfg, packClient, logger); err != nil {
+				return err
+			}
 
 			fileFilter, err := getFileFilter(descriptor)
 			if err != nil {
@@ -81,20 +99,8 @@ func Build(logger logging.Logger, cfg config.Config, packClient PackClient) *cob
 
 			buildpacks := flags.Buildpacks
 			if len(buildpacks) == 0 {
-				buildpacks = []string{}
-				projectDescriptorDir := filepath.Dir(actualDescriptorPath)
-				for _, bp := range descriptor.Build.Buildpacks {
-					if len(bp.URI) == 0 {
-						// there are several places through out the pack code where the "id@version" format is used.
-						// we should probably central this, but it's not clear where it belongs
-						buildpacks = append(buildpacks, fmt.Sprintf("%s@%s", bp.ID, bp.Version))
-					} else {
-						uri, err := paths.ToAbsolute(bp.URI, projectDescriptorDir)
-						if err != nil {
-							return err
-						}
-						buildpacks = append(buildpacks, uri)
-					}
+				if buildpacks, err = descriptorBuildpacks(descriptor, actualDescriptorPath); err != nil {
+					return err
 				}
 			}
 
@@ -111,12 +117,28 @@ func Build(logger logging.Logger, cfg config.Config, packClient PackClient) *cob
 				logger.Warn("Using untrusted builder with volume mounts. If there is sensitive data in the volumes, this may present a security vulnerability.")
 			}
 
+			if !trustBuilder && len(flags.SSH) > 0 {
+				logger.Warn("Using untrusted builder with SSH agent forwarding. Buildpacks may use the keys of the forwarded agents to access any host they are authorized for.")
+			}
+
 			pullPolicy, err := pubcfg.ParsePullPolicy(flags.Policy)
 			if err != nil {
 				return errors.Wrapf(err, "parsing pull policy %s", flags.Policy)
 			}
 
-			if err := packClient.Build(cmd.Context(), pack.BuildOptions{
+			var memory int64
+			if flags.Memory != "" {
+				if memory, err = units.RAMInBytes(flags.Memory); err != nil {
+					return errors.Wrapf(err, "parsing memory %s", style.Symbol(flags.Memory))
+				}
+			}
+
+			phaseTimeouts, err := parsePhaseTimeouts(flags.PhaseTimeouts)
+			if err != nil {
+				return err
+			}
+
+			buildOpts := pack.BuildOptions{
 				AppPath:           flags.AppPath,
 				Builder:           flags.Builder,
 				Registry:          flags.Registry,
@@ -128,15 +150,37 @@ func Build(logger logging.Logger, cfg config.Config, packClient PackClient) *cob
 				Publish:           flags.Publish,
 				PullPolicy:        pullPolicy,
 				ClearCache:        flags.ClearCache,
+				CacheImage:        flags.CacheImage,
 				TrustBuilder:      trustBuilder,
 				Buildpacks:        buildpacks,
+				InlineBuildpacks:  descriptorInlineBuildpacks(descriptor),
+				Secrets:           flags.Secrets,
 				ContainerConfig: pack.ContainerConfig{
-					Network: flags.Network,
-					Volumes: flags.Volumes,
+					Network:   flags.Network,
+					Volumes:   flags.Volumes,
+					SSH:       flags.SSH,
+					Memory:    memory,
+					CPUs:      flags.CPUs,
+					PidsLimit: flags.PidsLimit,
+					AddHosts:  flags.AddHosts,
+					DNS:       flags.DNS,
 				},
+				Timeout:            flags.Timeout,
+				PhaseTimeouts:      phaseTimeouts,
 				DefaultProcessType: flags.DefaultProcessType,
+				Output:             flags.Output,
+				Platform:           flags.Platform,
 				FileFilter:         fileFilter,
-			}); err != nil {
+			}
+			if flags.AppPath == pack.StdinAppPath {
+				buildOpts.AppReader = os.Stdin
+			}
+
+			if flags.Watch {
+				return watchBuild(cmd.Context(), logger, packClient, buildOpts)
+			}
+
+			if err := packClient.Build(cmd.Context(), buildOpts); err != nil {
 				return errors.Wrap(err, "failed to build")
 			}
 			logger.Infof("Successfully built image %s", style.Symbol(imageName))
@@ -149,7 +193,7 @@ func Build(logger logging.Logger, cfg config.Config, packClient PackClient) *cob
 }
 
 func buildCommandFlags(cmd *cobra.Command, buildFlags *BuildFlags, cfg config.Config) {
-	cmd.Flags().StringVarP(&buildFlags.AppPath, "path", "p", "", "Path to app dir or zip-formatted file (defaults to current working directory)")
+	cmd.Flags().StringVarP(&buildFlags.AppPath, "path", "p", "", "Path to app dir, zip, tar or tar.gz file, '-' to read a tar or tar.gz file from stdin, or git repository in the form 'git+<url>#<ref>[:<subdir>]' (defaults to current working directory)")
 	cmd.Flags().StringVarP(&buildFlags.Builder, "builder", "B", cfg.DefaultBuilder, "Builder image")
 	cmd.Flags().StringVarP(&buildFlags.Registry, "buildpack-registry", "r", cfg.DefaultRegistryName, "Buildpack Registry by name")
 	if !cfg.Experimental {
@@ -158,18 +202,86 @@ func buildCommandFlags(cmd *cobra.Command, buildFlags *BuildFlags, cfg config.Co
 	cmd.Flags().BoolVar(&buildFlags.Publish, "publish", false, "Publish to registry")
 	cmd.Flags().StringVar(&buildFlags.RunImage, "run-image", "", "Run image (defaults to default stack's run image)")
 	cmd.Flags().StringArrayVarP(&buildFlags.Env, "env", "e", []string{}, "Build-time environment variable, in the form 'VAR=VALUE' or 'VAR'.\nWhen using latter value-less form, value will be taken from current\n  environment at the time this command is executed.\nThis flag may be specified multiple times and will override\n  individual values defined by --env-file.")
-	cmd.Flags().StringArrayVar(&buildFlags.EnvFiles, "env-file", []string{}, "Build-time environment variables file\nOne variable per line, of the form 'VAR=VALUE' or 'VAR'\nWhen using latter value-less form, value will be taken from current\n  environment at the time this command is executed")
+	cmd.Flags().StringArrayVar(&buildFlags.EnvFiles, "env-file", []string{}, "Build-time environment variables file, in the .env format of docker-compose\nOne variable per line, of the form 'VAR=VALUE' or 'VAR', supporting quoted values,\n  '#' comments, an 'export' prefix and '${VAR}' interpolation\nWhen using latter value-less form, value will be taken from current\n  environment at the time this command is executed")
 	cmd.Flags().BoolVar(&buildFlags.ClearCache, "clear-cache", false, "Clear image's associated cache before building")
+	cmd.Flags().StringVar(&buildFlags.CacheImage, "cache-image", "", `Cache build layers in remote registry. Requires --publish`)
 	cmd.Flags().BoolVar(&buildFlags.TrustBuilder, "trust-builder", false, "Trust the provided builder\nAll lifecycle phases will be run in a single container (if supported by the lifecycle).")
 	cmd.Flags().StringSliceVarP(&buildFlags.Buildpacks, "buildpack", "b", nil, "Buildpack to use. One of:\n  a buildpack by id and version in the form of '<buildpack>@<version>',\n  path to a buildpack directory (not supported on Windows),\n  path/URL to a buildpack .tar or .tgz file, or\n  a packaged buildpack image name in the form of '<hostname>/<repo>[:<tag>]'"+multiValueHelp("buildpack"))
 	cmd.Flags().StringVar(&buildFlags.Network, "network", "", "Connect detect and build containers to network")
 	cmd.Flags().StringVarP(&buildFlags.DescriptorPath, "descriptor", "d", "", "Path to the project descriptor file")
 	cmd.Flags().StringArrayVar(&buildFlags.Volumes, "volume", nil, "Mount host volume into the build container, in the form '<host path>:<target path>[:<options>]'.\n- 'host path': Name of the volume or absolute directory path to mount.\n- 'target path': The path where the file or directory is available in the container.\n- 'options' (default \"ro\"): An optional comma separated list of mount options.\n    - \"ro\", volume contents are read-only.\n    - \"rw\", volume contents are readable and writeable.\n    - \"volume-opt=<key>=<value>\", can be specified more than once, takes a key-value pair consisting of the option name and its value."+multiValueHelp("volume"))
+	cmd.Flags().StringArrayVar(&buildFlags.Secrets, "secret", nil, "Secret made available to buildpacks in the build phase only, as the file '/platform/secrets/<id>'.\nSecrets are not stored in any image, and their values are masked in the build output. In the form:\n- 'id=<id>,src=<path>': the contents of a file.\n- 'id=<id>,env=<VAR>': the value of an environment variable.\nRepeat for each secret.")
+	cmd.Flags().StringArrayVar(&buildFlags.SSH, "ssh", nil, "SSH agent socket forwarded to the detect and build phases, as '/platform/ssh/<id>', in the form 'default|<id>=<socket path>'.\n- 'default': the agent SSH_AUTH_SOCK points to.\nSSH_AUTH_SOCK is set to the default agent, or else the first one.\nRepeat for each agent.")
+	cmd.Flags().StringVar(&buildFlags.Memory, "memory", "", "Memory limit of each build container, such as '2g'")
+	cmd.Flags().Float64Var(&buildFlags.CPUs, "cpus", 0, "Number of CPUs each build container may use, such as '1.5'")
+	cmd.Flags().Int64Var(&buildFlags.PidsLimit, "pids-limit", 0, "Maximum number of processes in each build container")
+	cmd.Flags().StringArrayVar(&buildFlags.AddHosts, "add-host", nil, "Add an /etc/hosts entry to the build containers, in the form 'host:ip'.\nRepeat for each host.")
+	cmd.Flags().StringArrayVar(&buildFlags.DNS, "dns", nil, "DNS server of the build containers.\nRepeat for each server.")
+	cmd.Flags().DurationVar(&buildFlags.Timeout, "timeout", 0, "Fail the build if the lifecycle runs for longer, such as '30m'")
+	cmd.Flags().StringArrayVar(&buildFlags.PhaseTimeouts, "phase-timeout", nil, "Fail the build if a lifecycle phase runs for longer, in the form '<phase>=<duration>', such as 'builder=20m'.\nPhases are detector, analyzer, restorer, builder, exporter and creator.\nRepeat for each phase.")
+	cmd.Flags().BoolVar(&buildFlags.Watch, "watch", false, "Rebuild the image whenever files in the app dir change, until interrupted.\nFiles excluded by the project descriptor are ignored.")
 	cmd.Flags().StringVarP(&buildFlags.DefaultProcessType, "default-process", "D", "", `Set the default process type. (default "web")`)
+	cmd.Flags().StringVar(&buildFlags.Output, "output", "", "Also save the image to a file, in the form 'oci:<dir>' for an OCI image layout directory, or 'docker-archive:<file.tar>' for a tarball 'docker load' accepts")
+	cmd.Flags().StringVar(&buildFlags.Platform, "platform", "", "Platform to build for, in the form '<os>/<arch>[/<variant>]', such as 'linux/arm64'.\nSelects the matching builder, run and lifecycle images when they are multi-platform image indexes.")
 	cmd.Flags().StringVar(&buildFlags.Policy, "pull-policy", "", `Pull policy to use. Accepted values are always, never, and if-not-present. (default "always")`)
 	cmd.Flags().StringSliceVarP(&buildFlags.AdditionalTags, "tag", "t", nil, "Additional tags to push the output image to."+multiValueHelp("tag"))
 }
 
+// applyProjectSettings sets the build settings the command line leaves unset, as told by isSet, to those of the project descriptor,
+// logging where each setting in effect comes from.
+func applyProjectSettings(isSet func(flagName string) bool, flags *BuildFlags, descriptor project.Descriptor, descriptorPath string, logger logging.Logger) {
+	source := func(flagName string, inDescriptor bool) string {
+		switch {
+		case isSet(flagName):
+			return fmt.Sprintf("flag %s", style.Symbol("--"+flagName))
+		case inDescriptor:
+			return fmt.Sprintf("project descriptor %s", style.Symbol(descriptorPath))
+		case flagName == "builder":
+			return "config"
+		default:
+			return "defaults"
+		}
+	}
+
+	stringSettings := []struct {
+		flagName   string
+		value      *string
+		descriptor string
+	}{
+		{"builder", &flags.Builder, descriptor.Build.Builder},
+		{"run-image", &flags.RunImage, descriptor.Build.RunImage},
+		{"default-process", &flags.DefaultProcessType, descriptor.Build.DefaultProcess},
+		{"pull-policy", &flags.Policy, descriptor.Build.PullPolicy},
+	}
+	for _, setting := range stringSettings {
+		inDescriptor := setting.descriptor != ""
+		if inDescriptor && !isSet(setting.flagName) {
+			*setting.value = setting.descriptor
+		}
+		if *setting.value != "" {
+			logger.Debugf("Using %s %s from %s", setting.flagName, style.Symbol(*setting.value), source(setting.flagName, inDescriptor))
+		}
+	}
+
+	sliceSettings := []struct {
+		flagName   string
+		value      *[]string
+		descriptor []string
+	}{
+		{"tag", &flags.AdditionalTags, descriptor.Build.Tags},
+		{"volume", &flags.Volumes, descriptor.Build.Volumes},
+	}
+	for _, setting := range sliceSettings {
+		inDescriptor := len(setting.descriptor) > 0
+		if inDescriptor && !isSet(setting.flagName) {
+			*setting.value = setting.descriptor
+		}
+		for _, value := range *setting.value {
+			logger.Debugf("Using %s %s from %s", setting.flagName, style.Symbol(value), source(setting.flagName, inDescriptor))
+		}
+	}
+}
+
 func validateBuildFlags(flags *BuildFlags, cfg config.Config, packClient PackClient, logger logging.Logger) error {
 	if flags.Builder == "" {
 		suggestSettingBuilder(logger, packClient)
@@ -180,17 +292,115 @@ func validateBuildFlags(flags *BuildFlags, cfg config.Config, packClient PackCli
 		return pack.NewExperimentError("Support for buildpack registries is currently experimental.")
 	}
 
+	if flags.CacheImage != "" && !flags.Publish {
+		return errors.New("cache-image flag requires the publish flag")
+	}
+
+	if flags.Watch && flags.AppPath != "" {
+		if fi, err := os.Stat(flags.AppPath); err != nil || !fi.IsDir() {
+			return errors.New("watch flag requires the app path to be a directory")
+		}
+	}
+
 	return nil
 }
 
+// parsePhaseTimeouts parses phase timeouts in the form '<phase>=<duration>'.
+func parsePhaseTimeouts(specs []string) (map[string]time.Duration, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+
+	timeouts := map[string]time.Duration{}
+	for _, spec := range specs {
+		parts := strings.SplitN(spec, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid phase timeout %s, must be in the form '<phase>=<duration>'", style.Symbol(spec))
+		}
+
+		timeout, err := time.ParseDuration(parts[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid phase timeout %s", style.Symbol(spec))
+		}
+		timeouts[parts[0]] = timeout
+	}
+	return timeouts, nil
+}
+
+// watchBuild builds the image, and rebuilds it whenever files in the app dir change, until ctx is done.
+// Failed builds are reported without ending the watch.
+func watchBuild(ctx context.Context, logger logging.Logger, packClient PackClient, opts pack.BuildOptions) error {
+	appDir := opts.AppPath
+	if appDir == "" {
+		appDir = "."
+	}
+
+	watcher, err := watch.NewWatcher(appDir, opts.FileFilter)
+	if err != nil {
+		return errors.Wrapf(err, "watching %s", style.Symbol(appDir))
+	}
+
+	var changed []string
+	for {
+		start := time.Now()
+		err := packClient.Build(ctx, opts)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		elapsed := time.Since(start).Round(100 * time.Millisecond)
+		if err != nil {
+			logger.Errorf("Failed to build image %s after %s: %s", style.Symbol(opts.Image), elapsed, err)
+		} else {
+			logger.Infof("Successfully built image %s in %s", style.Symbol(opts.Image), elapsed)
+		}
+
+		// the builder and run image fetched by the first build are reused
+		if opts.PullPolicy == pubcfg.PullAlways {
+			opts.PullPolicy = pubcfg.PullIfNotPresent
+		}
+
+		logger.Infof("Watching %s for changes, press Ctrl+C to stop", style.Symbol(appDir))
+		if changed, err = watcher.Wait(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return errors.Wrapf(err, "watching %s", style.Symbol(appDir))
+		}
+
+		logger.Infof("Rebuilding after changes to %s", summarizeFiles(changed))
+	}
+}
+
+// summarizeFiles lists the first few files, and how many others there are.
+func summarizeFiles(files []string) string {
+	const maxListed = 3
+	if len(files) <= maxListed {
+		return strings.Join(files, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(files[:maxListed], ", "), len(files)-maxListed)
+}
+
 func parseEnv(project project.Descriptor, envFiles []string, envVars []string) (map[string]string, error) {
 	env := map[string]string{}
 
+	// variables are interpolated from those set before them, or else from the environment
+	lookup := func(name string) (string, bool) {
+		if value, ok := env[name]; ok {
+			return value, true
+		}
+		return os.LookupEnv(name)
+	}
+
 	for _, envVar := range project.Build.Env {
-		env[envVar.Name] = envVar.Value
+		value, err := dotenv.Expand(envVar.Value, lookup)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value of project env var %s", style.Symbol(envVar.Name))
+		}
+		env[envVar.Name] = value
 	}
 	for _, envFile := range envFiles {
-		envFileVars, err := parseEnvFile(envFile)
+		envFileVars, err := parseEnvFile(envFile, lookup)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to parse env file '%s'", envFile)
 		}
@@ -205,20 +415,12 @@ func parseEnv(project project.Descriptor, envFiles []string, envVars []string) (
 	return env, nil
 }
 
-func parseEnvFile(filename string) (map[string]string, error) {
-	out := make(map[string]string)
+func parseEnvFile(filename string, lookup dotenv.LookupFunc) (map[string]string, error) {
 	f, err := ioutil.ReadFile(filename)
 	if err != nil {
 		return nil, errors.Wrapf(err, "open %s", filename)
 	}
-	for _, line := range strings.Split(string(f), "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-		out = addEnvVar(out, line)
-	}
-	return out, nil
+	return dotenv.Parse(string(f), lookup)
 }
 
 func addEnvVar(env map[string]string, item string) map[string]string {
@@ -231,7 +433,7 @@ func addEnvVar(env map[string]string, item string) map[string]string {
 	return env
 }
 
-func parseProjectToml(appPath, descriptorPath string) (project.Descriptor, string, error) {
+func parseProjectToml(appPath, descriptorPath string, logger logging.Logger) (project.Descriptor, string, error) {
 	actualPath := descriptorPath
 	computePath := descriptorPath == ""
 
@@ -246,21 +448,51 @@ func parseProjectToml(appPath, descriptorPath string) (project.Descriptor, strin
 		return project.Descriptor{}, "", errors.Wrap(err, "stat project descriptor")
 	}
 
-	descriptor, err := project.ReadProjectDescriptor(actualPath)
+	descriptor, err := project.ReadProjectDescriptorWithLogger(actualPath, logger)
 	return descriptor, actualPath, err
 }
 
-func getFileFilter(descriptor project.Descriptor) (func(string) bool, error) {
-	if len(descriptor.Build.Exclude) > 0 {
-		excludes := ignore.CompileIgnoreLines(descriptor.Build.Exclude...)
-		return func(fileName string) bool {
-			return !excludes.MatchesPath(fileName)
-		}, nil
+// descriptorBuildpacks returns the buildpacks declared by a project descriptor, with URIs relative to the descriptor made absolute.
+func descriptorBuildpacks(descriptor project.Descriptor, descriptorPath string) ([]string, error) {
+	buildpacks := []string{}
+	projectDescriptorDir := filepath.Dir(descriptorPath)
+	for _, bp := range descriptor.Build.Buildpacks {
+		switch {
+		case len(bp.URI) == 0 && len(bp.Version) == 0:
+			buildpacks = append(buildpacks, bp.ID)
+		case len(bp.URI) == 0:
+			// there are several places through out the pack code where the "id@version" format is used.
+			// we should probably central this, but it's not clear where it belongs
+			buildpacks = append(buildpacks, fmt.Sprintf("%s@%s", bp.ID, bp.Version))
+		default:
+			uri, err := paths.ToAbsolute(bp.URI, projectDescriptorDir)
+			if err != nil {
+				return nil, err
+			}
+			buildpacks = append(buildpacks, uri)
+		}
 	}
-	if len(descriptor.Build.Include) > 0 {
-		includes := ignore.CompileIgnoreLines(descriptor.Build.Include...)
-		return includes.MatchesPath, nil
+	return buildpacks, nil
+}
+
+// descriptorInlineBuildpacks returns the buildpacks a project descriptor defines with a script.
+func descriptorInlineBuildpacks(descriptor project.Descriptor) []pack.InlineBuildpack {
+	var inlineBuildpacks []pack.InlineBuildpack
+	for _, bp := range descriptor.Build.Buildpacks {
+		if bp.Script.Inline == "" {
+			continue
+		}
+		inlineBuildpacks = append(inlineBuildpacks, pack.InlineBuildpack{
+			ID:      bp.ID,
+			Version: bp.Version,
+			API:     bp.Script.API,
+			Shell:   bp.Script.Shell,
+			Script:  bp.Script.Inline,
+		})
 	}
+	return inlineBuildpacks
+}
 
-	return nil, nil
+func getFileFilter(descriptor project.Descriptor) (func(string) bool, error) {
+	return descriptor.FileFilter(), nil
 }
@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack"
+	pubcfg "github.com/buildpacks/pack/config"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/logging"
+	"github.com/buildpacks/pack/project"
+)
+
+type BuildAllFlags struct {
+	Manifest     string
+	Parallel     int
+	Builder      string
+	Publish      bool
+	TrustBuilder bool
+	Policy       string
+	Env          []string
+}
+
+// BuildAll builds every app listed in a manifest
+func BuildAll(logger logging.Logger, cfg config.Config, packClient PackClient) *cobra.Command {
+	var flags BuildAllFlags
+
+	cmd := &cobra.Command{
+		Use:   "build-all",
+		Args:  cobra.NoArgs,
+		Short: "Generate app images for every app listed in a manifest",
+		Long: "Build All builds each app listed in the [[apps]] table of a manifest, such as an apps.toml file or a root project descriptor.\n\n" +
+			"Each app is built as `pack build` would, using the project descriptor in its path. Images shared by the builds, " +
+			"such as the builder and run image, are pulled once. The output of each build is prefixed with the name of its app, " +
+			"and a summary is printed once every build has finished.",
+		Example: "pack build-all --manifest apps.toml --parallel 4",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.Parallel < 1 {
+				return errors.New("parallel flag must be at least 1")
+			}
+
+			manifest, err := project.ReadManifest(flags.Manifest)
+			if err != nil {
+				return errors.Wrapf(err, "reading manifest %s", style.Symbol(flags.Manifest))
+			}
+
+			// the builder of the command line is only used by apps setting none, while other flags override the project descriptors
+			isSet := func(flagName string) bool {
+				return flagName != "builder" && cmd.Flags().Changed(flagName)
+			}
+
+			var apps []pack.AppBuild
+			for _, app := range manifest.Apps {
+				opts, err := appBuildOptions(app, flags, isSet, cfg, logger)
+				if err != nil {
+					return errors.Wrapf(err, "app %s", style.Symbol(app.Name))
+				}
+
+				if opts.Builder == "" {
+					suggestSettingBuilder(logger, packClient)
+					return pack.NewSoftError()
+				}
+
+				apps = append(apps, pack.AppBuild{Name: app.Name, Options: opts})
+			}
+
+			results := packClient.BuildAll(cmd.Context(), pack.BuildAllOptions{
+				Apps:     apps,
+				Parallel: flags.Parallel,
+			})
+
+			logger.Info(style.Step("SUMMARY"))
+			failed := 0
+			for _, result := range results {
+				duration := result.Duration.Round(100 * time.Millisecond)
+				if result.Err != nil {
+					failed++
+					logger.Errorf("%s: failed to build image %s after %s: %s", result.Name, style.Symbol(result.Image), duration, result.Err)
+					continue
+				}
+				logger.Infof("%s: built image %s in %s", result.Name, style.Symbol(result.Image), duration)
+			}
+
+			if failed > 0 {
+				return errors.Errorf("%d of %d builds failed", failed, len(results))
+			}
+			logger.Infof("Successfully built %d images", len(results))
+			return nil
+		}),
+	}
+	cmd.Flags().StringVarP(&flags.Manifest, "manifest", "m", "apps.toml", "Path to the manifest listing the apps to build")
+	cmd.Flags().IntVar(&flags.Parallel, "parallel", 1, "Number of builds to run at the same time")
+	cmd.Flags().StringVarP(&flags.Builder, "builder", "B", cfg.DefaultBuilder, "Builder image, for apps that set none in the manifest or their project descriptor")
+	cmd.Flags().BoolVar(&flags.Publish, "publish", false, "Publish to registry")
+	cmd.Flags().BoolVar(&flags.TrustBuilder, "trust-builder", false, "Trust the builders of the apps\nAll lifecycle phases will be run in a single container (if supported by the lifecycle).")
+	cmd.Flags().StringVar(&flags.Policy, "pull-policy", "", `Pull policy to use. Accepted values are always, never, and if-not-present. (default "always")`)
+	cmd.Flags().StringArrayVarP(&flags.Env, "env", "e", []string{}, "Build-time environment variable for every app, in the form 'VAR=VALUE' or 'VAR'.\nWhen using latter value-less form, value will be taken from current\n  environment at the time this command is executed.\nThis flag may be specified multiple times and will override\n  individual values defined by the manifest.")
+	AddHelpFlag(cmd, "build-all")
+	return cmd
+}
+
+// appBuildOptions returns the options to build an app of a manifest with, combining the project descriptor of the app,
+// its manifest entry and the flags set, in increasing order of precedence, as for pack build.
+func appBuildOptions(app project.App, flags BuildAllFlags, isSet func(flagName string) bool, cfg config.Config, logger logging.Logger) (pack.BuildOptions, error) {
+	descriptor, descriptorPath, err := parseProjectToml(app.Path, "", logger)
+	if err != nil {
+		return pack.BuildOptions{}, err
+	}
+
+	buildFlags := BuildFlags{Builder: flags.Builder, Policy: flags.Policy}
+	applyProjectSettings(isSet, &buildFlags, descriptor, descriptorPath, logger)
+	if app.Builder != "" {
+		buildFlags.Builder = app.Builder
+	}
+
+	pullPolicy, err := pubcfg.ParsePullPolicy(buildFlags.Policy)
+	if err != nil {
+		return pack.BuildOptions{}, errors.Wrapf(err, "parsing pull policy %s", buildFlags.Policy)
+	}
+
+	fileFilter, err := getFileFilter(descriptor)
+	if err != nil {
+		return pack.BuildOptions{}, err
+	}
+
+	env, err := parseEnv(descriptor, nil, nil)
+	if err != nil {
+		return pack.BuildOptions{}, err
+	}
+	for k, v := range app.Env {
+		env[k] = v
+	}
+	for _, envVar := range flags.Env {
+		env = addEnvVar(env, envVar)
+	}
+
+	buildpacks, err := descriptorBuildpacks(descriptor, descriptorPath)
+	if err != nil {
+		return pack.BuildOptions{}, err
+	}
+
+	builder := buildFlags.Builder
+	return pack.BuildOptions{
+		AppPath:           app.Path,
+		Builder:           builder,
+		AdditionalMirrors: getMirrors(cfg),
+		AdditionalTags:    buildFlags.AdditionalTags,
+		Env:               env,
+		Image:             app.Image,
+		Publish:           flags.Publish,
+		PullPolicy:        pullPolicy,
+		TrustBuilder:      builder != "" && (isTrustedBuilder(cfg, builder) || flags.TrustBuilder),
+		Buildpacks:        buildpacks,
+		InlineBuildpacks:  descriptorInlineBuildpacks(descriptor),
+		FileFilter:        fileFilter,
+		RunImage:          buildFlags.RunImage,
+		ContainerConfig: pack.ContainerConfig{
+			Volumes: buildFlags.Volumes,
+		},
+		DefaultProcessType: buildFlags.DefaultProcessType,
+	}, nil
+}
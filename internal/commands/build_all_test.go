@@ -0,0 +1,227 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack"
+	pubcfg "github.com/buildpacks/pack/config"
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	ilogging "github.com/buildpacks/pack/internal/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBuildAllCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "BuildAllCommand", testBuildAllCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testBuildAllCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         *ilogging.LogWithWriters
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		tmpDir         string
+		manifestPath   string
+	)
+
+	it.Before(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "build-all-command")
+		h.AssertNil(t, err)
+
+		h.AssertNil(t, os.MkdirAll(filepath.Join(tmpDir, "api"), 0755))
+		h.AssertNil(t, ioutil.WriteFile(filepath.Join(tmpDir, "api", "project.toml"), []byte(`
+[[build.env]]
+name = "FROM_DESCRIPTOR"
+value = "descriptor-value"
+[[build.buildpacks]]
+id = "some/bp"
+version = "1.2.3"
+`), 0644))
+
+		manifestPath = filepath.Join(tmpDir, "apps.toml")
+		h.AssertNil(t, ioutil.WriteFile(manifestPath, []byte(`
+[[apps]]
+image = "some-registry/api"
+path = "api"
+env = { FROM_MANIFEST = "manifest-value" }
+
+[[apps]]
+name = "web"
+image = "some-registry/web"
+path = "web"
+builder = "web/builder"
+`), 0644))
+
+		logger = ilogging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+
+		command = commands.BuildAll(logger, config.Config{DefaultBuilder: "default/builder"}, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	when("#BuildAll", func() {
+		it("builds every app in the manifest", func() {
+			mockClient.EXPECT().
+				BuildAll(gomock.Any(), EqBuildAllOptions(2, []pack.AppBuild{
+					{Name: "api", Options: pack.BuildOptions{
+						AppPath:    filepath.Join(tmpDir, "api"),
+						Builder:    "default/builder",
+						Image:      "some-registry/api",
+						Env:        map[string]string{"FROM_DESCRIPTOR": "descriptor-value", "FROM_MANIFEST": "manifest-value", "FROM_FLAG": "flag-value"},
+						Buildpacks: []string{"some/bp@1.2.3"},
+					}},
+					{Name: "web", Options: pack.BuildOptions{
+						AppPath:    filepath.Join(tmpDir, "web"),
+						Builder:    "web/builder",
+						Image:      "some-registry/web",
+						Env:        map[string]string{"FROM_FLAG": "flag-value"},
+						Buildpacks: []string{},
+					}},
+				})).
+				Return([]pack.AppBuildResult{
+					{Name: "api", Image: "some-registry/api", Duration: 3 * time.Second},
+					{Name: "web", Image: "some-registry/web", Duration: time.Second},
+				})
+
+			command.SetArgs([]string{"--manifest", manifestPath, "--parallel", "2", "--env", "FROM_FLAG=flag-value"})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), "api: built image 'some-registry/api' in 3s")
+			h.AssertContains(t, outBuf.String(), "web: built image 'some-registry/web' in 1s")
+			h.AssertContains(t, outBuf.String(), "Successfully built 2 images")
+		})
+
+		when("the project descriptor of an app has build settings", func() {
+			var built []pack.AppBuild
+
+			it.Before(func() {
+				h.AssertNil(t, os.MkdirAll(filepath.Join(tmpDir, "web"), 0755))
+				h.AssertNil(t, ioutil.WriteFile(filepath.Join(tmpDir, "web", "project.toml"), []byte(`
+[build]
+builder = "descriptor/builder"
+run-image = "descriptor/run"
+default-process = "worker"
+pull-policy = "if-not-present"
+tags = ["some-registry/web:v1"]
+volumes = ["/some/cache:/cache"]
+`), 0644))
+
+				mockClient.EXPECT().
+					BuildAll(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, opts pack.BuildAllOptions) []pack.AppBuildResult {
+						built = opts.Apps
+						return nil
+					})
+			})
+
+			it("builds the app with them", func() {
+				command.SetArgs([]string{"--manifest", manifestPath})
+				h.AssertNil(t, command.Execute())
+
+				web := built[1].Options
+				h.AssertEq(t, web.Builder, "web/builder")
+				h.AssertEq(t, web.RunImage, "descriptor/run")
+				h.AssertEq(t, web.DefaultProcessType, "worker")
+				h.AssertEq(t, web.PullPolicy, pubcfg.PullIfNotPresent)
+				h.AssertEq(t, web.AdditionalTags, []string{"some-registry/web:v1"})
+				h.AssertEq(t, web.ContainerConfig.Volumes, []string{"/some/cache:/cache"})
+
+				api := built[0].Options
+				h.AssertEq(t, api.PullPolicy, pubcfg.PullAlways)
+				h.AssertEq(t, len(api.AdditionalTags), 0)
+			})
+
+			it("prefers the pull policy of the command line", func() {
+				command.SetArgs([]string{"--manifest", manifestPath, "--pull-policy", "never"})
+				h.AssertNil(t, command.Execute())
+
+				h.AssertEq(t, built[1].Options.PullPolicy, pubcfg.PullNever)
+			})
+		})
+
+		it("summarizes the builds and fails when any build fails", func() {
+			mockClient.EXPECT().
+				BuildAll(gomock.Any(), gomock.Any()).
+				Return([]pack.AppBuildResult{
+					{Name: "api", Image: "some-registry/api", Duration: 3 * time.Second},
+					{Name: "web", Image: "some-registry/web", Duration: time.Second, Err: errors.New("some-build-error")},
+				})
+
+			command.SetArgs([]string{"--manifest", manifestPath})
+			h.AssertError(t, command.Execute(), "1 of 2 builds failed")
+
+			h.AssertContains(t, outBuf.String(), "api: built image 'some-registry/api' in 3s")
+			h.AssertContains(t, outBuf.String(), "ERROR: web: failed to build image 'some-registry/web' after 1s: some-build-error")
+		})
+
+		it("errors for an invalid manifest", func() {
+			command.SetArgs([]string{"--manifest", filepath.Join(tmpDir, "not-exist.toml")})
+			h.AssertError(t, command.Execute(), "reading manifest")
+		})
+
+		it("errors when parallel is less than 1", func() {
+			command.SetArgs([]string{"--manifest", manifestPath, "--parallel", "0"})
+			h.AssertError(t, command.Execute(), "parallel flag must be at least 1")
+		})
+	})
+}
+
+func EqBuildAllOptions(parallel int, apps []pack.AppBuild) gomock.Matcher {
+	return buildAllOptionsMatcher{parallel: parallel, apps: apps}
+}
+
+type buildAllOptionsMatcher struct {
+	parallel int
+	apps     []pack.AppBuild
+}
+
+func (m buildAllOptionsMatcher) Matches(x interface{}) bool {
+	opts, ok := x.(pack.BuildAllOptions)
+	if !ok || opts.Parallel != m.parallel || len(opts.Apps) != len(m.apps) {
+		return false
+	}
+
+	for i, app := range opts.Apps {
+		expected := m.apps[i]
+		if app.Name != expected.Name ||
+			app.Options.AppPath != expected.Options.AppPath ||
+			app.Options.Builder != expected.Options.Builder ||
+			app.Options.Image != expected.Options.Image ||
+			!reflect.DeepEqual(app.Options.Env, expected.Options.Env) ||
+			!reflect.DeepEqual(app.Options.Buildpacks, expected.Options.Buildpacks) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m buildAllOptionsMatcher) String() string {
+	return fmt.Sprintf("is a BuildAllOptions with Parallel=%d and Apps=%+v", m.parallel, m.apps)
+}
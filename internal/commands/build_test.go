@@ -2,12 +2,15 @@ package commands_test
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	pubcfg "github.com/buildpacks/pack/config"
 
@@ -152,6 +155,22 @@ func testBuildCommand(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("--cache-image", func() {
+			it("passes it through to the client", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithCacheImage("some-cache-image")).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--publish", "--cache-image", "some-cache-image"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("fails when --publish is not set", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--cache-image", "some-cache-image"})
+				h.AssertError(t, command.Execute(), "cache-image flag requires the publish flag")
+			})
+		})
+
 		when("volume mounts are specified", func() {
 			it("mounts the volumes", func() {
 				mockClient.EXPECT().
@@ -173,6 +192,162 @@ func testBuildCommand(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("secrets are specified", func() {
+			it("passes them to the build", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithSecrets([]string{"id=npmrc,src=~/.npmrc", "id=token,env=TOKEN"})).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--secret", "id=npmrc,src=~/.npmrc", "--secret", "id=token,env=TOKEN"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("--path is '-'", func() {
+			it("reads the app from stdin", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithAppReader(os.Stdin)).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--path", "-"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("does not read stdin for other paths", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithAppReader(nil)).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("--platform", func() {
+			it("passes the platform through", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithPlatform("linux/arm64")).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--platform", "linux/arm64"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("resource limits and timeouts are specified", func() {
+			it("passes them through", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithResources(
+						pack.ContainerConfig{
+							Memory:    2 << 30,
+							CPUs:      1.5,
+							PidsLimit: 100,
+							AddHosts:  []string{"some-host:10.0.0.1"},
+							DNS:       []string{"10.0.0.2"},
+						},
+						time.Hour,
+						map[string]time.Duration{"builder": 30 * time.Minute},
+					)).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder",
+					"--memory", "2g", "--cpus", "1.5", "--pids-limit", "100",
+					"--add-host", "some-host:10.0.0.1", "--dns", "10.0.0.2",
+					"--timeout", "1h", "--phase-timeout", "builder=30m",
+				})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("fails for an invalid memory limit", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--memory", "lots"})
+				h.AssertError(t, command.Execute(), "parsing memory 'lots'")
+			})
+
+			it("fails for a phase timeout that is not in the form '<phase>=<duration>'", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--phase-timeout", "30m"})
+				h.AssertError(t, command.Execute(), "invalid phase timeout '30m', must be in the form '<phase>=<duration>'")
+			})
+		})
+
+		when("--output", func() {
+			it("passes the output through", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithOutput("oci:some/dir")).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--output", "oci:some/dir"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("SSH agents are specified", func() {
+			it("forwards them", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithSSH([]string{"default", "other=/some/agent.sock"})).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--ssh", "default", "--ssh", "other=/some/agent.sock"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("warns when running with an untrusted builder", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithSSH([]string{"default"})).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--ssh", "default"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Warning: Using untrusted builder with SSH agent forwarding")
+			})
+		})
+
+		when("--watch", func() {
+			var appDir string
+
+			it.Before(func() {
+				var err error
+				appDir, err = ioutil.TempDir("", "build-watch")
+				h.AssertNil(t, err)
+				h.AssertNil(t, ioutil.WriteFile(filepath.Join(appDir, "some-file.txt"), []byte("some-contents"), 0644))
+			})
+
+			it.After(func() {
+				h.AssertNil(t, os.RemoveAll(appDir))
+			})
+
+			it("rebuilds on changes until interrupted, reusing fetched images", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				gomock.InOrder(
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithPullPolicy(pubcfg.PullAlways)).
+						DoAndReturn(func(context.Context, pack.BuildOptions) error {
+							h.AssertNil(t, ioutil.WriteFile(filepath.Join(appDir, "some-file.txt"), []byte("modified-contents"), 0644))
+							return errors.New("some-build-error")
+						}),
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithPullPolicy(pubcfg.PullIfNotPresent)).
+						DoAndReturn(func(context.Context, pack.BuildOptions) error {
+							cancel()
+							return nil
+						}),
+				)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--path", appDir, "--watch"})
+				h.AssertNil(t, command.ExecuteContext(ctx))
+
+				h.AssertContains(t, outBuf.String(), "ERROR: Failed to build image 'image' after")
+				h.AssertContains(t, outBuf.String(), "some-build-error")
+				h.AssertContains(t, outBuf.String(), "Rebuilding after changes to some-file.txt")
+			})
+
+			it("requires the app path to be a directory", func() {
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--path", filepath.Join(appDir, "some-file.txt"), "--watch"})
+				h.AssertError(t, command.Execute(), "watch flag requires the app path to be a directory")
+			})
+		})
+
 		when("a default process is specified", func() {
 			it("sets that process", func() {
 				mockClient.EXPECT().
@@ -284,6 +459,63 @@ func testBuildCommand(t *testing.T, when spec.G, it spec.S) {
 					h.AssertNil(t, command.Execute())
 				})
 			})
+
+			when("an env file uses quotes, comments, export and interpolation", func() {
+				var envPath string
+
+				it.Before(func() {
+					envfile, err := ioutil.TempFile("", "envfile")
+					h.AssertNil(t, err)
+					defer envfile.Close()
+
+					envfile.WriteString(`# database settings
+export DB_HOST=localhost # the default host
+DB_URL="postgres://${DB_HOST}:5432"
+GREETING='hello
+world'
+`)
+					envPath = envfile.Name()
+				})
+
+				it.After(func() {
+					h.AssertNil(t, os.RemoveAll(envPath))
+				})
+
+				it("builds an image with the parsed env variables", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithEnv(map[string]string{
+							"DB_HOST":  "localhost",
+							"DB_URL":   "postgres://localhost:5432",
+							"GREETING": "hello\nworld",
+						})).
+						Return(nil)
+
+					command.SetArgs([]string{"--builder", "my-builder", "image", "--env-file", envPath})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+
+			when("an env file is invalid", func() {
+				var envPath string
+
+				it.Before(func() {
+					envfile, err := ioutil.TempFile("", "envfile")
+					h.AssertNil(t, err)
+					defer envfile.Close()
+
+					envfile.WriteString("KEY=VALUE\nOTHER=\"unterminated\n")
+					envPath = envfile.Name()
+				})
+
+				it.After(func() {
+					h.AssertNil(t, os.RemoveAll(envPath))
+				})
+
+				it("errors with the line of the error", func() {
+					command.SetArgs([]string{"--builder", "my-builder", "image", "--env-file", envPath})
+					h.AssertError(t, command.Execute(), "line 2: unterminated quoted value of 'OTHER'")
+				})
+			})
 		})
 
 		when("env vars are passed as flags", func() {
@@ -495,6 +727,49 @@ uri = "https://www.test.tgz"
 				})
 			})
 
+			when("descriptor has an inline buildpack", func() {
+				var projectTomlPath string
+
+				it.Before(func() {
+					projectToml, err := ioutil.TempFile("", "project.toml")
+					h.AssertNil(t, err)
+					defer projectToml.Close()
+
+					projectToml.WriteString(`
+[project]
+name = "Sample"
+
+[[build.buildpacks]]
+id = "example/lua"
+version = "1.0"
+
+[[build.buildpacks]]
+id = "example/migrate"
+
+  [build.buildpacks.script]
+  api = "0.4"
+  inline = "./migrate.sh"
+`)
+					projectTomlPath = projectToml.Name()
+				})
+
+				it.After(func() {
+					h.AssertNil(t, os.RemoveAll(projectTomlPath))
+				})
+
+				it("should build an image with the inline buildpack at its position", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithInlineBuildpacks(
+							[]string{"example/lua@1.0", "example/migrate"},
+							[]pack.InlineBuildpack{{ID: "example/migrate", API: "0.4", Script: "./migrate.sh"}},
+						)).
+						Return(nil)
+
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--descriptor", projectTomlPath})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+
 			when("descriptor buildpack has malformed uri", func() {
 				var projectTomlPath string
 
@@ -601,6 +876,129 @@ include = [ "*.jar" ]
 					h.AssertNil(t, command.Execute())
 				})
 			})
+
+			when("descriptor has env vars referencing other variables", func() {
+				var projectTomlPath string
+
+				it.Before(func() {
+					projectToml, err := ioutil.TempFile("", "project.toml")
+					h.AssertNil(t, err)
+					defer projectToml.Close()
+
+					projectToml.WriteString(`
+[project]
+name = "Sample"
+
+[[build.env]]
+name = "HOST"
+value = "example.com"
+
+[[build.env]]
+name = "URL"
+value = "https://${HOST}/${UNSET_PATH:-api}"
+`)
+					projectTomlPath = projectToml.Name()
+				})
+
+				it.After(func() {
+					h.AssertNil(t, os.RemoveAll(projectTomlPath))
+				})
+
+				it("interpolates them", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithEnv(map[string]string{
+							"HOST": "example.com",
+							"URL":  "https://example.com/api",
+						})).
+						Return(nil)
+
+					command.SetArgs([]string{"--builder", "my-builder", "image", "--descriptor", projectTomlPath})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+
+			when("descriptor has build settings", func() {
+				var projectTomlPath string
+
+				it.Before(func() {
+					projectToml, err := ioutil.TempFile("", "project.toml")
+					h.AssertNil(t, err)
+					defer projectToml.Close()
+
+					projectToml.WriteString(`
+[project]
+name = "Sample"
+
+[build]
+builder = "project-builder"
+run-image = "project/run"
+default-process = "worker"
+pull-policy = "never"
+tags = [ "project/tag" ]
+volumes = [ "/project/volume:/volume" ]
+`)
+					projectTomlPath = projectToml.Name()
+				})
+
+				it.After(func() {
+					h.AssertNil(t, os.RemoveAll(projectTomlPath))
+				})
+
+				it("should build with the settings of the descriptor", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithProjectSettings(pack.BuildOptions{
+							Builder:            "project-builder",
+							RunImage:           "project/run",
+							DefaultProcessType: "worker",
+							PullPolicy:         pubcfg.PullNever,
+							AdditionalTags:     []string{"project/tag"},
+							ContainerConfig:    pack.ContainerConfig{Volumes: []string{"/project/volume:/volume"}},
+						})).
+						Return(nil)
+
+					command.SetArgs([]string{"image", "--descriptor", projectTomlPath})
+					h.AssertNil(t, command.Execute())
+				})
+
+				it("should prefer the flags to the descriptor", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithProjectSettings(pack.BuildOptions{
+							Builder:            "flag-builder",
+							RunImage:           "flag/run",
+							DefaultProcessType: "web",
+							PullPolicy:         pubcfg.PullIfNotPresent,
+							AdditionalTags:     []string{"flag/tag"},
+							ContainerConfig:    pack.ContainerConfig{Volumes: []string{"/flag/volume:/volume"}},
+						})).
+						Return(nil)
+
+					command.SetArgs([]string{
+						"image",
+						"--descriptor", projectTomlPath,
+						"--builder", "flag-builder",
+						"--run-image", "flag/run",
+						"--default-process", "web",
+						"--pull-policy", "if-not-present",
+						"--tag", "flag/tag",
+						"--volume", "/flag/volume:/volume",
+					})
+					h.AssertNil(t, command.Execute())
+				})
+
+				it("should log where each setting comes from when verbose", func() {
+					logger.WantVerbose(true)
+					mockClient.EXPECT().
+						Build(gomock.Any(), gomock.Any()).
+						Return(nil)
+
+					command.SetArgs([]string{"image", "--descriptor", projectTomlPath, "--run-image", "flag/run"})
+					h.AssertNil(t, command.Execute())
+
+					h.AssertContains(t, outBuf.String(), "Using builder 'project-builder' from project descriptor '"+projectTomlPath+"'")
+					h.AssertContains(t, outBuf.String(), "Using run-image 'flag/run' from flag '--run-image'")
+					h.AssertContains(t, outBuf.String(), "Using tag 'project/tag' from project descriptor '"+projectTomlPath+"'")
+				})
+			})
 		})
 
 		when("additional tags are specified", func() {
@@ -644,6 +1042,15 @@ func EqBuildOptionsWithPullPolicy(policy pubcfg.PullPolicy) gomock.Matcher {
 	}
 }
 
+func EqBuildOptionsWithCacheImage(cacheImage string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("CacheImage=%s", cacheImage),
+		equals: func(o pack.BuildOptions) bool {
+			return o.CacheImage == cacheImage
+		},
+	}
+}
+
 func EqBuildOptionsWithNetwork(network string) gomock.Matcher {
 	return buildOptionsMatcher{
 		description: fmt.Sprintf("Network=%s", network),
@@ -689,6 +1096,66 @@ func EqBuildOptionsWithVolumes(volumes []string) gomock.Matcher {
 	}
 }
 
+func EqBuildOptionsWithAppReader(appReader io.Reader) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("AppReader=%v", appReader),
+		equals: func(o pack.BuildOptions) bool {
+			return o.AppReader == appReader
+		},
+	}
+}
+
+func EqBuildOptionsWithSecrets(secrets []string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("Secrets=%s", secrets),
+		equals: func(o pack.BuildOptions) bool {
+			return reflect.DeepEqual(o.Secrets, secrets)
+		},
+	}
+}
+
+func EqBuildOptionsWithSSH(ssh []string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("SSH=%s", ssh),
+		equals: func(o pack.BuildOptions) bool {
+			return reflect.DeepEqual(o.ContainerConfig.SSH, ssh)
+		},
+	}
+}
+
+func EqBuildOptionsWithResources(config pack.ContainerConfig, timeout time.Duration, phaseTimeouts map[string]time.Duration) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("ContainerConfig=%+v Timeout=%s PhaseTimeouts=%v", config, timeout, phaseTimeouts),
+		equals: func(o pack.BuildOptions) bool {
+			return o.ContainerConfig.Memory == config.Memory &&
+				o.ContainerConfig.CPUs == config.CPUs &&
+				o.ContainerConfig.PidsLimit == config.PidsLimit &&
+				reflect.DeepEqual(o.ContainerConfig.AddHosts, config.AddHosts) &&
+				reflect.DeepEqual(o.ContainerConfig.DNS, config.DNS) &&
+				o.Timeout == timeout &&
+				reflect.DeepEqual(o.PhaseTimeouts, phaseTimeouts)
+		},
+	}
+}
+
+func EqBuildOptionsWithPlatform(platform string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("Platform=%s", platform),
+		equals: func(o pack.BuildOptions) bool {
+			return o.Platform == platform
+		},
+	}
+}
+
+func EqBuildOptionsWithOutput(output string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("Output=%s", output),
+		equals: func(o pack.BuildOptions) bool {
+			return o.Output == output
+		},
+	}
+}
+
 func EqBuildOptionsWithEnv(env map[string]string) gomock.Matcher {
 	return buildOptionsMatcher{
 		description: fmt.Sprintf("Env=%+v", env),
@@ -727,6 +1194,30 @@ func EqBuildOptionsWithBuildpacks(buildpacks []string) gomock.Matcher {
 	}
 }
 
+func EqBuildOptionsWithInlineBuildpacks(buildpacks []string, inlineBuildpacks []pack.InlineBuildpack) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("Buildpacks=%+v InlineBuildpacks=%+v", buildpacks, inlineBuildpacks),
+		equals: func(o pack.BuildOptions) bool {
+			return reflect.DeepEqual(o.Buildpacks, buildpacks) && reflect.DeepEqual(o.InlineBuildpacks, inlineBuildpacks)
+		},
+	}
+}
+
+func EqBuildOptionsWithProjectSettings(expected pack.BuildOptions) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("Builder=%s RunImage=%s DefaultProcessType=%s PullPolicy=%s AdditionalTags=%+v Volumes=%+v",
+			expected.Builder, expected.RunImage, expected.DefaultProcessType, expected.PullPolicy, expected.AdditionalTags, expected.ContainerConfig.Volumes),
+		equals: func(o pack.BuildOptions) bool {
+			return o.Builder == expected.Builder &&
+				o.RunImage == expected.RunImage &&
+				o.DefaultProcessType == expected.DefaultProcessType &&
+				o.PullPolicy == expected.PullPolicy &&
+				reflect.DeepEqual(o.AdditionalTags, expected.AdditionalTags) &&
+				reflect.DeepEqual(o.ContainerConfig.Volumes, expected.ContainerConfig.Volumes)
+		},
+	}
+}
+
 type buildOptionsMatcher struct {
 	equals      func(pack.BuildOptions) bool
 	description string
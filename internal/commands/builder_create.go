@@ -20,6 +20,7 @@ type BuilderCreateFlags struct {
 	Publish         bool
 	Registry        string
 	Policy          string
+	Platform        string
 }
 
 // CreateBuilder creates a builder image, based on a builder config
@@ -62,6 +63,7 @@ Creating a custom builder allows you to control what buildpacks are used and wha
 				Publish:     flags.Publish,
 				Registry:    flags.Registry,
 				PullPolicy:  pullPolicy,
+				Platform:    flags.Platform,
 			}); err != nil {
 				return err
 			}
@@ -78,6 +80,7 @@ Creating a custom builder allows you to control what buildpacks are used and wha
 	cmd.Flags().StringVarP(&flags.BuilderTomlPath, "config", "c", "", "Path to builder TOML file (required)")
 	cmd.Flags().BoolVar(&flags.Publish, "publish", false, "Publish to registry")
 	cmd.Flags().StringVar(&flags.Policy, "pull-policy", "", "Pull policy to use. Accepted values are always, never, and if-not-present. The default is always")
+	cmd.Flags().StringVar(&flags.Platform, "platform", "", "Platform of the builder, in the form '<os>/<arch>[/<variant>]', such as 'linux/arm64'.\nSelects the matching build and run images when they are multi-platform image indexes.")
 
 	AddHelpFlag(cmd, "create")
 	return cmd
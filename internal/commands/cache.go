@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/logging"
+)
+
+func NewCacheCommand(logger logging.Logger, client PackClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Interact with build and launch caches",
+		RunE:  nil,
+	}
+
+	cmd.AddCommand(CacheList(logger, client))
+	cmd.AddCommand(CacheInspect(logger, client))
+	cmd.AddCommand(CacheRemove(logger, client))
+
+	AddHelpFlag(cmd, "cache")
+	return cmd
+}
+
+func cacheImage(image string) string {
+	if image == "" {
+		return "<unknown>"
+	}
+	return image
+}
+
+func cacheSize(size int64) string {
+	if size < 0 {
+		return "<unknown>"
+	}
+	return units.HumanSize(float64(size))
+}
+
+func cacheLastUsed(lastUsed time.Time) string {
+	if lastUsed.IsZero() {
+		return "<unknown>"
+	}
+	return units.HumanDuration(time.Since(lastUsed)) + " ago"
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/logging"
+)
+
+func CacheInspect(logger logging.Logger, client PackClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "inspect <image-name>",
+		Args:    cobra.ExactArgs(1),
+		Short:   "Show the caches of an app image",
+		Example: "pack cache inspect cnbs/sample-app",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			caches, err := client.InspectCache(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			logger.Infof("Caches of %s:", style.Symbol(args[0]))
+			for _, info := range caches {
+				logger.Info("")
+				logger.Infof("%s cache", info.Kind)
+				logger.Infof("  Volume:    %s", info.Volume)
+				logger.Infof("  Image:     %s", cacheImage(info.Image))
+				logger.Infof("  Size:      %s", cacheSize(info.Size))
+				if !info.Created.IsZero() {
+					logger.Infof("  Created:   %s", info.Created.Format(time.RFC3339))
+				}
+				logger.Infof("  Last used: %s", cacheLastUsed(info.LastUsed))
+			}
+			return nil
+		}),
+	}
+
+	AddHelpFlag(cmd, "inspect")
+	return cmd
+}
@@ -0,0 +1,88 @@
+package commands_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack"
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	ilogging "github.com/buildpacks/pack/internal/logging"
+	"github.com/buildpacks/pack/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCacheInspectCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "CacheInspectCommand", testCacheInspectCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testCacheInspectCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		cmd            *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+	)
+
+	it.Before(func() {
+		logger = ilogging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+
+		cmd = commands.CacheInspect(logger, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#CacheInspect", func() {
+		it("shows each cache of the image", func() {
+			mockClient.EXPECT().InspectCache(gomock.Any(), "some/app").Return([]pack.CacheInfo{
+				{
+					Volume:  "pack-cache-123.build",
+					Image:   "index.docker.io/some/app:latest",
+					Kind:    "build",
+					Size:    2000000,
+					Created: time.Date(2020, 10, 1, 10, 0, 0, 0, time.UTC),
+				},
+			}, nil)
+
+			cmd.SetArgs([]string{"some/app"})
+			h.AssertNil(t, cmd.Execute())
+
+			h.AssertContains(t, outBuf.String(), `Caches of 'some/app':
+
+build cache
+  Volume:    pack-cache-123.build
+  Image:     index.docker.io/some/app:latest
+  Size:      2MB
+  Created:   2020-10-01T10:00:00Z
+  Last used: <unknown>
+`)
+		})
+
+		it("requires an image name", func() {
+			cmd.SetArgs([]string{})
+			h.AssertError(t, cmd.Execute(), "accepts 1 arg(s), received 0")
+		})
+
+		it("errors when the image has no caches", func() {
+			mockClient.EXPECT().InspectCache(gomock.Any(), "some/app").Return(nil, errors.New("no caches found"))
+
+			cmd.SetArgs([]string{"some/app"})
+			h.AssertError(t, cmd.Execute(), "no caches found")
+		})
+	})
+}
@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/logging"
+)
+
+func CacheList(logger logging.Logger, client PackClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Args:    cobra.NoArgs,
+		Short:   "List build and launch caches",
+		Long:    "List the build and launch caches kept in docker volumes, along with the app image each cache belongs to.",
+		Example: "pack cache list",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			caches, err := client.ListCaches(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			if len(caches) == 0 {
+				logger.Info("No caches found")
+				return nil
+			}
+
+			tw := tabwriter.NewWriter(logger.Writer(), 0, 0, 3, ' ', 0)
+			fmt.Fprintln(tw, "IMAGE\tKIND\tSIZE\tLAST USED\tVOLUME")
+			for _, info := range caches {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", cacheImage(info.Image), info.Kind, cacheSize(info.Size), cacheLastUsed(info.LastUsed), info.Volume)
+			}
+			return tw.Flush()
+		}),
+	}
+
+	AddHelpFlag(cmd, "list")
+	return cmd
+}
@@ -0,0 +1,91 @@
+package commands_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack"
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	ilogging "github.com/buildpacks/pack/internal/logging"
+	"github.com/buildpacks/pack/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCacheListCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "CacheListCommand", testCacheListCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testCacheListCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		cmd            *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+	)
+
+	it.Before(func() {
+		logger = ilogging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+
+		cmd = commands.CacheList(logger, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#CacheList", func() {
+		it("lists each cache", func() {
+			mockClient.EXPECT().ListCaches(gomock.Any()).Return([]pack.CacheInfo{
+				{
+					Volume:   "pack-cache-123.build",
+					Image:    "index.docker.io/some/app:latest",
+					Kind:     "build",
+					Size:     2000000,
+					LastUsed: time.Now().Add(-3 * time.Hour),
+				},
+				{
+					Volume: "pack-cache-456.launch",
+					Kind:   "launch",
+					Size:   -1,
+				},
+			}, nil)
+
+			cmd.SetArgs([]string{})
+			h.AssertNil(t, cmd.Execute())
+
+			h.AssertContainsMatch(t, outBuf.String(), `IMAGE\s+KIND\s+SIZE\s+LAST USED\s+VOLUME`)
+			h.AssertContainsMatch(t, outBuf.String(), `index.docker.io/some/app:latest\s+build\s+2MB\s+3 hours ago\s+pack-cache-123.build`)
+			h.AssertContainsMatch(t, outBuf.String(), `<unknown>\s+launch\s+<unknown>\s+<unknown>\s+pack-cache-456.launch`)
+		})
+
+		it("reports when there are no caches", func() {
+			mockClient.EXPECT().ListCaches(gomock.Any()).Return(nil, nil)
+
+			cmd.SetArgs([]string{})
+			h.AssertNil(t, cmd.Execute())
+
+			h.AssertContains(t, outBuf.String(), "No caches found")
+		})
+
+		it("errors when the caches cannot be listed", func() {
+			mockClient.EXPECT().ListCaches(gomock.Any()).Return(nil, errors.New("some-error"))
+
+			cmd.SetArgs([]string{})
+			h.AssertError(t, cmd.Execute(), "some-error")
+		})
+	})
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/logging"
+)
+
+type CacheRemoveFlags struct {
+	All bool
+}
+
+func CacheRemove(logger logging.Logger, client PackClient) *cobra.Command {
+	var flags CacheRemoveFlags
+
+	cmd := &cobra.Command{
+		Use:     "rm [<image-name>]",
+		Args:    cobra.MaximumNArgs(1),
+		Short:   "Remove the caches of an app image",
+		Long:    "Remove the build and launch caches of an app image, or every cache with --all.",
+		Example: "pack cache rm cnbs/sample-app",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			opts := pack.RemoveCacheOptions{All: flags.All}
+			switch {
+			case flags.All && len(args) > 0:
+				return errors.New("an image name cannot be used with --all")
+			case !flags.All && len(args) == 0:
+				return errors.New("an image name or --all is required")
+			case len(args) > 0:
+				opts.Image = args[0]
+			}
+
+			removed, err := client.RemoveCache(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+
+			for _, info := range removed {
+				logger.Infof("Removed %s cache %s", info.Kind, style.Symbol(info.Volume))
+			}
+			logger.Infof("Successfully removed %d cache(s)", len(removed))
+			return nil
+		}),
+	}
+	cmd.Flags().BoolVar(&flags.All, "all", false, "Remove every cache")
+	AddHelpFlag(cmd, "rm")
+
+	return cmd
+}
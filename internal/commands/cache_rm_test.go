@@ -0,0 +1,82 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack"
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	ilogging "github.com/buildpacks/pack/internal/logging"
+	"github.com/buildpacks/pack/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCacheRemoveCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "CacheRemoveCommand", testCacheRemoveCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testCacheRemoveCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		cmd            *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+	)
+
+	it.Before(func() {
+		logger = ilogging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+
+		cmd = commands.CacheRemove(logger, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#CacheRemove", func() {
+		it("removes the caches of the image", func() {
+			mockClient.EXPECT().RemoveCache(gomock.Any(), pack.RemoveCacheOptions{Image: "some/app"}).Return([]pack.CacheInfo{
+				{Volume: "pack-cache-123.build", Kind: "build"},
+				{Volume: "pack-cache-123.launch", Kind: "launch"},
+			}, nil)
+
+			cmd.SetArgs([]string{"some/app"})
+			h.AssertNil(t, cmd.Execute())
+
+			h.AssertContains(t, outBuf.String(), "Removed build cache 'pack-cache-123.build'")
+			h.AssertContains(t, outBuf.String(), "Removed launch cache 'pack-cache-123.launch'")
+			h.AssertContains(t, outBuf.String(), "Successfully removed 2 cache(s)")
+		})
+
+		it("removes every cache with --all", func() {
+			mockClient.EXPECT().RemoveCache(gomock.Any(), pack.RemoveCacheOptions{All: true}).Return(nil, nil)
+
+			cmd.SetArgs([]string{"--all"})
+			h.AssertNil(t, cmd.Execute())
+
+			h.AssertContains(t, outBuf.String(), "Successfully removed 0 cache(s)")
+		})
+
+		it("requires an image name or --all", func() {
+			cmd.SetArgs([]string{})
+			h.AssertError(t, cmd.Execute(), "an image name or --all is required")
+		})
+
+		it("does not accept an image name with --all", func() {
+			cmd.SetArgs([]string{"some/app", "--all"})
+			h.AssertError(t, cmd.Execute(), "an image name cannot be used with --all")
+		})
+	})
+}
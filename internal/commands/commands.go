@@ -19,15 +19,24 @@ import (
 //go:generate mockgen -package testmocks -destination testmocks/mock_pack_client.go github.com/buildpacks/pack/internal/commands PackClient
 type PackClient interface {
 	InspectBuilder(string, bool, ...pack.BuilderInspectionModifier) (*pack.BuilderInfo, error)
-	InspectImage(string, bool) (*pack.ImageInfo, error)
+	InspectImage(string, bool, ...pack.ImageInspectionModifier) (*pack.ImageInfo, error)
 	Rebase(context.Context, pack.RebaseOptions) error
+	RebaseWithReport(context.Context, pack.RebaseOptions) (*pack.RebaseReport, error)
+	RebaseAll(context.Context, pack.RebaseAllOptions) []pack.ImageRebaseResult
+	ImageOutdated(context.Context, pack.ImageOutdatedOptions) (*pack.ImageOutdatedReport, error)
 	CreateBuilder(context.Context, pack.CreateBuilderOptions) error
 	PackageBuildpack(ctx context.Context, opts pack.PackageBuildpackOptions) error
 	Build(context.Context, pack.BuildOptions) error
+	BuildAll(context.Context, pack.BuildAllOptions) []pack.AppBuildResult
+	Detect(context.Context, pack.DetectOptions) (*pack.DetectResult, error)
 	RegisterBuildpack(context.Context, pack.RegisterBuildpackOptions) error
 	YankBuildpack(pack.YankBuildpackOptions) error
 	InspectBuildpack(pack.InspectBuildpackOptions) (*pack.BuildpackInfo, error)
 	PullBuildpack(context.Context, pack.PullBuildpackOptions) error
+	ListCaches(context.Context) ([]pack.CacheInfo, error)
+	InspectCache(context.Context, string) ([]pack.CacheInfo, error)
+	RemoveCache(context.Context, pack.RemoveCacheOptions) ([]pack.CacheInfo, error)
+	Prune(context.Context, pack.PruneOptions) (*pack.PruneReport, error)
 }
 
 func AddHelpFlag(cmd *cobra.Command, commandName string) {
@@ -103,3 +112,18 @@ func isTrustedBuilder(cfg config.Config, builder string) bool {
 func deprecationWarning(logger logging.Logger, oldCmd, replacementCmd string) {
 	logger.Warnf("Command %s has been deprecated, please use %s instead", style.Symbol("pack "+oldCmd), style.Symbol("pack "+replacementCmd))
 }
+
+// logToStderr writes the logs of a command, and of the client sharing its logger, to stderr,
+// so that stdout holds nothing but machine-readable output, such as a json report.
+// The returned function writes logs to stdout again.
+func logToStderr(logger logging.Logger) func() {
+	type stderrLogger interface {
+		WantLogsOnStderr(f bool)
+	}
+
+	if l, ok := logger.(stderrLogger); ok {
+		l.WantLogsOnStderr(true)
+		return func() { l.WantLogsOnStderr(false) }
+	}
+	return func() {}
+}
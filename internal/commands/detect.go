@@ -0,0 +1,176 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack"
+	pubcfg "github.com/buildpacks/pack/config"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/logging"
+)
+
+type DetectFlags struct {
+	AppPath        string
+	Builder        string
+	Registry       string
+	DescriptorPath string
+	Network        string
+	Policy         string
+	OutputFormat   string
+	Env            []string
+	EnvFiles       []string
+	Buildpacks     []string
+}
+
+// Detect runs only the detection of a build, explaining the buildpack group it selects
+func Detect(logger logging.Logger, cfg config.Config, packClient PackClient) *cobra.Command {
+	var flags DetectFlags
+
+	cmd := &cobra.Command{
+		Use:   "detect",
+		Args:  cobra.NoArgs,
+		Short: "Run only the detection of a build and explain the buildpack group it selects",
+		Long: "Detect runs the detector of a builder against an app, without building it, and shows the group of " +
+			"buildpacks a build would select along with the result of each buildpack of every group tried.\n\n" +
+			"Optional buildpacks that fail detection are shown as 'skip'. Run with --verbose to also see the output of the detector.",
+		Example: "pack detect --path apps/test-app --builder cnbs/sample-builder:bionic",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.OutputFormat != "human-readable" && flags.OutputFormat != "json" {
+				return errors.Errorf("invalid output format %s, must be one of human-readable or json", style.Symbol(flags.OutputFormat))
+			}
+			if flags.OutputFormat == "json" {
+				defer logToStderr(logger)()
+			}
+
+			descriptor, actualDescriptorPath, err := parseProjectToml(flags.AppPath, flags.DescriptorPath, logger)
+			if err != nil {
+				return err
+			}
+			if actualDescriptorPath != "" {
+				logger.Debugf("Using project descriptor located at %s", style.Symbol(actualDescriptorPath))
+			}
+
+			if descriptor.Build.Builder != "" && !cmd.Flags().Changed("builder") {
+				flags.Builder = descriptor.Build.Builder
+			}
+			if descriptor.Build.PullPolicy != "" && !cmd.Flags().Changed("pull-policy") {
+				flags.Policy = descriptor.Build.PullPolicy
+			}
+
+			if flags.Builder == "" {
+				suggestSettingBuilder(logger, packClient)
+				return pack.NewSoftError()
+			}
+
+			if flags.Registry != "" && !cfg.Experimental {
+				return pack.NewExperimentError("Support for buildpack registries is currently experimental.")
+			}
+
+			env, err := parseEnv(descriptor, flags.EnvFiles, flags.Env)
+			if err != nil {
+				return err
+			}
+
+			buildpacks := flags.Buildpacks
+			if len(buildpacks) == 0 {
+				if buildpacks, err = descriptorBuildpacks(descriptor, actualDescriptorPath); err != nil {
+					return err
+				}
+			}
+
+			pullPolicy, err := pubcfg.ParsePullPolicy(flags.Policy)
+			if err != nil {
+				return errors.Wrapf(err, "parsing pull policy %s", flags.Policy)
+			}
+
+			result, detectErr := packClient.Detect(cmd.Context(), pack.DetectOptions{
+				AppPath:          flags.AppPath,
+				Builder:          flags.Builder,
+				Registry:         flags.Registry,
+				Env:              env,
+				Buildpacks:       buildpacks,
+				InlineBuildpacks: descriptorInlineBuildpacks(descriptor),
+				FileFilter:       descriptor.FileFilter(),
+				PullPolicy:       pullPolicy,
+				Network:          flags.Network,
+			})
+			if result == nil {
+				return errors.Wrap(detectErr, "failed to detect")
+			}
+
+			if flags.OutputFormat == "json" {
+				out, err := json.MarshalIndent(result, "", "  ")
+				if err != nil {
+					return errors.Wrap(err, "encoding detect result")
+				}
+				if _, err := fmt.Fprintln(logger.Writer(), string(out)); err != nil {
+					return err
+				}
+			} else {
+				printDetectResult(logger, result)
+			}
+
+			if detectErr != nil {
+				return errors.Wrap(detectErr, "failed to detect")
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVarP(&flags.AppPath, "path", "p", "", "Path to app dir (defaults to current working directory)")
+	cmd.Flags().StringVarP(&flags.Builder, "builder", "B", cfg.DefaultBuilder, "Builder image")
+	cmd.Flags().StringVarP(&flags.Registry, "buildpack-registry", "r", cfg.DefaultRegistryName, "Buildpack Registry by name")
+	if !cfg.Experimental {
+		cmd.Flags().MarkHidden("buildpack-registry")
+	}
+	cmd.Flags().StringArrayVarP(&flags.Env, "env", "e", []string{}, "Build-time environment variable, in the form 'VAR=VALUE' or 'VAR'.\nThis flag may be specified multiple times and will override\n  individual values defined by --env-file.")
+	cmd.Flags().StringArrayVar(&flags.EnvFiles, "env-file", []string{}, "Build-time environment variables file, in the .env format of docker-compose\nOne variable per line, of the form 'VAR=VALUE' or 'VAR'")
+	cmd.Flags().StringSliceVarP(&flags.Buildpacks, "buildpack", "b", nil, "Buildpack to use, as for 'pack build'"+multiValueHelp("buildpack"))
+	cmd.Flags().StringVar(&flags.Network, "network", "", "Connect the detect container to network")
+	cmd.Flags().StringVarP(&flags.DescriptorPath, "descriptor", "d", "", "Path to the project descriptor file")
+	cmd.Flags().StringVar(&flags.Policy, "pull-policy", "", `Pull policy to use. Accepted values are always, never, and if-not-present. (default "always")`)
+	cmd.Flags().StringVarP(&flags.OutputFormat, "output", "o", "human-readable", "Output format to display the detection results (json, human-readable)")
+	AddHelpFlag(cmd, "detect")
+	return cmd
+}
+
+func printDetectResult(logger logging.Logger, result *pack.DetectResult) {
+	if len(result.Group) == 0 {
+		logger.Info("No buildpack group passed detection")
+	} else {
+		logger.Info("Selected group:")
+		for _, bp := range result.Group {
+			logger.Infof("  %s", detectedBuildpackName(bp.ID, bp.Version))
+		}
+	}
+
+	for i, group := range result.Groups {
+		logger.Info("")
+		logger.Infof("Group %d: %s", i+1, detectedGroupStatus(group))
+		for _, bp := range group.Buildpacks {
+			logger.Infof("  %-5s %s", bp.Status, detectedBuildpackName(bp.ID, bp.Version))
+		}
+	}
+}
+
+func detectedBuildpackName(id, version string) string {
+	if version == "" {
+		return id
+	}
+	return fmt.Sprintf("%s@%s", id, version)
+}
+
+// detectedGroupStatus is 'pass' when none of the buildpacks of a group failed detection, and 'fail' otherwise.
+func detectedGroupStatus(group pack.DetectedGroup) string {
+	for _, bp := range group.Buildpacks {
+		if bp.Status == "fail" || bp.Status == "error" {
+			return "fail"
+		}
+	}
+	return "pass"
+}
@@ -0,0 +1,183 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/buildpacks/lifecycle"
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack"
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	ilogging "github.com/buildpacks/pack/internal/logging"
+	"github.com/buildpacks/pack/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestDetectCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "DetectCommand", testDetectCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testDetectCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		result         *pack.DetectResult
+	)
+
+	it.Before(func() {
+		logger = ilogging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+
+		command = commands.Detect(logger, config.Config{DefaultBuilder: "some/builder"}, mockClient)
+
+		result = &pack.DetectResult{
+			Group: []lifecycle.GroupBuildpack{{ID: "some/other-buildpack", Version: "2.0.0"}},
+			Groups: []pack.DetectedGroup{
+				{Buildpacks: []pack.DetectedBuildpack{
+					{ID: "some/buildpack", Version: "1.0.0", Status: "fail"},
+				}},
+				{Buildpacks: []pack.DetectedBuildpack{
+					{ID: "some/optional-buildpack", Version: "1.0.0", Status: "skip"},
+					{ID: "some/other-buildpack", Version: "2.0.0", Status: "pass"},
+				}},
+			},
+		}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#Detect", func() {
+		it("shows the selected group and the results of each group", func() {
+			mockClient.EXPECT().
+				Detect(gomock.Any(), EqDetectOptionsWithBuilder("some/builder")).
+				Return(result, nil)
+
+			command.SetArgs([]string{"--path", "some/app"})
+			h.AssertNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), `Selected group:
+  some/other-buildpack@2.0.0
+
+Group 1: fail
+  fail  some/buildpack@1.0.0
+
+Group 2: pass
+  skip  some/optional-buildpack@1.0.0
+  pass  some/other-buildpack@2.0.0
+`)
+		})
+
+		it("uses the builder of the flag", func() {
+			mockClient.EXPECT().
+				Detect(gomock.Any(), EqDetectOptionsWithBuilder("my/builder")).
+				Return(result, nil)
+
+			command.SetArgs([]string{"--builder", "my/builder"})
+			h.AssertNil(t, command.Execute())
+		})
+
+		when("--output json", func() {
+			it("shows the results as json", func() {
+				mockClient.EXPECT().
+					Detect(gomock.Any(), gomock.Any()).
+					Return(result, nil)
+
+				command.SetArgs([]string{"--output", "json"})
+				h.AssertNil(t, command.Execute())
+
+				h.AssertContains(t, outBuf.String(), `"group": [`)
+				h.AssertContains(t, outBuf.String(), `{
+          "id": "some/optional-buildpack",
+          "version": "1.0.0",
+          "status": "skip"
+        }`)
+			})
+
+			it("prints nothing but the results on stdout, logging to stderr", func() {
+				var stdout, stderr bytes.Buffer
+				logger := ilogging.NewLogWithWriters(&stdout, &stderr)
+				logger.WantQuiet(true)
+				command := commands.Detect(logger, config.Config{DefaultBuilder: "some/builder"}, mockClient)
+
+				mockClient.EXPECT().
+					Detect(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(context.Context, pack.DetectOptions) (*pack.DetectResult, error) {
+						logger.Warn("some-warning")
+						return result, nil
+					})
+
+				command.SetArgs([]string{"--output", "json"})
+				h.AssertNil(t, command.Execute())
+
+				var detected pack.DetectResult
+				h.AssertNil(t, json.Unmarshal(stdout.Bytes(), &detected))
+				h.AssertEq(t, &detected, result)
+				h.AssertContains(t, stderr.String(), "some-warning")
+			})
+		})
+
+		when("the output format is unknown", func() {
+			it("errors", func() {
+				command.SetArgs([]string{"--output", "yaml"})
+				h.AssertError(t, command.Execute(), "invalid output format 'yaml'")
+			})
+		})
+
+		when("no group passes detection", func() {
+			it("shows the results and errors", func() {
+				result.Group = nil
+				mockClient.EXPECT().
+					Detect(gomock.Any(), gomock.Any()).
+					Return(result, errors.New("detector failed"))
+
+				h.AssertError(t, command.Execute(), "detector failed")
+				h.AssertContains(t, outBuf.String(), "No buildpack group passed detection")
+				h.AssertContains(t, outBuf.String(), "Group 2: pass")
+			})
+		})
+	})
+}
+
+func EqDetectOptionsWithBuilder(builder string) gomock.Matcher {
+	return detectOptionsMatcher{
+		description: "Builder=" + builder,
+		equals: func(o pack.DetectOptions) bool {
+			return o.Builder == builder
+		},
+	}
+}
+
+type detectOptionsMatcher struct {
+	equals      func(pack.DetectOptions) bool
+	description string
+}
+
+func (m detectOptionsMatcher) Matches(x interface{}) bool {
+	if b, ok := x.(pack.DetectOptions); ok {
+		return m.equals(b)
+	}
+	return false
+}
+
+func (m detectOptionsMatcher) String() string {
+	return "is a DetectOptions with " + m.description
+}
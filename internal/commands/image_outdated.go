@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack"
+	pubcfg "github.com/buildpacks/pack/config"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/logging"
+)
+
+type ImageOutdatedFlags struct {
+	Publish        bool
+	RunImage       string
+	Policy         string
+	OutputFormat   string
+	FailIfOutdated bool
+}
+
+type imageOutdatedOutput struct {
+	Image  string                    `json:"image"`
+	Report *pack.ImageOutdatedReport `json:"report,omitempty"`
+	Error  string                    `json:"error,omitempty"`
+}
+
+// ImageOutdated reports the app images whose run image has been updated since they were built
+func ImageOutdated(logger logging.Logger, cfg config.Config, client PackClient) *cobra.Command {
+	var flags ImageOutdatedFlags
+
+	cmd := &cobra.Command{
+		Use:   "outdated <image-name>...",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Report app images whose run image has been updated",
+		Long: "Outdated compares the run image each app image was built on with the current run image, resolved from the app image " +
+			"and the run image mirrors of the config as `pack rebase` does, and reports the images a rebase would update.\n\n" +
+			"With --fail-if-outdated, pack exits with status 2 when any image is outdated, so that CI can gate on it.",
+		Example: "pack image outdated buildpacksio/pack --publish --fail-if-outdated",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.OutputFormat != "human-readable" && flags.OutputFormat != "json" {
+				return errors.Errorf("invalid output format %s, must be one of human-readable or json", style.Symbol(flags.OutputFormat))
+			}
+			if flags.OutputFormat == "json" {
+				defer logToStderr(logger)()
+			}
+
+			pullPolicy, err := pubcfg.ParsePullPolicy(flags.Policy)
+			if err != nil {
+				return errors.Wrapf(err, "parsing pull policy %s", flags.Policy)
+			}
+
+			var outputs []imageOutdatedOutput
+			failed, outdated := 0, 0
+			for _, img := range args {
+				output := imageOutdatedOutput{Image: img}
+				output.Report, err = client.ImageOutdated(cmd.Context(), pack.ImageOutdatedOptions{
+					RepoName:          img,
+					Publish:           flags.Publish,
+					PullPolicy:        pullPolicy,
+					RunImage:          flags.RunImage,
+					AdditionalMirrors: getMirrors(cfg),
+				})
+				switch {
+				case err != nil:
+					failed++
+					output.Error = err.Error()
+				case output.Report.Outdated:
+					outdated++
+				}
+				outputs = append(outputs, output)
+			}
+
+			if flags.OutputFormat == "json" {
+				out, err := json.MarshalIndent(outputs, "", "  ")
+				if err != nil {
+					return errors.Wrap(err, "encoding outdated images")
+				}
+				if _, err := fmt.Fprintln(logger.Writer(), string(out)); err != nil {
+					return err
+				}
+			} else if err := printOutdatedImages(logger, outputs); err != nil {
+				return err
+			}
+
+			if failed > 0 {
+				return errors.Errorf("failed to check %d of %d images", failed, len(outputs))
+			}
+			if outdated > 0 && flags.FailIfOutdated {
+				return pack.NewSoftError()
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().BoolVar(&flags.Publish, "publish", false, "Check images in a registry, rather than the docker daemon")
+	cmd.Flags().StringVar(&flags.RunImage, "run-image", "", "Run image to compare the images against")
+	cmd.Flags().StringVar(&flags.Policy, "pull-policy", "", `Pull policy to use. Accepted values are always, never, and if-not-present. (default "always")`)
+	cmd.Flags().StringVarP(&flags.OutputFormat, "output", "o", "human-readable", "Output format (json, human-readable)")
+	cmd.Flags().BoolVar(&flags.FailIfOutdated, "fail-if-outdated", false, "Exit with status 2 when any image is outdated")
+	AddHelpFlag(cmd, "outdated")
+	return cmd
+}
+
+func printOutdatedImages(logger logging.Logger, outputs []imageOutdatedOutput) error {
+	tw := tabwriter.NewWriter(logger.Writer(), 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "IMAGE\tSTATUS\tRUN IMAGE\tCURRENT DIGEST\tLATEST DIGEST")
+	for _, output := range outputs {
+		if output.Report == nil {
+			continue
+		}
+		status := "up to date"
+		if output.Report.Outdated {
+			status = "outdated"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", output.Image, status, output.Report.RunImage,
+			digestOrUnknown(output.Report.CurrentBase.Digest), digestOrUnknown(output.Report.LatestBase.Digest))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, output := range outputs {
+		if output.Error != "" {
+			logger.Errorf("failed to check image %s: %s", style.Symbol(output.Image), output.Error)
+		}
+	}
+	return nil
+}
+
+func digestOrUnknown(digest string) string {
+	if digest == "" {
+		return "<unknown>"
+	}
+	return digest
+}
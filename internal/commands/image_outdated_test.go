@@ -0,0 +1,198 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack"
+	pubcfg "github.com/buildpacks/pack/config"
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	ilogging "github.com/buildpacks/pack/internal/logging"
+	"github.com/buildpacks/pack/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestImageOutdatedCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "ImageOutdatedCommand", testImageOutdatedCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testImageOutdatedCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		outdatedReport *pack.ImageOutdatedReport
+		currentReport  *pack.ImageOutdatedReport
+	)
+
+	it.Before(func() {
+		logger = ilogging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+
+		cfg := config.Config{
+			RunImages: []config.RunImage{{
+				Image:   "some/run",
+				Mirrors: []string{"example.com/some/run"},
+			}},
+		}
+		command = commands.ImageOutdated(logger, cfg, mockClient)
+
+		outdatedReport = &pack.ImageOutdatedReport{
+			Image:       "some/app",
+			RunImage:    "some/run",
+			CurrentBase: pack.RebaseBase{TopLayer: "old-top-layer", Digest: "sha256:old-digest"},
+			LatestBase:  pack.RebaseBase{TopLayer: "new-top-layer", Digest: "sha256:new-digest"},
+			Outdated:    true,
+		}
+		currentReport = &pack.ImageOutdatedReport{
+			Image:       "other/app",
+			RunImage:    "some/run",
+			CurrentBase: pack.RebaseBase{TopLayer: "new-top-layer"},
+			LatestBase:  pack.RebaseBase{TopLayer: "new-top-layer", Digest: "sha256:new-digest"},
+		}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#ImageOutdated", func() {
+		it("reports which images are outdated", func() {
+			mockClient.EXPECT().
+				ImageOutdated(gomock.Any(), pack.ImageOutdatedOptions{
+					RepoName:          "some/app",
+					PullPolicy:        pubcfg.PullAlways,
+					AdditionalMirrors: map[string][]string{"some/run": {"example.com/some/run"}},
+				}).
+				Return(outdatedReport, nil)
+			mockClient.EXPECT().
+				ImageOutdated(gomock.Any(), pack.ImageOutdatedOptions{
+					RepoName:          "other/app",
+					PullPolicy:        pubcfg.PullAlways,
+					AdditionalMirrors: map[string][]string{"some/run": {"example.com/some/run"}},
+				}).
+				Return(currentReport, nil)
+
+			command.SetArgs([]string{"some/app", "other/app"})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), `IMAGE       STATUS       RUN IMAGE   CURRENT DIGEST      LATEST DIGEST
+some/app    outdated     some/run    sha256:old-digest   sha256:new-digest
+other/app   up to date   some/run    <unknown>           sha256:new-digest
+`)
+		})
+
+		when("--publish, --run-image and --pull-policy", func() {
+			it("passes them through", func() {
+				mockClient.EXPECT().
+					ImageOutdated(gomock.Any(), pack.ImageOutdatedOptions{
+						RepoName:          "some/app",
+						Publish:           true,
+						PullPolicy:        pubcfg.PullNever,
+						RunImage:          "some/other-run",
+						AdditionalMirrors: map[string][]string{"some/run": {"example.com/some/run"}},
+					}).
+					Return(currentReport, nil)
+
+				command.SetArgs([]string{"some/app", "--publish", "--run-image", "some/other-run", "--pull-policy", "never"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("--output json", func() {
+			it("prints the report of every image", func() {
+				mockClient.EXPECT().ImageOutdated(gomock.Any(), gomock.Any()).Return(outdatedReport, nil)
+
+				command.SetArgs([]string{"some/app", "--output", "json"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), `"outdated": true`)
+				h.AssertContains(t, outBuf.String(), `"digest": "sha256:new-digest"`)
+			})
+
+			it("prints nothing but the reports on stdout, logging to stderr", func() {
+				var stdout, stderr bytes.Buffer
+				logger := ilogging.NewLogWithWriters(&stdout, &stderr)
+				command := commands.ImageOutdated(logger, config.Config{}, mockClient)
+
+				mockClient.EXPECT().
+					ImageOutdated(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(context.Context, pack.ImageOutdatedOptions) (*pack.ImageOutdatedReport, error) {
+						logger.Info("Pulling image 'some/run'")
+						return outdatedReport, nil
+					})
+
+				command.SetArgs([]string{"some/app", "--output", "json"})
+				h.AssertNil(t, command.Execute())
+
+				var outputs []struct {
+					Image  string                    `json:"image"`
+					Report *pack.ImageOutdatedReport `json:"report"`
+				}
+				h.AssertNil(t, json.Unmarshal(stdout.Bytes(), &outputs))
+				h.AssertEq(t, len(outputs), 1)
+				h.AssertEq(t, outputs[0].Report, outdatedReport)
+				h.AssertContains(t, stderr.String(), "Pulling image 'some/run'")
+			})
+		})
+
+		when("--fail-if-outdated", func() {
+			it("fails with a soft error when an image is outdated", func() {
+				mockClient.EXPECT().ImageOutdated(gomock.Any(), gomock.Any()).Return(outdatedReport, nil)
+
+				command.SetArgs([]string{"some/app", "--fail-if-outdated"})
+				err := command.Execute()
+				_, isSoftError := err.(pack.SoftError)
+				h.AssertEq(t, isSoftError, true)
+			})
+
+			it("succeeds when every image is up to date", func() {
+				mockClient.EXPECT().ImageOutdated(gomock.Any(), gomock.Any()).Return(currentReport, nil)
+
+				command.SetArgs([]string{"other/app", "--fail-if-outdated"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("an image cannot be checked", func() {
+			it("checks the other images and reports the failure", func() {
+				mockClient.EXPECT().ImageOutdated(gomock.Any(), gomock.Any()).Return(nil, errors.New("some-error"))
+				mockClient.EXPECT().ImageOutdated(gomock.Any(), gomock.Any()).Return(currentReport, nil)
+
+				command.SetArgs([]string{"some/app", "other/app"})
+				h.AssertError(t, command.Execute(), "failed to check 1 of 2 images")
+				h.AssertContains(t, outBuf.String(), "ERROR: failed to check image 'some/app': some-error")
+				h.AssertContains(t, outBuf.String(), "other/app")
+			})
+		})
+
+		when("no image is provided", func() {
+			it("errors", func() {
+				command.SetArgs([]string{})
+				h.AssertError(t, command.Execute(), "requires at least 1 arg")
+			})
+		})
+
+		when("--output is unknown", func() {
+			it("errors", func() {
+				command.SetArgs([]string{"some/app", "--output", "yaml"})
+				h.AssertError(t, command.Execute(), "invalid output format 'yaml'")
+			})
+		})
+	})
+}
@@ -0,0 +1,49 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	ilogging "github.com/buildpacks/pack/internal/logging"
+	"github.com/buildpacks/pack/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestImageCommand(t *testing.T) {
+	spec.Run(t, "ImageCommand", testImageCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testImageCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		cmd    *cobra.Command
+		logger logging.Logger
+		outBuf bytes.Buffer
+	)
+
+	it.Before(func() {
+		logger = ilogging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController := gomock.NewController(t)
+		mockClient := testmocks.NewMockPackClient(mockController)
+		cmd = commands.NewImageCommand(logger, config.Config{}, mockClient)
+		cmd.SetOut(logging.GetWriterForLevel(logger, logging.InfoLevel))
+	})
+
+	when("image", func() {
+		it("prints help text", func() {
+			cmd.SetArgs([]string{})
+			h.AssertNil(t, cmd.Execute())
+			output := outBuf.String()
+			h.AssertContains(t, output, "Interact with app images")
+			h.AssertContains(t, output, "Usage:")
+			h.AssertContains(t, output, "outdated")
+		})
+	})
+}
@@ -3,6 +3,7 @@ package commands
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/buildpacks/pack"
 	"github.com/buildpacks/pack/internal/inspectimage"
 
 	"github.com/buildpacks/pack/internal/inspectimage/writer"
@@ -18,6 +19,7 @@ type InspectImageWriterFactory interface {
 
 type InspectImageFlags struct {
 	BOM          bool
+	Layers       bool
 	OutputFormat string
 }
 
@@ -46,8 +48,13 @@ func InspectImage(
 				return err
 			}
 
-			remote, remoteErr := client.InspectImage(img, false)
-			local, localErr := client.InspectImage(img, true)
+			var modifiers []pack.ImageInspectionModifier
+			if flags.Layers {
+				modifiers = append(modifiers, pack.WithImageLayers())
+			}
+
+			remote, remoteErr := client.InspectImage(img, false, modifiers...)
+			local, localErr := client.InspectImage(img, true, modifiers...)
 
 			if err := w.Print(logger, sharedImageInfo, local, remote, localErr, remoteErr); err != nil {
 				return err
@@ -57,6 +64,7 @@ func InspectImage(
 	}
 	AddHelpFlag(cmd, "inspect-image")
 	cmd.Flags().BoolVar(&flags.BOM, "bom", false, "print bill of materials")
+	cmd.Flags().BoolVar(&flags.Layers, "layers", false, "print the layers of the image with their sizes, and what added each of them.\nThe layers of remote images are downloaded to measure them.")
 	cmd.Flags().StringVarP(&flags.OutputFormat, "output", "o", "human-readable", "Output format to display builder detail (json, yaml, toml, human-readable).\nOmission of this flag will display as human-readable.")
 	return cmd
 }
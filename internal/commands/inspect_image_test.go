@@ -133,6 +133,33 @@ func testInspectImageCommand(t *testing.T, when spec.G, it spec.S) {
 			assert.Equal(inspectImageWriter.RecievedGeneralInfo.RunImageMirrors, cfg.RunImages)
 		})
 
+		when("--layers", func() {
+			it("inspects the images with their layers", func() {
+				inspectImageWriter := newDefaultInspectImageWriter()
+				inspectImageWriterFactory := newImageWriterFactory(inspectImageWriter)
+
+				withLayers := func(info *pack.ImageInfo) func(string, bool, ...pack.ImageInspectionModifier) (*pack.ImageInfo, error) {
+					return func(_ string, _ bool, modifiers ...pack.ImageInspectionModifier) (*pack.ImageInfo, error) {
+						var inspectionConfig pack.ImageInspectionConfig
+						for _, mod := range modifiers {
+							mod(&inspectionConfig)
+						}
+						assert.Equal(inspectionConfig.Layers, true)
+						return info, nil
+					}
+				}
+				mockClient.EXPECT().InspectImage("some/image", true, gomock.Any()).DoAndReturn(withLayers(expectedLocalImageInfo))
+				mockClient.EXPECT().InspectImage("some/image", false, gomock.Any()).DoAndReturn(withLayers(expectedRemoteImageInfo))
+
+				command := commands.InspectImage(logger, inspectImageWriterFactory, cfg, mockClient)
+				command.SetArgs([]string{"some/image", "--layers"})
+				assert.Nil(command.Execute())
+
+				assert.Equal(inspectImageWriter.ReceivedInfoForLocal, expectedLocalImageInfo)
+				assert.Equal(inspectImageWriter.ReceivedInfoForRemote, expectedRemoteImageInfo)
+			})
+		})
+
 		when("error cases", func() {
 			when("client returns an error when inspecting", func() {
 				it("passes errors to the Writer", func() {
@@ -0,0 +1,19 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/logging"
+)
+
+func NewProjectCommand(logger logging.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "project",
+		Short: "Interact with project descriptors",
+		RunE:  nil,
+	}
+
+	cmd.AddCommand(ProjectMigrate(logger))
+	AddHelpFlag(cmd, "project")
+	return cmd
+}
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/logging"
+	"github.com/buildpacks/pack/project"
+)
+
+type ProjectMigrateFlags struct {
+	DescriptorPath string
+}
+
+func ProjectMigrate(logger logging.Logger) *cobra.Command {
+	var flags ProjectMigrateFlags
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Args:  cobra.NoArgs,
+		Short: "Migrate a project descriptor to the latest schema version",
+		Long: "Rewrite a project descriptor in the layout of schema version " + project.LatestSchemaVersion + ", " +
+			"with a [_] table and an [io.buildpacks] table.\n" +
+			"Metadata and tables of other tools are kept, but comments are not.",
+		Example: "pack project migrate --descriptor app/project.toml",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			info, err := os.Stat(flags.DescriptorPath)
+			if err != nil {
+				return errors.Wrap(err, "stat project descriptor")
+			}
+
+			contents, err := ioutil.ReadFile(flags.DescriptorPath)
+			if err != nil {
+				return errors.Wrap(err, "reading project descriptor")
+			}
+
+			migrated, changed, err := project.Migrate(string(contents))
+			if err != nil {
+				return errors.Wrapf(err, "migrating %s", style.Symbol(flags.DescriptorPath))
+			}
+
+			if !changed {
+				logger.Infof("Project descriptor %s already has schema version %s", style.Symbol(flags.DescriptorPath), project.LatestSchemaVersion)
+				return nil
+			}
+
+			if err := ioutil.WriteFile(flags.DescriptorPath, []byte(migrated), info.Mode()); err != nil {
+				return errors.Wrap(err, "writing project descriptor")
+			}
+
+			logger.Infof("Migrated project descriptor %s to schema version %s", style.Symbol(flags.DescriptorPath), project.LatestSchemaVersion)
+			return nil
+		}),
+	}
+	cmd.Flags().StringVarP(&flags.DescriptorPath, "descriptor", "d", "project.toml", "Path to the project descriptor file")
+	AddHelpFlag(cmd, "migrate")
+
+	return cmd
+}
@@ -0,0 +1,89 @@
+package commands_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	ilogging "github.com/buildpacks/pack/internal/logging"
+	"github.com/buildpacks/pack/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestProjectMigrateCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ProjectMigrateCommand", testProjectMigrateCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testProjectMigrateCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		cmd            *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		tmpDir         string
+		descriptorPath string
+	)
+
+	it.Before(func() {
+		logger = ilogging.NewLogWithWriters(&outBuf, &outBuf)
+		cmd = commands.ProjectMigrate(logger)
+
+		var err error
+		tmpDir, err = ioutil.TempDir("", "project-migrate-command-test")
+		h.AssertNil(t, err)
+		descriptorPath = filepath.Join(tmpDir, "project.toml")
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	when("#ProjectMigrate", func() {
+		it("rewrites a descriptor to the latest schema version", func() {
+			h.AssertNil(t, ioutil.WriteFile(descriptorPath, []byte(`
+[project]
+name = "gallant"
+
+[[build.buildpacks]]
+id = "example/lua"
+version = "1.0"
+`), 0644))
+
+			cmd.SetArgs([]string{"--descriptor", descriptorPath})
+			h.AssertNil(t, cmd.Execute())
+
+			contents, err := ioutil.ReadFile(descriptorPath)
+			h.AssertNil(t, err)
+			h.AssertContains(t, string(contents), `schema-version = "0.2"`)
+			h.AssertContains(t, string(contents), "[[io.buildpacks.group]]")
+			h.AssertContains(t, outBuf.String(), "Migrated project descriptor '"+descriptorPath+"' to schema version 0.2")
+		})
+
+		it("leaves a descriptor of the latest schema version as it is", func() {
+			original := "# comment\n[_]\nschema-version = \"0.2\"\n"
+			h.AssertNil(t, ioutil.WriteFile(descriptorPath, []byte(original), 0644))
+
+			cmd.SetArgs([]string{"--descriptor", descriptorPath})
+			h.AssertNil(t, cmd.Execute())
+
+			contents, err := ioutil.ReadFile(descriptorPath)
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(contents), original)
+			h.AssertContains(t, outBuf.String(), "already has schema version 0.2")
+		})
+
+		it("fails when the descriptor does not exist", func() {
+			cmd.SetArgs([]string{"--descriptor", descriptorPath})
+			h.AssertError(t, cmd.Execute(), "stat project descriptor")
+		})
+	})
+}
@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"sort"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/logging"
+)
+
+type PruneFlags struct {
+	DryRun        bool
+	OlderThan     time.Duration
+	IncludeCaches bool
+}
+
+func Prune(logger logging.Logger, client PackClient) *cobra.Command {
+	var flags PruneFlags
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Args:  cobra.NoArgs,
+		Short: "Remove resources left behind by interrupted builds",
+		Long: "Remove the phase containers, volumes and ephemeral builder images left behind by interrupted builds.\n\n" +
+			"Running containers and volumes and builders still in use are left alone, so that builds in progress are not affected.\n" +
+			"Ephemeral builders kept for reuse by later builds are only removed with --older-than.",
+		Example: "pack prune --older-than 24h",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			report, err := client.Prune(cmd.Context(), pack.PruneOptions{
+				DryRun:        flags.DryRun,
+				OlderThan:     flags.OlderThan,
+				IncludeCaches: flags.IncludeCaches,
+			})
+			if err != nil {
+				return err
+			}
+
+			action := "Removed"
+			if flags.DryRun {
+				action = "Would remove"
+			}
+			for _, resource := range report.Removed {
+				logger.Infof("%s %s %s (%s)", action, resource.Kind, style.Symbol(resource.Name), units.HumanSize(float64(resource.Size)))
+			}
+
+			var failed []string
+			for name := range report.Failed {
+				failed = append(failed, name)
+			}
+			sort.Strings(failed)
+			for _, name := range failed {
+				logger.Warnf("Unable to remove %s: %s", style.Symbol(name), report.Failed[name])
+			}
+
+			if flags.DryRun {
+				logger.Infof("Would reclaim %s", units.HumanSize(float64(report.SpaceReclaimed)))
+			} else {
+				logger.Infof("Reclaimed %s", units.HumanSize(float64(report.SpaceReclaimed)))
+			}
+			return nil
+		}),
+	}
+	cmd.Flags().BoolVar(&flags.DryRun, "dry-run", false, "Show what would be removed, without removing anything")
+	cmd.Flags().DurationVar(&flags.OlderThan, "older-than", 0, "Only remove resources created longer ago than this duration, e.g. '24h'."+
+		"\nRunning containers older than this are also removed")
+	cmd.Flags().BoolVar(&flags.IncludeCaches, "include-caches", false, "Also remove build and launch caches")
+	AddHelpFlag(cmd, "prune")
+
+	return cmd
+}
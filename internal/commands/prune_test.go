@@ -0,0 +1,94 @@
+package commands_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack"
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	ilogging "github.com/buildpacks/pack/internal/logging"
+	"github.com/buildpacks/pack/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestPruneCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "PruneCommand", testPruneCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testPruneCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		cmd            *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		pruneReport    *pack.PruneReport
+	)
+
+	it.Before(func() {
+		logger = ilogging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+
+		cmd = commands.Prune(logger, mockClient)
+
+		pruneReport = &pack.PruneReport{
+			Removed: []pack.PrunedResource{
+				{Kind: pack.PruneKindContainer, Name: "some-container", Size: 1000},
+				{Kind: pack.PruneKindVolume, Name: "pack-layers-abc", Size: 2000000},
+			},
+			Failed:         map[string]error{"pack-app-abc": errors.New("volume is in use")},
+			SpaceReclaimed: 2001000,
+		}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#Prune", func() {
+		it("reports the removed resources and reclaimed space", func() {
+			mockClient.EXPECT().Prune(gomock.Any(), pack.PruneOptions{}).Return(pruneReport, nil)
+
+			cmd.SetArgs([]string{})
+			h.AssertNil(t, cmd.Execute())
+
+			h.AssertContains(t, outBuf.String(), "Removed container 'some-container' (1kB)")
+			h.AssertContains(t, outBuf.String(), "Removed volume 'pack-layers-abc' (2MB)")
+			h.AssertContains(t, outBuf.String(), "Warning: Unable to remove 'pack-app-abc': volume is in use")
+			h.AssertContains(t, outBuf.String(), "Reclaimed 2.001MB")
+		})
+
+		it("passes the flags through", func() {
+			mockClient.EXPECT().Prune(gomock.Any(), pack.PruneOptions{
+				DryRun:        true,
+				OlderThan:     24 * time.Hour,
+				IncludeCaches: true,
+			}).Return(pruneReport, nil)
+
+			cmd.SetArgs([]string{"--dry-run", "--older-than", "24h", "--include-caches"})
+			h.AssertNil(t, cmd.Execute())
+
+			h.AssertContains(t, outBuf.String(), "Would remove container 'some-container' (1kB)")
+			h.AssertContains(t, outBuf.String(), "Would reclaim 2.001MB")
+		})
+
+		it("errors when the resources cannot be listed", func() {
+			mockClient.EXPECT().Prune(gomock.Any(), gomock.Any()).Return(nil, errors.New("some-error"))
+
+			cmd.SetArgs([]string{})
+			h.AssertError(t, cmd.Execute(), "some-error")
+		})
+	})
+}
@@ -1,6 +1,13 @@
 package commands
 
 import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
@@ -14,29 +21,79 @@ import (
 
 func Rebase(logger logging.Logger, cfg config.Config, client PackClient) *cobra.Command {
 	var opts pack.RebaseOptions
-	var policy string
+	var policy, outputFormat, fromFile string
+	var parallel int
 
 	cmd := &cobra.Command{
-		Use:     "rebase <image-name>",
-		Args:    cobra.ExactArgs(1),
+		Use:     "rebase <image-name>...",
+		Args:    cobra.ArbitraryArgs,
 		Short:   "Rebase app image with latest run image",
-		Example: "pack rebase buildpacksio/pack",
+		Example: "pack rebase buildpacksio/pack\npack rebase --from-file images.txt --parallel 4",
 		Long: "Rebase allows you to quickly swap out the underlying OS layers (run image) of an app image generated by `pack build` " +
-			"with a newer version of the run image, without re-building the application.",
+			"with a newer version of the run image, without re-building the application.\n\n" +
+			"Several images may be rebased at once, by name or listed in a file with --from-file. Images shared by the rebases, " +
+			"such as the run image, are pulled once, and the failed rebases are reported once every rebase has finished.",
 		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
-			opts.RepoName = args[0]
 			opts.AdditionalMirrors = getMirrors(cfg)
 
+			if outputFormat != "human-readable" && outputFormat != "json" {
+				return errors.Errorf("invalid output format %s, must be one of human-readable or json", style.Symbol(outputFormat))
+			}
+			if outputFormat == "json" {
+				defer logToStderr(logger)()
+			}
+
+			if parallel < 1 {
+				return errors.New("parallel flag must be at least 1")
+			}
+
 			var err error
 			opts.PullPolicy, err = pubcfg.ParsePullPolicy(policy)
 			if err != nil {
 				return errors.Wrapf(err, "parsing pull policy %s", policy)
 			}
 
-			if err := client.Rebase(cmd.Context(), opts); err != nil {
+			images := args
+			if fromFile != "" {
+				listed, err := readImageList(fromFile)
+				if err != nil {
+					return errors.Wrapf(err, "reading images from %s", style.Symbol(fromFile))
+				}
+				images = append(images, listed...)
+			}
+
+			switch {
+			case len(images) == 0:
+				return errors.New("no images to rebase, pass image names or --from-file")
+			case len(images) > 1:
+				return rebaseAll(cmd, logger, client, images, opts, parallel, outputFormat)
+			}
+
+			opts.RepoName = images[0]
+
+			if !opts.DryRun && outputFormat == "human-readable" {
+				if err := client.Rebase(cmd.Context(), opts); err != nil {
+					return err
+				}
+				logger.Infof("Successfully rebased image %s", style.Symbol(opts.RepoName))
+				return nil
+			}
+
+			report, err := client.RebaseWithReport(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+
+			if outputFormat == "json" {
+				out, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return errors.Wrap(err, "encoding rebase report")
+				}
+				_, err = fmt.Fprintln(logger.Writer(), string(out))
 				return err
 			}
-			logger.Infof("Successfully rebased image %s", style.Symbol(opts.RepoName))
+
+			printRebaseReport(logger, report)
 			return nil
 		}),
 	}
@@ -44,7 +101,107 @@ func Rebase(logger logging.Logger, cfg config.Config, client PackClient) *cobra.
 	cmd.Flags().BoolVar(&opts.Publish, "publish", false, "Publish to registry")
 	cmd.Flags().StringVar(&opts.RunImage, "run-image", "", "Run image to use for rebasing")
 	cmd.Flags().StringVar(&policy, "pull-policy", "", "Pull policy to use. Accepted values are always, never, and if-not-present. The default is always")
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "Rebase even when the run image is of another stack than the app image,\nor lacks mixins the app image requires")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Report what the rebase would change, without changing the image")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "human-readable", "Output format of the rebase report (json, human-readable).\nWith json, a report of the rebase is also printed without --dry-run.")
+	cmd.Flags().StringVar(&fromFile, "from-file", "", "File listing images to rebase, one per line.\nBlank lines and lines starting with '#' are ignored.")
+	cmd.Flags().IntVar(&parallel, "parallel", 1, "Number of rebases to run at the same time, when rebasing several images")
+	cmd.Flags().StringVar(&opts.Platform, "platform", "", "Platform of the image, in the form '<os>/<arch>[/<variant>]', such as 'linux/arm64'.\nSelects the matching run image when it is a multi-platform image index.")
 
 	AddHelpFlag(cmd, "rebase")
 	return cmd
 }
+
+type imageRebaseOutput struct {
+	Image  string             `json:"image"`
+	Report *pack.RebaseReport `json:"report,omitempty"`
+	Error  string             `json:"error,omitempty"`
+}
+
+// rebaseAll rebases several images, reporting the failed rebases once every rebase has finished.
+func rebaseAll(cmd *cobra.Command, logger logging.Logger, client PackClient, images []string, opts pack.RebaseOptions, parallel int, outputFormat string) error {
+	results := client.RebaseAll(cmd.Context(), pack.RebaseAllOptions{
+		Images:   images,
+		Options:  opts,
+		Parallel: parallel,
+		Report:   opts.DryRun || outputFormat == "json",
+	})
+
+	failed := 0
+	var outputs []imageRebaseOutput
+	for _, result := range results {
+		output := imageRebaseOutput{Image: result.Image, Report: result.Report}
+		if result.Err != nil {
+			failed++
+			output.Error = result.Err.Error()
+		}
+		outputs = append(outputs, output)
+	}
+
+	if outputFormat == "json" {
+		out, err := json.MarshalIndent(outputs, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "encoding rebase reports")
+		}
+		if _, err := fmt.Fprintln(logger.Writer(), string(out)); err != nil {
+			return err
+		}
+	} else {
+		logger.Info(style.Step("SUMMARY"))
+		for _, result := range results {
+			switch {
+			case result.Err != nil:
+				logger.Errorf("failed to rebase image %s: %s", style.Symbol(result.Image), result.Err)
+			case result.Report != nil:
+				printRebaseReport(logger, result.Report)
+			default:
+				logger.Infof("Successfully rebased image %s", style.Symbol(result.Image))
+			}
+		}
+	}
+
+	if failed > 0 {
+		return errors.Errorf("%d of %d rebases failed", failed, len(results))
+	}
+	if !opts.DryRun && outputFormat == "human-readable" {
+		logger.Infof("Successfully rebased %d images", len(results))
+	}
+	return nil
+}
+
+// readImageList reads the names of images from a file listing one per line, ignoring blank lines and comments.
+func readImageList(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var images []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		images = append(images, line)
+	}
+	return images, scanner.Err()
+}
+
+func printRebaseReport(logger logging.Logger, report *pack.RebaseReport) {
+	tw := tabwriter.NewWriter(logger.Writer(), 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Image:\t%s\n", report.Image)
+	fmt.Fprintf(tw, "Run image:\t%s\n", report.RunImage)
+	fmt.Fprintf(tw, "Previous base:\t%s (top layer %s)\n", report.PreviousBase.Digest, report.PreviousBase.TopLayer)
+	fmt.Fprintf(tw, "New base:\t%s (top layer %s)\n", report.NewBase.Digest, report.NewBase.TopLayer)
+	fmt.Fprintf(tw, "Layers swapped:\t%d\n", report.LayersSwapped)
+	if report.ImageDigest != "" {
+		fmt.Fprintf(tw, "Rebased image:\t%s\n", report.ImageDigest)
+	}
+	tw.Flush()
+
+	if report.DryRun {
+		logger.Info("Dry run, the image was not changed")
+	}
+}
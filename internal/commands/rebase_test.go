@@ -2,8 +2,16 @@ package commands_test
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/pkg/errors"
+
 	"github.com/heroku/color"
 
 	pubcfg "github.com/buildpacks/pack/config"
@@ -52,7 +60,7 @@ func testRebaseCommand(t *testing.T, when spec.G, it spec.S) {
 		when("no image is provided", func() {
 			it("fails to run", func() {
 				err := command.Execute()
-				h.AssertError(t, err, "accepts 1 arg")
+				h.AssertError(t, err, "no images to rebase")
 			})
 		})
 
@@ -105,6 +113,123 @@ func testRebaseCommand(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			when("--dry-run", func() {
+				it("shows what the rebase would change", func() {
+					opts.DryRun = true
+					mockClient.EXPECT().
+						RebaseWithReport(gomock.Any(), opts).
+						Return(&pack.RebaseReport{
+							Image:         repoName,
+							RunImage:      "test/image",
+							PreviousBase:  pack.RebaseBase{TopLayer: "sha256:old-top", Digest: "sha256:old"},
+							NewBase:       pack.RebaseBase{TopLayer: "sha256:new-top", Digest: "sha256:new"},
+							LayersSwapped: 3,
+							DryRun:        true,
+						}, nil)
+
+					command.SetArgs([]string{repoName, "--dry-run"})
+					h.AssertNil(t, command.Execute())
+
+					h.AssertContains(t, outBuf.String(), `Image:           test/repo-image
+Run image:       test/image
+Previous base:   sha256:old (top layer sha256:old-top)
+New base:        sha256:new (top layer sha256:new-top)
+Layers swapped:  3
+Dry run, the image was not changed`)
+				})
+
+				when("--output json", func() {
+					it("prints the report as json", func() {
+						opts.DryRun = true
+						mockClient.EXPECT().
+							RebaseWithReport(gomock.Any(), opts).
+							Return(&pack.RebaseReport{
+								Image:         repoName,
+								LayersSwapped: 3,
+								DryRun:        true,
+							}, nil)
+
+						command.SetArgs([]string{repoName, "--dry-run", "--output", "json"})
+						h.AssertNil(t, command.Execute())
+
+						h.AssertContains(t, outBuf.String(), `"image": "test/repo-image"`)
+						h.AssertContains(t, outBuf.String(), `"layersSwapped": 3`)
+						h.AssertContains(t, outBuf.String(), `"dryRun": true`)
+						h.AssertNotContains(t, outBuf.String(), "Successfully rebased")
+					})
+				})
+			})
+
+			when("--output json", func() {
+				it("rebases and prints the report with the digest of the image", func() {
+					mockClient.EXPECT().
+						RebaseWithReport(gomock.Any(), opts).
+						Return(&pack.RebaseReport{
+							Image:       repoName,
+							ImageDigest: "sha256:rebased",
+						}, nil)
+
+					command.SetArgs([]string{repoName, "--output", "json"})
+					h.AssertNil(t, command.Execute())
+
+					h.AssertContains(t, outBuf.String(), `"imageDigest": "sha256:rebased"`)
+				})
+
+				it("prints nothing but the report on stdout, logging to stderr", func() {
+					var stdout, stderr bytes.Buffer
+					logger := ilogging.NewLogWithWriters(&stdout, &stderr)
+					command := commands.Rebase(logger, cfg, mockClient)
+
+					mockClient.EXPECT().
+						RebaseWithReport(gomock.Any(), opts).
+						DoAndReturn(func(context.Context, pack.RebaseOptions) (*pack.RebaseReport, error) {
+							logger.Info("Pulling image 'test/image'")
+							logger.Warn("some-warning")
+							return &pack.RebaseReport{Image: repoName, ImageDigest: "sha256:rebased"}, nil
+						})
+
+					command.SetArgs([]string{repoName, "--output", "json"})
+					h.AssertNil(t, command.Execute())
+
+					var report pack.RebaseReport
+					h.AssertNil(t, json.Unmarshal(stdout.Bytes(), &report))
+					h.AssertEq(t, report.ImageDigest, "sha256:rebased")
+					h.AssertContains(t, stderr.String(), "Pulling image 'test/image'")
+					h.AssertContains(t, stderr.String(), "some-warning")
+				})
+			})
+
+			when("--output is unknown", func() {
+				it("errors", func() {
+					command.SetArgs([]string{repoName, "--output", "yaml"})
+					h.AssertError(t, command.Execute(), "invalid output format 'yaml'")
+				})
+			})
+
+			when("--force", func() {
+				it("forces the rebase", func() {
+					opts.Force = true
+					mockClient.EXPECT().
+						Rebase(gomock.Any(), opts).
+						Return(nil)
+
+					command.SetArgs([]string{repoName, "--force"})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+
+			when("--platform", func() {
+				it("passes the platform through", func() {
+					opts.Platform = "linux/arm64"
+					mockClient.EXPECT().
+						Rebase(gomock.Any(), opts).
+						Return(nil)
+
+					command.SetArgs([]string{repoName, "--platform", "linux/arm64"})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+
 			when("--pull-policy unknown-policy", func() {
 				it("fails to run", func() {
 					command.SetArgs([]string{repoName, "--pull-policy", "unknown-policy"})
@@ -112,5 +237,114 @@ func testRebaseCommand(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 		})
+
+		when("several images are provided", func() {
+			var (
+				tmpDir string
+				opts   pack.RebaseOptions
+			)
+
+			it.Before(func() {
+				var err error
+				tmpDir, err = ioutil.TempDir("", "rebase-command")
+				h.AssertNil(t, err)
+
+				opts = pack.RebaseOptions{PullPolicy: pubcfg.PullAlways, AdditionalMirrors: map[string][]string{}}
+			})
+
+			it.After(func() {
+				os.RemoveAll(tmpDir)
+			})
+
+			it("rebases the images named and listed in --from-file", func() {
+				imageList := filepath.Join(tmpDir, "images.txt")
+				h.AssertNil(t, ioutil.WriteFile(imageList, []byte("# some comment\nsome/listed-image\n\n  other/listed-image  \n"), 0600))
+
+				mockClient.EXPECT().
+					RebaseAll(gomock.Any(), pack.RebaseAllOptions{
+						Images:   []string{"some/image", "some/listed-image", "other/listed-image"},
+						Options:  opts,
+						Parallel: 3,
+					}).
+					Return([]pack.ImageRebaseResult{{Image: "some/image"}, {Image: "some/listed-image"}, {Image: "other/listed-image"}})
+
+				command.SetArgs([]string{"some/image", "--from-file", imageList, "--parallel", "3"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Successfully rebased image 'some/listed-image'")
+				h.AssertContains(t, outBuf.String(), "Successfully rebased 3 images")
+			})
+
+			it("reports the failed rebases", func() {
+				mockClient.EXPECT().
+					RebaseAll(gomock.Any(), gomock.Any()).
+					Return([]pack.ImageRebaseResult{{Image: "some/image"}, {Image: "other/image", Err: errors.New("some-rebase-error")}})
+
+				command.SetArgs([]string{"some/image", "other/image"})
+				h.AssertError(t, command.Execute(), "1 of 2 rebases failed")
+				h.AssertContains(t, outBuf.String(), "ERROR: failed to rebase image 'other/image': some-rebase-error")
+			})
+
+			when("--output json", func() {
+				it("prints the result of every rebase", func() {
+					mockClient.EXPECT().
+						RebaseAll(gomock.Any(), pack.RebaseAllOptions{
+							Images:   []string{"some/image", "other/image"},
+							Options:  opts,
+							Parallel: 1,
+							Report:   true,
+						}).
+						Return([]pack.ImageRebaseResult{
+							{Image: "some/image", Report: &pack.RebaseReport{Image: "some/image", LayersSwapped: 2}},
+							{Image: "other/image", Err: errors.New("some-rebase-error")},
+						})
+
+					command.SetArgs([]string{"some/image", "other/image", "--output", "json"})
+					h.AssertError(t, command.Execute(), "1 of 2 rebases failed")
+					h.AssertContains(t, outBuf.String(), `"layersSwapped": 2`)
+					h.AssertContains(t, outBuf.String(), `"error": "some-rebase-error"`)
+				})
+
+				it("prints nothing but the results on stdout, logging the prefixed output of each rebase to stderr", func() {
+					var stdout, stderr bytes.Buffer
+					logger := ilogging.NewLogWithWriters(&stdout, &stderr)
+					command := commands.Rebase(logger, cfg, mockClient)
+
+					mockClient.EXPECT().
+						RebaseAll(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(context.Context, pack.RebaseAllOptions) []pack.ImageRebaseResult {
+							prefixLogger := logging.NewPrefixLogger(logger, "some/image")
+							prefixLogger.Info("Rebasing 'some/image'")
+							fmt.Fprintln(logging.GetWriterForLevel(prefixLogger, logging.InfoLevel), "some-pull-progress")
+							return []pack.ImageRebaseResult{
+								{Image: "some/image", Report: &pack.RebaseReport{Image: "some/image", LayersSwapped: 2}},
+								{Image: "other/image", Report: &pack.RebaseReport{Image: "other/image", LayersSwapped: 1}},
+							}
+						})
+
+					command.SetArgs([]string{"some/image", "other/image", "--output", "json"})
+					h.AssertNil(t, command.Execute())
+
+					var results []map[string]interface{}
+					h.AssertNil(t, json.Unmarshal(stdout.Bytes(), &results))
+					h.AssertEq(t, len(results), 2)
+					h.AssertContains(t, stderr.String(), "[some/image] Rebasing 'some/image'")
+					h.AssertContains(t, stderr.String(), "[some/image] some-pull-progress")
+				})
+			})
+
+			when("--from-file does not exist", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"--from-file", filepath.Join(tmpDir, "missing.txt")})
+					h.AssertError(t, command.Execute(), "reading images from")
+				})
+			})
+
+			when("--parallel is less than 1", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"some/image", "other/image", "--parallel", "0"})
+					h.AssertError(t, command.Execute(), "parallel flag must be at least 1")
+				})
+			})
+		})
 	})
 }
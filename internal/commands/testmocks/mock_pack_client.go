@@ -50,6 +50,20 @@ func (mr *MockPackClientMockRecorder) Build(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Build", reflect.TypeOf((*MockPackClient)(nil).Build), arg0, arg1)
 }
 
+// BuildAll mocks base method
+func (m *MockPackClient) BuildAll(arg0 context.Context, arg1 pack.BuildAllOptions) []pack.AppBuildResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildAll", arg0, arg1)
+	ret0, _ := ret[0].([]pack.AppBuildResult)
+	return ret0
+}
+
+// BuildAll indicates an expected call of BuildAll
+func (mr *MockPackClientMockRecorder) BuildAll(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildAll", reflect.TypeOf((*MockPackClient)(nil).BuildAll), arg0, arg1)
+}
+
 // CreateBuilder mocks base method
 func (m *MockPackClient) CreateBuilder(arg0 context.Context, arg1 pack.CreateBuilderOptions) error {
 	m.ctrl.T.Helper()
@@ -64,6 +78,36 @@ func (mr *MockPackClientMockRecorder) CreateBuilder(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBuilder", reflect.TypeOf((*MockPackClient)(nil).CreateBuilder), arg0, arg1)
 }
 
+// Detect mocks base method
+func (m *MockPackClient) Detect(arg0 context.Context, arg1 pack.DetectOptions) (*pack.DetectResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Detect", arg0, arg1)
+	ret0, _ := ret[0].(*pack.DetectResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Detect indicates an expected call of Detect
+func (mr *MockPackClientMockRecorder) Detect(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Detect", reflect.TypeOf((*MockPackClient)(nil).Detect), arg0, arg1)
+}
+
+// ImageOutdated mocks base method
+func (m *MockPackClient) ImageOutdated(arg0 context.Context, arg1 pack.ImageOutdatedOptions) (*pack.ImageOutdatedReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImageOutdated", arg0, arg1)
+	ret0, _ := ret[0].(*pack.ImageOutdatedReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImageOutdated indicates an expected call of ImageOutdated
+func (mr *MockPackClientMockRecorder) ImageOutdated(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImageOutdated", reflect.TypeOf((*MockPackClient)(nil).ImageOutdated), arg0, arg1)
+}
+
 // InspectBuilder mocks base method
 func (m *MockPackClient) InspectBuilder(arg0 string, arg1 bool, arg2 ...pack.BuilderInspectionModifier) (*pack.BuilderInfo, error) {
 	m.ctrl.T.Helper()
@@ -99,19 +143,54 @@ func (mr *MockPackClientMockRecorder) InspectBuildpack(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectBuildpack", reflect.TypeOf((*MockPackClient)(nil).InspectBuildpack), arg0)
 }
 
+// InspectCache mocks base method
+func (m *MockPackClient) InspectCache(arg0 context.Context, arg1 string) ([]pack.CacheInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InspectCache", arg0, arg1)
+	ret0, _ := ret[0].([]pack.CacheInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InspectCache indicates an expected call of InspectCache
+func (mr *MockPackClientMockRecorder) InspectCache(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectCache", reflect.TypeOf((*MockPackClient)(nil).InspectCache), arg0, arg1)
+}
+
 // InspectImage mocks base method
-func (m *MockPackClient) InspectImage(arg0 string, arg1 bool) (*pack.ImageInfo, error) {
+func (m *MockPackClient) InspectImage(arg0 string, arg1 bool, arg2 ...pack.ImageInspectionModifier) (*pack.ImageInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InspectImage", arg0, arg1)
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "InspectImage", varargs...)
 	ret0, _ := ret[0].(*pack.ImageInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // InspectImage indicates an expected call of InspectImage
-func (mr *MockPackClientMockRecorder) InspectImage(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockPackClientMockRecorder) InspectImage(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectImage", reflect.TypeOf((*MockPackClient)(nil).InspectImage), arg0, arg1)
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectImage", reflect.TypeOf((*MockPackClient)(nil).InspectImage), varargs...)
+}
+
+// ListCaches mocks base method
+func (m *MockPackClient) ListCaches(arg0 context.Context) ([]pack.CacheInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCaches", arg0)
+	ret0, _ := ret[0].([]pack.CacheInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCaches indicates an expected call of ListCaches
+func (mr *MockPackClientMockRecorder) ListCaches(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCaches", reflect.TypeOf((*MockPackClient)(nil).ListCaches), arg0)
 }
 
 // PackageBuildpack mocks base method
@@ -128,6 +207,21 @@ func (mr *MockPackClientMockRecorder) PackageBuildpack(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PackageBuildpack", reflect.TypeOf((*MockPackClient)(nil).PackageBuildpack), arg0, arg1)
 }
 
+// Prune mocks base method
+func (m *MockPackClient) Prune(arg0 context.Context, arg1 pack.PruneOptions) (*pack.PruneReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prune", arg0, arg1)
+	ret0, _ := ret[0].(*pack.PruneReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prune indicates an expected call of Prune
+func (mr *MockPackClientMockRecorder) Prune(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prune", reflect.TypeOf((*MockPackClient)(nil).Prune), arg0, arg1)
+}
+
 // PullBuildpack mocks base method
 func (m *MockPackClient) PullBuildpack(arg0 context.Context, arg1 pack.PullBuildpackOptions) error {
 	m.ctrl.T.Helper()
@@ -156,6 +250,35 @@ func (mr *MockPackClientMockRecorder) Rebase(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rebase", reflect.TypeOf((*MockPackClient)(nil).Rebase), arg0, arg1)
 }
 
+// RebaseAll mocks base method
+func (m *MockPackClient) RebaseAll(arg0 context.Context, arg1 pack.RebaseAllOptions) []pack.ImageRebaseResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebaseAll", arg0, arg1)
+	ret0, _ := ret[0].([]pack.ImageRebaseResult)
+	return ret0
+}
+
+// RebaseAll indicates an expected call of RebaseAll
+func (mr *MockPackClientMockRecorder) RebaseAll(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebaseAll", reflect.TypeOf((*MockPackClient)(nil).RebaseAll), arg0, arg1)
+}
+
+// RebaseWithReport mocks base method
+func (m *MockPackClient) RebaseWithReport(arg0 context.Context, arg1 pack.RebaseOptions) (*pack.RebaseReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebaseWithReport", arg0, arg1)
+	ret0, _ := ret[0].(*pack.RebaseReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RebaseWithReport indicates an expected call of RebaseWithReport
+func (mr *MockPackClientMockRecorder) RebaseWithReport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebaseWithReport", reflect.TypeOf((*MockPackClient)(nil).RebaseWithReport), arg0, arg1)
+}
+
 // RegisterBuildpack mocks base method
 func (m *MockPackClient) RegisterBuildpack(arg0 context.Context, arg1 pack.RegisterBuildpackOptions) error {
 	m.ctrl.T.Helper()
@@ -170,6 +293,21 @@ func (mr *MockPackClientMockRecorder) RegisterBuildpack(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterBuildpack", reflect.TypeOf((*MockPackClient)(nil).RegisterBuildpack), arg0, arg1)
 }
 
+// RemoveCache mocks base method
+func (m *MockPackClient) RemoveCache(arg0 context.Context, arg1 pack.RemoveCacheOptions) ([]pack.CacheInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCache", arg0, arg1)
+	ret0, _ := ret[0].([]pack.CacheInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveCache indicates an expected call of RemoveCache
+func (mr *MockPackClientMockRecorder) RemoveCache(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCache", reflect.TypeOf((*MockPackClient)(nil).RemoveCache), arg0, arg1)
+}
+
 // YankBuildpack mocks base method
 func (m *MockPackClient) YankBuildpack(arg0 pack.YankBuildpackOptions) error {
 	m.ctrl.T.Helper()
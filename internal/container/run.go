@@ -12,6 +12,15 @@ import (
 	"github.com/pkg/errors"
 )
 
+// ExitError is returned by Run when the container exits with a non-zero status code.
+type ExitError struct {
+	StatusCode int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("failed with status code: %d", e.StatusCode)
+}
+
 func Run(ctx context.Context, docker client.CommonAPIClient, ctrID string, out, errOut io.Writer) error {
 	bodyChan, errChan := docker.ContainerWait(ctx, ctrID, dcontainer.WaitConditionNextExit)
 
@@ -29,11 +38,11 @@ func Run(ctx context.Context, docker client.CommonAPIClient, ctrID string, out,
 		return errors.Wrap(err, "container start")
 	}
 
-	copyErr := make(chan error)
+	copyErr := make(chan error, 1)
 	go func() {
 		_, err := stdcopy.StdCopy(out, errOut, resp.Reader)
-		defer optionallyCloseWriter(out)
-		defer optionallyCloseWriter(errOut)
+		optionallyCloseWriter(out)
+		optionallyCloseWriter(errOut)
 
 		copyErr <- err
 	}()
@@ -41,7 +50,9 @@ func Run(ctx context.Context, docker client.CommonAPIClient, ctrID string, out,
 	select {
 	case body := <-bodyChan:
 		if body.StatusCode != 0 {
-			return fmt.Errorf("failed with status code: %d", body.StatusCode)
+			// the output of a failed container often explains why, so wait for all of it
+			<-copyErr
+			return &ExitError{StatusCode: int(body.StatusCode)}
 		}
 	case err := <-errChan:
 		return err
@@ -0,0 +1,310 @@
+package dotenv
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// LookupFunc returns the value of a variable, and whether it is set.
+type LookupFunc func(name string) (string, bool)
+
+// Parse parses the contents of a .env file, as docker-compose reads them:
+//
+//	# comments and blank lines are ignored
+//	export KEY=value      # an optional 'export' prefix, and an inline comment
+//	KEY='literal $value'  # single quotes keep their contents as they are, across lines
+//	KEY="a\nb ${OTHER}"   # double quotes support escapes and interpolation, across lines
+//	KEY=${OTHER:-default} # unquoted values are interpolated
+//	KEY                   # the value of KEY from lookup
+//
+// Variables are interpolated from the entries before them, or else from lookup. Errors report the line they occur on.
+func Parse(contents string, lookup LookupFunc) (map[string]string, error) {
+	p := &parser{
+		src:  strings.ReplaceAll(contents, "\r\n", "\n"),
+		line: 1,
+		vars: map[string]string{},
+	}
+	p.lookup = func(name string) (string, bool) {
+		if value, ok := p.vars[name]; ok {
+			return value, true
+		}
+		return lookup(name)
+	}
+
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return p.vars, nil
+		}
+		if err := p.parseEntry(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Expand interpolates the variables a value references as '$VAR', '${VAR}', '${VAR:-default}' or '${VAR-default}'.
+// Unset variables expand to an empty string, and '$$' to '$'.
+func Expand(value string, lookup LookupFunc) (string, error) {
+	return expand(value, false, lookup)
+}
+
+type parser struct {
+	src    string
+	pos    int
+	line   int
+	vars   map[string]string
+	lookup LookupFunc
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) next() byte {
+	c := p.src[p.pos]
+	p.pos++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+
+// skipBlank skips whitespace, blank lines and comments.
+func (p *parser) skipBlank() {
+	for !p.eof() {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\n':
+			p.next()
+		case '#':
+			p.skipLine()
+		default:
+			return
+		}
+	}
+}
+
+// skipLine skips the rest of the current line, including its end.
+func (p *parser) skipLine() {
+	for !p.eof() {
+		if p.next() == '\n' {
+			return
+		}
+	}
+}
+
+// restOfLine returns the rest of the current line, consuming its end.
+func (p *parser) restOfLine() string {
+	end := strings.IndexByte(p.src[p.pos:], '\n')
+	if end < 0 {
+		end = len(p.src) - p.pos
+	}
+	rest := p.src[p.pos : p.pos+end]
+	p.pos += end
+	if !p.eof() {
+		p.next()
+	}
+	return rest
+}
+
+func (p *parser) errorf(line int, format string, args ...interface{}) error {
+	return errors.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) parseEntry() error {
+	line := p.line
+	rest := p.src[p.pos:]
+
+	eq := strings.IndexAny(rest, "=\n")
+	if eq < 0 || rest[eq] == '\n' {
+		name := trimExport(strings.TrimSpace(stripComment(p.restOfLine())))
+		if err := validateName(name); err != nil {
+			return p.errorf(line, "%s", err)
+		}
+		value, _ := p.lookup(name)
+		p.vars[name] = value
+		return nil
+	}
+
+	name := trimExport(strings.TrimSpace(rest[:eq]))
+	if err := validateName(name); err != nil {
+		return p.errorf(line, "%s", err)
+	}
+	p.pos += eq + 1
+
+	for !p.eof() && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.next()
+	}
+
+	if p.eof() || (p.src[p.pos] != '\'' && p.src[p.pos] != '"') {
+		value, err := expand(strings.TrimSpace(stripComment(p.restOfLine())), false, p.lookup)
+		if err != nil {
+			return p.errorf(line, "invalid value of %s: %s", style.Symbol(name), err)
+		}
+		p.vars[name] = value
+		return nil
+	}
+
+	quote := p.next()
+	var raw strings.Builder
+	for {
+		if p.eof() {
+			return p.errorf(line, "unterminated quoted value of %s", style.Symbol(name))
+		}
+		c := p.next()
+		if c == quote {
+			break
+		}
+		if c == '\\' && quote == '"' && !p.eof() {
+			raw.WriteByte(c)
+			c = p.next()
+		}
+		raw.WriteByte(c)
+	}
+
+	closingLine := p.line
+	if trailing := strings.TrimSpace(stripComment(" " + p.restOfLine())); trailing != "" {
+		return p.errorf(closingLine, "unexpected %s after the quoted value of %s", style.Symbol(trailing), style.Symbol(name))
+	}
+
+	if quote == '\'' {
+		p.vars[name] = raw.String()
+		return nil
+	}
+
+	value, err := expand(raw.String(), true, p.lookup)
+	if err != nil {
+		return p.errorf(line, "invalid value of %s: %s", style.Symbol(name), err)
+	}
+	p.vars[name] = value
+	return nil
+}
+
+// trimExport removes an 'export' prefix from a variable name.
+func trimExport(name string) string {
+	for _, prefix := range []string{"export ", "export\t"} {
+		if strings.HasPrefix(name, prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(name, prefix))
+		}
+	}
+	return name
+}
+
+// stripComment removes a comment, which starts with a '#' at the start of s or after whitespace.
+func stripComment(s string) string {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t') {
+			return s[:i]
+		}
+	}
+	return s
+}
+
+func validateName(name string) error {
+	if name == "" {
+		return errors.New("missing variable name")
+	}
+	if strings.ContainsAny(name, " \t'\"$") {
+		return errors.Errorf("invalid variable name %s", style.Symbol(name))
+	}
+	return nil
+}
+
+// expand interpolates the variables s references, also unescaping it when escapes is true.
+func expand(s string, escapes bool, lookup LookupFunc) (string, error) {
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && escapes && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				out.WriteByte('\n')
+			case 't':
+				out.WriteByte('\t')
+			case 'r':
+				out.WriteByte('\r')
+			case '\\', '"', '$':
+				out.WriteByte(s[i])
+			default:
+				out.WriteByte('\\')
+				out.WriteByte(s[i])
+			}
+		case c == '$' && i+1 < len(s) && s[i+1] == '$':
+			i++
+			out.WriteByte('$')
+		case c == '$' && i+1 < len(s) && s[i+1] == '{':
+			end := closingBrace(s[i:])
+			if end < 0 {
+				return "", errors.Errorf("unterminated variable reference %s", style.Symbol(s[i:]))
+			}
+			value, err := expandReference(s[i+2:i+end], lookup)
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(value)
+			i += end
+		case c == '$' && i+1 < len(s) && isNameStart(s[i+1]):
+			end := i + 2
+			for end < len(s) && isNameChar(s[end]) {
+				end++
+			}
+			value, _ := lookup(s[i+1 : end])
+			out.WriteString(value)
+			i = end - 1
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.String(), nil
+}
+
+// expandReference expands the contents of a '${...}' reference.
+func expandReference(ref string, lookup LookupFunc) (string, error) {
+	name, def, hasDefault, emptyIsUnset := ref, "", false, false
+	if i := strings.IndexByte(ref, '-'); i >= 0 {
+		name, def, hasDefault = ref[:i], ref[i+1:], true
+		if strings.HasSuffix(name, ":") {
+			name, emptyIsUnset = strings.TrimSuffix(name, ":"), true
+		}
+	}
+
+	if name == "" || !isNameStart(name[0]) || strings.IndexFunc(name, func(r rune) bool { return r > 127 || !isNameChar(byte(r)) }) >= 0 {
+		return "", errors.Errorf("invalid variable reference %s", style.Symbol("${"+ref+"}"))
+	}
+
+	value, ok := lookup(name)
+	if hasDefault && (!ok || (emptyIsUnset && value == "")) {
+		return expand(def, false, lookup)
+	}
+	return value, nil
+}
+
+// closingBrace returns the index of the '}' closing the reference s starts with, allowing for references nested in its default.
+func closingBrace(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
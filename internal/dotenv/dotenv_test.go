@@ -0,0 +1,132 @@
+package dotenv_test
+
+import (
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/dotenv"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestDotenv(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Dotenv", testDotenv, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testDotenv(t *testing.T, when spec.G, it spec.S) {
+	var host dotenv.LookupFunc
+
+	it.Before(func() {
+		host = func(name string) (string, bool) {
+			value, ok := map[string]string{"HOST_VAR": "host-value", "EMPTY": ""}[name]
+			return value, ok
+		}
+	})
+
+	when("#Parse", func() {
+		it("parses plain entries, ignoring comments and blank lines", func() {
+			vars, err := dotenv.Parse(`
+# a comment
+KEY=VALUE
+  SPACED  =  some value
+WITH_HASH=a#b
+INLINE=value # a comment
+EMPTY_VALUE=
+`, host)
+			h.AssertNil(t, err)
+			h.AssertEq(t, vars, map[string]string{
+				"KEY":         "VALUE",
+				"SPACED":      "some value",
+				"WITH_HASH":   "a#b",
+				"INLINE":      "value",
+				"EMPTY_VALUE": "",
+			})
+		})
+
+		it("supports an export prefix", func() {
+			vars, err := dotenv.Parse("export FOO=bar\nexport\tBAZ='qux'\n", host)
+			h.AssertNil(t, err)
+			h.AssertEq(t, vars, map[string]string{"FOO": "bar", "BAZ": "qux"})
+		})
+
+		it("takes the value of entries without one from lookup", func() {
+			vars, err := dotenv.Parse("HOST_VAR\nUNSET_VAR # a comment\n", host)
+			h.AssertNil(t, err)
+			h.AssertEq(t, vars, map[string]string{"HOST_VAR": "host-value", "UNSET_VAR": ""})
+		})
+
+		it("keeps single quoted values as they are", func() {
+			vars, err := dotenv.Parse(`SINGLE='a $HOST_VAR \n # b'`+"\nMULTI='line 1\nline 2' # a comment\r\n", host)
+			h.AssertNil(t, err)
+			h.AssertEq(t, vars, map[string]string{
+				"SINGLE": `a $HOST_VAR \n # b`,
+				"MULTI":  "line 1\nline 2",
+			})
+		})
+
+		it("unescapes and interpolates double quoted values", func() {
+			vars, err := dotenv.Parse(`DOUBLE="a\tb\n\"c\" \$HOST_VAR ${HOST_VAR} \\ # d"
+MULTI="line 1
+line 2"
+`, host)
+			h.AssertNil(t, err)
+			h.AssertEq(t, vars, map[string]string{
+				"DOUBLE": "a\tb\n\"c\" $HOST_VAR host-value \\ # d",
+				"MULTI":  "line 1\nline 2",
+			})
+		})
+
+		it("interpolates from earlier entries, then from lookup", func() {
+			vars, err := dotenv.Parse(`HOST_VAR=overridden
+FIRST=one
+SECOND=$FIRST-${HOST_VAR}
+THIRD=${UNSET:-${FIRST}} ${EMPTY-default} ${EMPTY:-default} $$FIRST
+`, host)
+			h.AssertNil(t, err)
+			h.AssertEq(t, vars["SECOND"], "one-overridden")
+			h.AssertEq(t, vars["THIRD"], "one  default $FIRST")
+		})
+
+		it("errors with the line of an unterminated quoted value", func() {
+			_, err := dotenv.Parse("KEY=VALUE\n\nQUOTED=\"unterminated\nOTHER=VALUE\n", host)
+			h.AssertError(t, err, "line 3: unterminated quoted value of 'QUOTED'")
+		})
+
+		it("errors with the line of characters after a quoted value", func() {
+			_, err := dotenv.Parse("KEY='multi\nline' trailing\n", host)
+			h.AssertError(t, err, "line 2: unexpected 'trailing' after the quoted value of 'KEY'")
+		})
+
+		it("errors with the line of an invalid variable name", func() {
+			_, err := dotenv.Parse("KEY=VALUE\nNOT VALID=VALUE\n", host)
+			h.AssertError(t, err, "line 2: invalid variable name 'NOT VALID'")
+		})
+
+		it("errors with the line of a missing variable name", func() {
+			_, err := dotenv.Parse("=VALUE\n", host)
+			h.AssertError(t, err, "line 1: missing variable name")
+		})
+
+		it("errors with the line of an unterminated variable reference", func() {
+			_, err := dotenv.Parse("KEY=VALUE\nOTHER=${KEY\n", host)
+			h.AssertError(t, err, "line 2: invalid value of 'OTHER': unterminated variable reference '${KEY'")
+		})
+	})
+
+	when("#Expand", func() {
+		it("interpolates variables from lookup", func() {
+			value, err := dotenv.Expand(`$HOST_VAR/${HOST_VAR}/${UNSET:-default}/$UNSET/\n`, host)
+			h.AssertNil(t, err)
+			h.AssertEq(t, value, `host-value/host-value/default//\n`)
+		})
+
+		it("errors on an invalid reference", func() {
+			_, err := dotenv.Expand(`${NOT VALID}`, host)
+			h.AssertError(t, err, "invalid variable reference '${NOT VALID}'")
+		})
+	})
+}
@@ -0,0 +1,32 @@
+package fakes
+
+import (
+	"context"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/image"
+)
+
+type FakeLayerReader struct {
+	ImageLayers map[string][]image.Layer
+}
+
+func NewFakeLayerReader() *FakeLayerReader {
+	return &FakeLayerReader{
+		ImageLayers: map[string][]image.Layer{},
+	}
+}
+
+func (f *FakeLayerReader) Layers(ctx context.Context, img imgutil.Image) ([]image.Layer, error) {
+	layers, ok := f.ImageLayers[img.Name()]
+	if !ok {
+		return nil, errors.Errorf("no layers for image %s", img.Name())
+	}
+	return layers, nil
+}
+
+func (f *FakeLayerReader) LayersWithSizes(ctx context.Context, img imgutil.Image) ([]image.Layer, error) {
+	return f.Layers(ctx, img)
+}
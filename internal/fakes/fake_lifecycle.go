@@ -7,10 +7,17 @@ import (
 )
 
 type FakeLifecycle struct {
-	Opts build.LifecycleOptions
+	Opts         build.LifecycleOptions
+	DetectResult build.DetectResult
+	DetectError  error
 }
 
 func (f *FakeLifecycle) Execute(ctx context.Context, opts build.LifecycleOptions) error {
 	f.Opts = opts
 	return nil
 }
+
+func (f *FakeLifecycle) Detect(ctx context.Context, opts build.LifecycleOptions) (build.DetectResult, error) {
+	f.Opts = opts
+	return f.DetectResult, f.DetectError
+}
@@ -0,0 +1,173 @@
+package gitsource
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/src-d/go-billy.v4/osfs"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/cache"
+	"gopkg.in/src-d/go-git.v4/storage/filesystem"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// Prefix marks an app path as a git repository.
+const Prefix = "git+"
+
+var supportedSchemes = []string{"https", "http", "file"}
+
+// Source is an app source in a git repository, referenced as 'git+<repository>#<ref>[:<subdir>]'.
+type Source struct {
+	// URL of the repository.
+	Repository string
+
+	// Commit, tag or branch to check out.
+	// The default branch of the repository when empty.
+	Ref string
+
+	// Directory within the repository holding the app.
+	// The root of the repository when empty.
+	Subdir string
+}
+
+// Checkout is a Source cloned to a local directory.
+type Checkout struct {
+	Source
+
+	// Directory the repository was cloned to, holding the git directory apart from the worktree,
+	// so that the history and config of the repository are not part of the app.
+	Dir string
+
+	// Directory holding the app, within Dir.
+	AppPath string
+
+	// SHA of the commit that was checked out.
+	Commit string
+}
+
+// IsSource returns whether an app path references a git repository.
+func IsSource(path string) bool {
+	return strings.HasPrefix(path, Prefix)
+}
+
+// Parse parses an app path referencing a git repository.
+func Parse(path string) (Source, error) {
+	if !IsSource(path) {
+		return Source{}, errors.Errorf("git source must start with %s", style.Symbol(Prefix))
+	}
+
+	repository, fragment := strings.TrimPrefix(path, Prefix), ""
+	if i := strings.Index(repository, "#"); i >= 0 {
+		repository, fragment = repository[:i], repository[i+1:]
+	}
+
+	repoURL, err := url.Parse(repository)
+	if err != nil {
+		return Source{}, errors.Wrapf(err, "parsing repository url %s", style.Symbol(repository))
+	}
+	if !isSupportedScheme(repoURL.Scheme) {
+		return Source{}, errors.Errorf("unsupported repository url %s, must use one of the schemes %s", style.Symbol(repository), strings.Join(supportedSchemes, ", "))
+	}
+
+	source := Source{Repository: repository, Ref: fragment}
+	// git does not allow colons in ref names
+	if i := strings.Index(fragment, ":"); i >= 0 {
+		source.Ref, source.Subdir = fragment[:i], fragment[i+1:]
+	}
+
+	subdir := filepath.Clean(filepath.FromSlash(source.Subdir))
+	if filepath.IsAbs(subdir) || subdir == ".." || strings.HasPrefix(subdir, ".."+string(filepath.Separator)) {
+		return Source{}, errors.Errorf("subdirectory %s must be relative to the repository root", style.Symbol(source.Subdir))
+	}
+	return source, nil
+}
+
+// PublicRepository returns the URL of the repository without any credentials it contains.
+func (s Source) PublicRepository() string {
+	repoURL, err := url.Parse(s.Repository)
+	if err != nil {
+		return s.Repository
+	}
+	repoURL.User = nil
+	return repoURL.String()
+}
+
+// Clone clones the repository to dir and checks out the ref.
+// The worktree is checked out to a directory of its own within dir, without the git directory.
+func (s Source) Clone(ctx context.Context, dir string) (Checkout, error) {
+	worktreeDir := filepath.Join(dir, "worktree")
+	storage := filesystem.NewStorage(osfs.New(filepath.Join(dir, "git")), cache.NewObjectLRUDefault())
+	repository, err := git.CloneContext(ctx, storage, osfs.New(worktreeDir), &git.CloneOptions{
+		URL:  s.Repository,
+		Tags: git.AllTags,
+	})
+	if err != nil {
+		return Checkout{}, errors.Wrapf(err, "cloning repository %s", style.Symbol(s.PublicRepository()))
+	}
+
+	hash, err := resolveRef(repository, s.Ref)
+	if err != nil {
+		return Checkout{}, err
+	}
+
+	worktree, err := repository.Worktree()
+	if err != nil {
+		return Checkout{}, err
+	}
+
+	if err := worktree.Checkout(&git.CheckoutOptions{Hash: hash, Force: true}); err != nil {
+		return Checkout{}, errors.Wrapf(err, "checking out %s", style.Symbol(s.Ref))
+	}
+
+	// the worktree is left with a file pointing to the git directory
+	if err := os.Remove(filepath.Join(worktreeDir, git.GitDirName)); err != nil && !os.IsNotExist(err) {
+		return Checkout{}, errors.Wrap(err, "removing git directory link")
+	}
+
+	appPath := filepath.Join(worktreeDir, filepath.FromSlash(s.Subdir))
+	fi, err := os.Stat(appPath)
+	if err != nil || !fi.IsDir() {
+		return Checkout{}, errors.Errorf("subdirectory %s does not exist in repository", style.Symbol(s.Subdir))
+	}
+
+	return Checkout{
+		Source:  s,
+		Dir:     dir,
+		AppPath: appPath,
+		Commit:  hash.String(),
+	}, nil
+}
+
+// resolveRef returns the commit a commit SHA, tag or branch points to.
+// Branches other than the default branch are only known as remote branches after a clone.
+func resolveRef(repository *git.Repository, ref string) (plumbing.Hash, error) {
+	if ref == "" {
+		head, err := repository.Head()
+		if err != nil {
+			return plumbing.ZeroHash, errors.Wrap(err, "resolving HEAD")
+		}
+		return head.Hash(), nil
+	}
+
+	for _, revision := range []string{ref, "origin/" + ref} {
+		if hash, err := repository.ResolveRevision(plumbing.Revision(revision)); err == nil {
+			return *hash, nil
+		}
+	}
+	return plumbing.ZeroHash, errors.Errorf("ref %s not found in repository", style.Symbol(ref))
+}
+
+func isSupportedScheme(scheme string) bool {
+	for _, supported := range supportedSchemes {
+		if scheme == supported {
+			return true
+		}
+	}
+	return false
+}
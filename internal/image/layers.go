@@ -0,0 +1,217 @@
+package image
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/buildpacks/imgutil/local"
+	"github.com/buildpacks/imgutil/remote"
+	"github.com/docker/docker/client"
+	"github.com/google/go-containerregistry/pkg/authn"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	ggcrremote "github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// Layer is a layer of an image.
+type Layer struct {
+	// Diff ID of the layer, the digest of its uncompressed contents.
+	DiffID string
+
+	// Size of the uncompressed contents of the layer in bytes, or -1 if unknown.
+	// Only set by LayersWithSizes.
+	Size int64
+
+	// Size of the compressed layer in bytes, or -1 if unknown.
+	// Only set by LayersWithSizes.
+	CompressedSize int64
+}
+
+// LayerReader lists the layers of images, whether they are in the docker daemon or a registry.
+type LayerReader struct {
+	docker   client.CommonAPIClient
+	keychain authn.Keychain
+}
+
+func NewLayerReader(docker client.CommonAPIClient, keychain authn.Keychain) *LayerReader {
+	return &LayerReader{
+		docker:   docker,
+		keychain: keychain,
+	}
+}
+
+// Layers returns the layers of img, bottom-most first. img is identified by its ID in the daemon
+// or its digest in a registry, so that they are the layers of the image as fetched.
+func (r *LayerReader) Layers(ctx context.Context, img imgutil.Image) ([]Layer, error) {
+	identifier, err := img.Identifier()
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading identifier of %s", style.Symbol(img.Name()))
+	}
+
+	var diffIDs []string
+	switch id := identifier.(type) {
+	case local.IDIdentifier:
+		inspect, _, err := r.docker.ImageInspectWithRaw(ctx, id.ImageID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "inspecting %s", style.Symbol(img.Name()))
+		}
+		diffIDs = inspect.RootFS.Layers
+	case remote.DigestIdentifier:
+		remoteImage, err := ggcrremote.Image(id.Digest, ggcrremote.WithAuthFromKeychain(r.keychain), ggcrremote.WithContext(ctx))
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s from registry", style.Symbol(img.Name()))
+		}
+		configFile, err := remoteImage.ConfigFile()
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading config of %s", style.Symbol(img.Name()))
+		}
+		for _, diffID := range configFile.RootFS.DiffIDs {
+			diffIDs = append(diffIDs, diffID.String())
+		}
+	default:
+		return nil, errors.Errorf("cannot read the layers of %s", style.Symbol(img.Name()))
+	}
+
+	var layers []Layer
+	for _, diffID := range diffIDs {
+		layers = append(layers, Layer{DiffID: diffID})
+	}
+	return layers, nil
+}
+
+// LayersWithSizes returns the layers of img as Layers does, along with their sizes.
+// Images in the daemon are stored uncompressed, so only their uncompressed sizes are known. These are measured
+// by saving the image from the daemon, as its history does not tell which entries created a layer.
+// The compressed sizes of images in a registry are read from their manifest, while their layers are downloaded
+// to measure their uncompressed sizes.
+func (r *LayerReader) LayersWithSizes(ctx context.Context, img imgutil.Image) ([]Layer, error) {
+	identifier, err := img.Identifier()
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading identifier of %s", style.Symbol(img.Name()))
+	}
+
+	switch id := identifier.(type) {
+	case local.IDIdentifier:
+		layers, err := r.Layers(ctx, img)
+		if err != nil {
+			return nil, err
+		}
+
+		sizes, err := r.savedLayerSizes(ctx, id.ImageID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "measuring layers of %s", style.Symbol(img.Name()))
+		}
+
+		for i := range layers {
+			layers[i].Size, layers[i].CompressedSize = -1, -1
+			if len(sizes) == len(layers) {
+				layers[i].Size = sizes[i]
+			}
+		}
+		return layers, nil
+	case remote.DigestIdentifier:
+		remoteImage, err := ggcrremote.Image(id.Digest, ggcrremote.WithAuthFromKeychain(r.keychain), ggcrremote.WithContext(ctx))
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s from registry", style.Symbol(img.Name()))
+		}
+		remoteLayers, err := remoteImage.Layers()
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading layers of %s", style.Symbol(img.Name()))
+		}
+
+		var layers []Layer
+		for _, remoteLayer := range remoteLayers {
+			diffID, err := remoteLayer.DiffID()
+			if err != nil {
+				return nil, errors.Wrapf(err, "reading diff ID of a layer of %s", style.Symbol(img.Name()))
+			}
+			compressedSize, err := remoteLayer.Size()
+			if err != nil {
+				return nil, errors.Wrapf(err, "reading size of layer %s", style.Symbol(diffID.String()))
+			}
+			size, err := uncompressedSize(remoteLayer)
+			if err != nil {
+				return nil, errors.Wrapf(err, "measuring layer %s", style.Symbol(diffID.String()))
+			}
+			layers = append(layers, Layer{DiffID: diffID.String(), Size: size, CompressedSize: compressedSize})
+		}
+		return layers, nil
+	default:
+		return nil, errors.Errorf("cannot read the layers of %s", style.Symbol(img.Name()))
+	}
+}
+
+// savedLayerSizes returns the sizes of the layer tars of an image in the daemon, bottom-most first,
+// as listed by the manifest of the image saved from the daemon. Layers missing from the save have a size of -1.
+func (r *LayerReader) savedLayerSizes(ctx context.Context, imageID string) ([]int64, error) {
+	rc, err := r.docker.ImageSave(ctx, []string{imageID})
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var manifest []struct {
+		Layers []string
+	}
+	sizes := map[string]int64{}
+	// a layer shared by several image layers is saved once, and linked to from the others
+	links := map[string]string{}
+
+	tr := tar.NewReader(rc)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "reading saved image")
+		}
+
+		name := path.Clean(header.Name)
+		switch {
+		case name == "manifest.json":
+			if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+				return nil, errors.Wrap(err, "reading manifest of saved image")
+			}
+		case header.Typeflag == tar.TypeReg:
+			sizes[name] = header.Size
+		case header.Typeflag == tar.TypeSymlink:
+			links[name] = path.Join(path.Dir(name), header.Linkname)
+		}
+	}
+
+	if len(manifest) != 1 {
+		return nil, errors.Errorf("saved image has %d manifests, expected 1", len(manifest))
+	}
+
+	var layerSizes []int64
+	for _, layer := range manifest[0].Layers {
+		name := path.Clean(layer)
+		if target, ok := links[name]; ok {
+			name = target
+		}
+
+		size, ok := sizes[name]
+		if !ok {
+			size = -1
+		}
+		layerSizes = append(layerSizes, size)
+	}
+	return layerSizes, nil
+}
+
+func uncompressedSize(layer v1.Layer) (int64, error) {
+	rc, err := layer.Uncompressed()
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+	return io.Copy(ioutil.Discard, rc)
+}
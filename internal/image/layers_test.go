@@ -0,0 +1,134 @@
+package image_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/buildpacks/imgutil/fakes"
+	"github.com/buildpacks/imgutil/local"
+	"github.com/docker/docker/api/types"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/image"
+	h "github.com/buildpacks/pack/testhelpers"
+	"github.com/buildpacks/pack/testmocks"
+)
+
+func TestLayers(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Layers", testLayers, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testLayers(t *testing.T, when spec.G, it spec.S) {
+	var (
+		mockController *gomock.Controller
+		mockDocker     *testmocks.MockCommonAPIClient
+		subject        *image.LayerReader
+		img            *fakes.Image
+	)
+
+	// savedImage returns a tar of an image as the docker daemon saves it, with the files given and a manifest listing layers
+	savedImage := func(files map[string]int, links map[string]string, layers ...string) []byte {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for name, size := range files {
+			h.AssertNil(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(size)}))
+			_, err := tw.Write(make([]byte, size))
+			h.AssertNil(t, err)
+		}
+		for name, target := range links {
+			h.AssertNil(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeSymlink, Linkname: target}))
+		}
+
+		manifest := `[{"Config":"some-config.json","Layers":["` + layers[0]
+		for _, layer := range layers[1:] {
+			manifest += `","` + layer
+		}
+		manifest += `"]}]`
+		h.AssertNil(t, tw.WriteHeader(&tar.Header{Name: "manifest.json", Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(manifest))}))
+		_, err := tw.Write([]byte(manifest))
+		h.AssertNil(t, err)
+
+		h.AssertNil(t, tw.Close())
+		return buf.Bytes()
+	}
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockDocker = testmocks.NewMockCommonAPIClient(mockController)
+		subject = image.NewLayerReader(mockDocker, authn.DefaultKeychain)
+
+		img = fakes.NewImage("some/image", "", local.IDIdentifier{ImageID: "some-image-id"})
+
+		inspect := types.ImageInspect{}
+		inspect.RootFS.Layers = []string{"sha256:bottom", "sha256:middle", "sha256:top"}
+		mockDocker.EXPECT().ImageInspectWithRaw(gomock.Any(), "some-image-id").Return(inspect, nil, nil)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+		img.Cleanup()
+	})
+
+	when("#LayersWithSizes", func() {
+		when("the image is in the daemon", func() {
+			it("measures the layers of the saved image, following links to shared layers", func() {
+				saved := savedImage(
+					map[string]int{"bottom/layer.tar": 3072, "middle/layer.tar": 1024, "some-config.json": 10},
+					map[string]string{"top/layer.tar": "../middle/layer.tar"},
+					"bottom/layer.tar", "middle/layer.tar", "top/layer.tar",
+				)
+				mockDocker.EXPECT().ImageSave(gomock.Any(), []string{"some-image-id"}).Return(ioutil.NopCloser(bytes.NewReader(saved)), nil)
+
+				layers, err := subject.LayersWithSizes(context.TODO(), img)
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, layers, []image.Layer{
+					{DiffID: "sha256:bottom", Size: 3072, CompressedSize: -1},
+					{DiffID: "sha256:middle", Size: 1024, CompressedSize: -1},
+					{DiffID: "sha256:top", Size: 1024, CompressedSize: -1},
+				})
+			})
+
+			it("measures the layers of an image saved in the OCI layout", func() {
+				saved := savedImage(
+					map[string]int{"blobs/sha256/bottom": 3072, "blobs/sha256/middle": 1024, "blobs/sha256/top": 0},
+					nil,
+					"blobs/sha256/bottom", "blobs/sha256/middle", "blobs/sha256/top",
+				)
+				mockDocker.EXPECT().ImageSave(gomock.Any(), []string{"some-image-id"}).Return(ioutil.NopCloser(bytes.NewReader(saved)), nil)
+
+				layers, err := subject.LayersWithSizes(context.TODO(), img)
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, layers[0].Size, int64(3072))
+				h.AssertEq(t, layers[1].Size, int64(1024))
+				h.AssertEq(t, layers[2].Size, int64(0))
+			})
+
+			it("leaves the sizes unknown when the saved image does not match", func() {
+				saved := savedImage(
+					map[string]int{"bottom/layer.tar": 3072},
+					nil,
+					"bottom/layer.tar",
+				)
+				mockDocker.EXPECT().ImageSave(gomock.Any(), []string{"some-image-id"}).Return(ioutil.NopCloser(bytes.NewReader(saved)), nil)
+
+				layers, err := subject.LayersWithSizes(context.TODO(), img)
+				h.AssertNil(t, err)
+
+				for _, layer := range layers {
+					h.AssertEq(t, layer.Size, int64(-1))
+				}
+			})
+		})
+	})
+}
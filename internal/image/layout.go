@@ -0,0 +1,21 @@
+package image
+
+import (
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/pkg/errors"
+)
+
+// WriteLayout writes an OCI image layout holding only img to dir, replacing the index of any layout already there.
+func WriteLayout(dir string, img v1.Image, options ...layout.Option) error {
+	p, err := layout.Write(dir, empty.Index)
+	if err != nil {
+		return errors.Wrap(err, "writing index")
+	}
+
+	if err := p.AppendImage(img, options...); err != nil {
+		return errors.Wrap(err, "writing layout")
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package image
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// ParsePlatform parses a platform in the form '<os>/<arch>[/<variant>]', such as 'linux/arm64'.
+func ParsePlatform(platform string) (*v1.Platform, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return nil, errors.Errorf("platform %s must be in the form '<os>/<arch>[/<variant>]'", style.Symbol(platform))
+	}
+
+	p := &v1.Platform{OS: parts[0], Architecture: parts[1]}
+	if len(parts) == 3 {
+		p.Variant = parts[2]
+	}
+	return p, nil
+}
+
+// PlatformString formats a platform in the form ParsePlatform accepts.
+func PlatformString(platform v1.Platform) string {
+	s := fmt.Sprintf("%s/%s", platform.OS, platform.Architecture)
+	if platform.Variant != "" {
+		s += "/" + platform.Variant
+	}
+	return s
+}
+
+// ResolvePlatform returns the digest reference of the manifest for platform, when the registry image imageName is an index.
+// Any other image is returned unchanged, to be checked with CheckPlatform once fetched.
+func ResolvePlatform(imageName string, platform v1.Platform, keychain authn.Keychain) (string, error) {
+	ref, err := name.ParseReference(imageName, name.WeakValidation)
+	if err != nil {
+		return "", err
+	}
+
+	desc, err := remote.Get(ref, remote.WithAuthFromKeychain(keychain))
+	if err != nil {
+		if transportErr, ok := err.(*transport.Error); ok && transportErr.StatusCode == http.StatusNotFound {
+			return "", errors.Wrapf(ErrNotFound, "image %s does not exist in registry", style.Symbol(imageName))
+		}
+		return "", errors.Wrapf(err, "reading manifest of %s", style.Symbol(imageName))
+	}
+
+	if !desc.MediaType.IsIndex() {
+		return imageName, nil
+	}
+
+	index, err := desc.ImageIndex()
+	if err != nil {
+		return "", err
+	}
+
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return "", err
+	}
+
+	var available []string
+	for _, m := range manifest.Manifests {
+		if m.Platform == nil {
+			continue
+		}
+		if matchesPlatform(*m.Platform, platform) {
+			return ref.Context().Digest(m.Digest.String()).Name(), nil
+		}
+		available = append(available, PlatformString(*m.Platform))
+	}
+
+	return "", errors.Errorf("image %s has no manifest for platform %s, only for: %s",
+		style.Symbol(imageName), style.Symbol(PlatformString(platform)), strings.Join(available, ", "))
+}
+
+// CheckPlatform checks that img was built for the OS and architecture of platform.
+func CheckPlatform(img imgutil.Image, platform v1.Platform) error {
+	os, err := img.OS()
+	if err != nil {
+		return errors.Wrapf(err, "reading OS of %s", style.Symbol(img.Name()))
+	}
+
+	arch, err := img.Architecture()
+	if err != nil {
+		return errors.Wrapf(err, "reading architecture of %s", style.Symbol(img.Name()))
+	}
+
+	if os != platform.OS || arch != platform.Architecture {
+		return errors.Errorf("image %s is for platform %s, not %s",
+			style.Symbol(img.Name()), style.Symbol(os+"/"+arch), style.Symbol(PlatformString(platform)))
+	}
+	return nil
+}
+
+// matchesPlatform reports whether an image for candidate runs on platform, ignoring the variant when platform leaves it out.
+func matchesPlatform(candidate, platform v1.Platform) bool {
+	return candidate.OS == platform.OS &&
+		candidate.Architecture == platform.Architecture &&
+		(platform.Variant == "" || candidate.Variant == platform.Variant)
+}
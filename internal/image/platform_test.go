@@ -0,0 +1,150 @@
+package image_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/buildpacks/imgutil/fakes"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/image"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestPlatform(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Platform", testPlatform, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testPlatform(t *testing.T, when spec.G, it spec.S) {
+	var arm64 = v1.Platform{OS: "linux", Architecture: "arm64"}
+
+	when("#ParsePlatform", func() {
+		it("parses an os and architecture", func() {
+			platform, err := image.ParsePlatform("linux/arm64")
+			h.AssertNil(t, err)
+			h.AssertEq(t, *platform, arm64)
+		})
+
+		it("parses a variant", func() {
+			platform, err := image.ParsePlatform("linux/arm/v7")
+			h.AssertNil(t, err)
+			h.AssertEq(t, *platform, v1.Platform{OS: "linux", Architecture: "arm", Variant: "v7"})
+		})
+
+		for _, invalid := range []string{"linux", "linux/", "/arm64", "linux/arm/v7/extra"} {
+			invalid := invalid
+			it("errors for "+invalid, func() {
+				_, err := image.ParsePlatform(invalid)
+				h.AssertError(t, err, "must be in the form '<os>/<arch>[/<variant>]'")
+			})
+		}
+	})
+
+	when("#ResolvePlatform", func() {
+		var (
+			server   *httptest.Server
+			repoName string
+		)
+
+		it.Before(func() {
+			server = httptest.NewServer(registry.New())
+			repoName = strings.TrimPrefix(server.URL, "http://") + "/some/image"
+		})
+
+		it.After(func() {
+			server.Close()
+		})
+
+		writeIndex := func(platforms ...v1.Platform) map[string]v1.Hash {
+			digests := map[string]v1.Hash{}
+			index := v1.ImageIndex(empty.Index)
+			for _, platform := range platforms {
+				img, err := random.Image(1024, 1)
+				h.AssertNil(t, err)
+				digest, err := img.Digest()
+				h.AssertNil(t, err)
+
+				p := platform
+				index = mutate.AppendManifests(index, mutate.IndexAddendum{
+					Add:        img,
+					Descriptor: v1.Descriptor{Platform: &p},
+				})
+				digests[image.PlatformString(platform)] = digest
+			}
+
+			ref, err := name.ParseReference(repoName)
+			h.AssertNil(t, err)
+			h.AssertNil(t, remote.WriteIndex(ref, index))
+			return digests
+		}
+
+		it("returns the digest reference of the manifest for the platform", func() {
+			digests := writeIndex(v1.Platform{OS: "linux", Architecture: "amd64"}, arm64)
+
+			resolved, err := image.ResolvePlatform(repoName, arm64, authn.DefaultKeychain)
+			h.AssertNil(t, err)
+			h.AssertEq(t, resolved, repoName+"@"+digests["linux/arm64"].String())
+		})
+
+		it("matches any variant when the platform has none", func() {
+			digests := writeIndex(v1.Platform{OS: "linux", Architecture: "arm", Variant: "v7"})
+
+			resolved, err := image.ResolvePlatform(repoName, v1.Platform{OS: "linux", Architecture: "arm"}, authn.DefaultKeychain)
+			h.AssertNil(t, err)
+			h.AssertEq(t, resolved, repoName+"@"+digests["linux/arm/v7"].String())
+		})
+
+		it("errors when no manifest is for the platform", func() {
+			writeIndex(v1.Platform{OS: "linux", Architecture: "amd64"}, v1.Platform{OS: "windows", Architecture: "amd64"})
+
+			_, err := image.ResolvePlatform(repoName, arm64, authn.DefaultKeychain)
+			h.AssertError(t, err, "has no manifest for platform 'linux/arm64', only for: linux/amd64, windows/amd64")
+		})
+
+		it("returns the name of an image that is not an index", func() {
+			img, err := random.Image(1024, 1)
+			h.AssertNil(t, err)
+			ref, err := name.ParseReference(repoName)
+			h.AssertNil(t, err)
+			h.AssertNil(t, remote.Write(ref, img))
+
+			resolved, err := image.ResolvePlatform(repoName, arm64, authn.DefaultKeychain)
+			h.AssertNil(t, err)
+			h.AssertEq(t, resolved, repoName)
+		})
+
+		it("returns ErrNotFound when the image does not exist", func() {
+			_, err := image.ResolvePlatform(repoName, arm64, authn.DefaultKeychain)
+			h.AssertEq(t, errors.Is(err, image.ErrNotFound), true)
+		})
+	})
+
+	when("#CheckPlatform", func() {
+		it("accepts an image for the platform", func() {
+			img := fakes.NewImage("some/image", "", nil)
+			h.AssertNil(t, img.SetArchitecture("arm64"))
+
+			h.AssertNil(t, image.CheckPlatform(img, arm64))
+		})
+
+		it("errors for an image for another platform", func() {
+			img := fakes.NewImage("some/image", "", nil)
+
+			h.AssertError(t, image.CheckPlatform(img, arm64), "image 'some/image' is for platform 'linux/amd64', not 'linux/arm64'")
+		})
+	})
+}
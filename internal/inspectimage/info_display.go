@@ -37,12 +37,33 @@ type BaseDisplay struct {
 	Reference string `json:"reference" yaml:"reference" toml:"reference"`
 }
 
+// LayerDisplay is a layer of an image. Sizes are in bytes, and left out when unknown.
+type LayerDisplay struct {
+	DiffID         string `json:"diff_id" yaml:"diff_id" toml:"diff_id"`
+	Origin         string `json:"origin" yaml:"origin" toml:"origin"`
+	Buildpack      string `json:"buildpack,omitempty" yaml:"buildpack,omitempty" toml:"buildpack,omitempty"`
+	Name           string `json:"name,omitempty" yaml:"name,omitempty" toml:"name,omitempty"`
+	Size           *int64 `json:"size,omitempty" yaml:"size,omitempty" toml:"size,omitempty"`
+	CompressedSize *int64 `json:"compressed_size,omitempty" yaml:"compressed_size,omitempty" toml:"compressed_size,omitempty"`
+}
+
+// LayerTotalDisplay totals the layers of an origin, or of a buildpack. Sizes are left out when any is unknown.
+type LayerTotalDisplay struct {
+	Origin         string `json:"origin" yaml:"origin" toml:"origin"`
+	Buildpack      string `json:"buildpack,omitempty" yaml:"buildpack,omitempty" toml:"buildpack,omitempty"`
+	Layers         int    `json:"layers" yaml:"layers" toml:"layers"`
+	Size           *int64 `json:"size,omitempty" yaml:"size,omitempty" toml:"size,omitempty"`
+	CompressedSize *int64 `json:"compressed_size,omitempty" yaml:"compressed_size,omitempty" toml:"compressed_size,omitempty"`
+}
+
 type InfoDisplay struct {
 	StackID         string                  `json:"stack" yaml:"stack" toml:"stack"`
 	Base            BaseDisplay             `json:"base_image" yaml:"base_image" toml:"base_image"`
 	RunImageMirrors []RunImageMirrorDisplay `json:"run_images" yaml:"run_images" toml:"run_images"`
 	Buildpacks      []dist.BuildpackInfo    `json:"buildpacks" yaml:"buildpacks" toml:"buildpacks"`
 	Processes       []ProcessDisplay        `json:"processes" yaml:"processes" toml:"processes"`
+	Layers          []LayerDisplay          `json:"layers,omitempty" yaml:"layers,omitempty" toml:"layers,omitempty"`
+	LayerTotals     []LayerTotalDisplay     `json:"layer_totals,omitempty" yaml:"layer_totals,omitempty" toml:"layer_totals,omitempty"`
 }
 
 type InspectOutput struct {
@@ -61,6 +82,8 @@ func NewInfoDisplay(info *pack.ImageInfo, generalInfo GeneralInfo) *InfoDisplay
 		RunImageMirrors: displayMirrors(info, generalInfo),
 		Buildpacks:      displayBuildpacks(info.Buildpacks),
 		Processes:       displayProcesses(info.Processes),
+		Layers:          displayLayers(info.Layers),
+		LayerTotals:     displayLayerTotals(info.Layers),
 	}
 }
 
@@ -150,6 +173,59 @@ func displayProcesses(details pack.ProcessDetails) []ProcessDisplay {
 	return result
 }
 
+func displayLayers(layers []pack.ImageLayer) []LayerDisplay {
+	var result []LayerDisplay
+	for _, layer := range layers {
+		result = append(result, LayerDisplay{
+			DiffID:         layer.DiffID,
+			Origin:         string(layer.Origin),
+			Buildpack:      layer.Buildpack,
+			Name:           layer.Name,
+			Size:           knownSize(layer.Size),
+			CompressedSize: knownSize(layer.CompressedSize),
+		})
+	}
+	return result
+}
+
+// displayLayerTotals totals the layers of each buildpack, and of each other origin, in the order they first occur.
+func displayLayerTotals(layers []pack.ImageLayer) []LayerTotalDisplay {
+	var result []LayerTotalDisplay
+	index := map[LayerTotalDisplay]int{}
+	for _, layer := range layers {
+		key := LayerTotalDisplay{Origin: string(layer.Origin), Buildpack: layer.Buildpack}
+		i, ok := index[key]
+		if !ok {
+			i = len(result)
+			index[key] = i
+			total := key
+			total.Size, total.CompressedSize = new(int64), new(int64)
+			result = append(result, total)
+		}
+
+		result[i].Layers++
+		result[i].Size = addSize(result[i].Size, layer.Size)
+		result[i].CompressedSize = addSize(result[i].CompressedSize, layer.CompressedSize)
+	}
+	return result
+}
+
+func knownSize(size int64) *int64 {
+	if size < 0 {
+		return nil
+	}
+	return &size
+}
+
+// addSize adds size to total, leaving total unknown when either is.
+func addSize(total *int64, size int64) *int64 {
+	if total == nil || size < 0 {
+		return nil
+	}
+	sum := *total + size
+	return &sum
+}
+
 func convertToDisplay(proc launch.Process, isDefault bool) ProcessDisplay {
 	var shell string
 	switch proc.Direct {
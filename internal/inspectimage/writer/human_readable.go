@@ -7,6 +7,8 @@ import (
 	"text/tabwriter"
 	"text/template"
 
+	"github.com/docker/go-units"
+
 	"github.com/buildpacks/pack/internal/inspectimage"
 
 	"github.com/buildpacks/pack"
@@ -55,12 +57,19 @@ func writeImageInfo(
 	err error,
 ) error {
 	imgTpl := template.Must(template.New("runImages").
-		Funcs(template.FuncMap{"StringsJoin": strings.Join}).
+		Funcs(template.FuncMap{
+			"StringsJoin": strings.Join,
+			"ShortDiffID": shortDiffID,
+			"LayerOrigin": layerOrigin,
+			"LayerSize":   layerSize,
+		}).
 		Parse(runImagesTemplate))
 	imgTpl = template.Must(imgTpl.New("buildpacks").
 		Parse(buildpacksTemplate))
 	imgTpl = template.Must(imgTpl.New("processes").
 		Parse(processesTemplate))
+	imgTpl = template.Must(imgTpl.New("layers").
+		Parse(layersTemplate))
 	imgTpl = template.Must(imgTpl.New("image").
 		Parse(imageTemplate))
 	if err != nil {
@@ -139,6 +148,22 @@ Processes:
   {{- end }}
 {{- end }}`
 
+var layersTemplate = `
+{{- if .Info.Layers }}
+
+Layers:
+  DIFF ID	ORIGIN	SIZE	COMPRESSED SIZE
+{{- range $_, $l := .Info.Layers }}
+  {{ ShortDiffID $l.DiffID }}	{{ LayerOrigin $l.Origin $l.Buildpack $l.Name }}	{{ LayerSize $l.Size }}	{{ LayerSize $l.CompressedSize }}
+{{- end }}
+
+Layer Totals:
+  ORIGIN	LAYERS	SIZE	COMPRESSED SIZE
+{{- range $_, $t := .Info.LayerTotals }}
+  {{ LayerOrigin $t.Origin $t.Buildpack "" }}	{{ $t.Layers }}	{{ LayerSize $t.Size }}	{{ LayerSize $t.CompressedSize }}
+{{- end }}
+{{- end }}`
+
 var imageTemplate = `
 Stack: {{ .Info.StackID }}
 
@@ -148,4 +173,34 @@ Base Image:
 {{- end}}
   Top Layer: {{ .Info.Base.TopLayer }}
 {{ template "runImages" . }}
-{{ template "buildpacks" . }}{{ template "processes" . }}`
+{{ template "buildpacks" . }}{{ template "processes" . }}{{ template "layers" . }}`
+
+// shortDiffID abbreviates a diff ID to the first 12 characters of its digest, as docker does.
+func shortDiffID(diffID string) string {
+	algorithm, digest := "", diffID
+	if i := strings.Index(diffID, ":"); i >= 0 {
+		algorithm, digest = diffID[:i+1], diffID[i+1:]
+	}
+	if len(digest) > 12 {
+		digest = digest[:12]
+	}
+	return algorithm + digest
+}
+
+func layerOrigin(origin, buildpack, name string) string {
+	switch {
+	case buildpack != "" && name != "":
+		return fmt.Sprintf("%s %s (%s)", origin, buildpack, name)
+	case buildpack != "":
+		return fmt.Sprintf("%s %s", origin, buildpack)
+	default:
+		return origin
+	}
+}
+
+func layerSize(size *int64) string {
+	if size == nil {
+		return "-"
+	}
+	return units.HumanSize(float64(*size))
+}
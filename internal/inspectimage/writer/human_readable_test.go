@@ -330,6 +330,44 @@ Processes:
 					assert.Contains(outBuf.String(), "Run Images:\n  (none)")
 				})
 			})
+
+			when("the image has layers", func() {
+				it.Before(func() {
+					remoteInfo.Layers = []pack.ImageLayer{
+						{DiffID: "sha256:1111111111111111111111", Size: 1000, CompressedSize: 400, Origin: pack.LayerOriginRunImage},
+						{DiffID: "sha256:2222222222222222222222", Size: 2000000, CompressedSize: 800000, Origin: pack.LayerOriginBuildpack, Buildpack: "test.bp.one.remote", Name: "some-layer"},
+						{DiffID: "sha256:3333333333333333333333", Size: 3000000, CompressedSize: 1000000, Origin: pack.LayerOriginBuildpack, Buildpack: "test.bp.one.remote", Name: "other-layer"},
+						{DiffID: "sha256:4444444444444444444444", Size: 50, CompressedSize: -1, Origin: pack.LayerOriginApp},
+					}
+				})
+
+				it("displays the layers and their totals", func() {
+					sharedImageInfo := inspectimage.GeneralInfo{
+						Name:            "test-image",
+						RunImageMirrors: []config.RunImage{},
+					}
+					humanReadableWriter := writer.NewHumanReadable()
+
+					logger := ilogging.NewLogWithWriters(&outBuf, &outBuf)
+					err := humanReadableWriter.Print(logger, sharedImageInfo, nil, remoteInfo, nil, nil)
+					assert.Nil(err)
+
+					assert.Contains(outBuf.String(), `
+Layers:
+  DIFF ID                    ORIGIN                                            SIZE        COMPRESSED SIZE
+  sha256:111111111111        run-image                                         1kB         400B
+  sha256:222222222222        buildpack test.bp.one.remote (some-layer)         2MB         800kB
+  sha256:333333333333        buildpack test.bp.one.remote (other-layer)        3MB         1MB
+  sha256:444444444444        app                                               50B         -
+
+Layer Totals:
+  ORIGIN                              LAYERS        SIZE        COMPRESSED SIZE
+  run-image                           1             1kB         400B
+  buildpack test.bp.one.remote        2             5MB         1.8MB
+  app                                 1             50B         -
+`)
+				})
+			})
 		})
 
 		when("error handled cases", func() {
@@ -345,6 +345,76 @@ args = [
 				assert.NotContains(outBuf.String(), "test.stack.id.local")
 				assert.ContainsTOML(outBuf.String(), expectedRemoteOutput)
 			})
+
+			when("the image has layers", func() {
+				it.Before(func() {
+					remoteInfo.Layers = []pack.ImageLayer{
+						{DiffID: "sha256:1111111111111111111111", Size: 1000, CompressedSize: 400, Origin: pack.LayerOriginRunImage},
+						{DiffID: "sha256:2222222222222222222222", Size: 2000000, CompressedSize: 800000, Origin: pack.LayerOriginBuildpack, Buildpack: "test.bp.one.remote", Name: "some-layer"},
+						{DiffID: "sha256:3333333333333333333333", Size: 3000000, CompressedSize: 1000000, Origin: pack.LayerOriginBuildpack, Buildpack: "test.bp.one.remote", Name: "other-layer"},
+						{DiffID: "sha256:4444444444444444444444", Size: 50, CompressedSize: -1, Origin: pack.LayerOriginApp},
+					}
+				})
+
+				it("prints the layers and their totals", func() {
+					sharedImageInfo := inspectimage.GeneralInfo{
+						Name:            "test-image",
+						RunImageMirrors: []config.RunImage{},
+					}
+					tomlWriter := writer.NewTOML()
+
+					logger := ilogging.NewLogWithWriters(&outBuf, &outBuf)
+					err := tomlWriter.Print(logger, sharedImageInfo, nil, remoteInfo, nil, nil)
+					assert.Nil(err)
+
+					assert.ContainsTOML(outBuf.String(), `
+[[layers]]
+  diff_id = "sha256:1111111111111111111111"
+  origin = "run-image"
+  size = 1000
+  compressed_size = 400
+
+[[layers]]
+  diff_id = "sha256:2222222222222222222222"
+  origin = "buildpack"
+  buildpack = "test.bp.one.remote"
+  name = "some-layer"
+  size = 2000000
+  compressed_size = 800000
+
+[[layers]]
+  diff_id = "sha256:3333333333333333333333"
+  origin = "buildpack"
+  buildpack = "test.bp.one.remote"
+  name = "other-layer"
+  size = 3000000
+  compressed_size = 1000000
+
+[[layers]]
+  diff_id = "sha256:4444444444444444444444"
+  origin = "app"
+  size = 50
+
+[[layer_totals]]
+  origin = "run-image"
+  layers = 1
+  size = 1000
+  compressed_size = 400
+
+[[layer_totals]]
+  origin = "buildpack"
+  buildpack = "test.bp.one.remote"
+  layers = 2
+  size = 5000000
+  compressed_size = 1800000
+
+[[layer_totals]]
+  origin = "app"
+  layers = 1
+  size = 50
+`)
+				})
+			})
 		})
 	})
 }
@@ -30,10 +30,11 @@ const (
 type LogWithWriters struct {
 	sync.Mutex
 	log.Logger
-	wantTime bool
-	clock    func() time.Time
-	out      io.Writer
-	errOut   io.Writer
+	wantTime     bool
+	logsOnStderr bool
+	clock        func() time.Time
+	out          io.Writer
+	errOut       io.Writer
 }
 
 // NewLogWithWriters creates a logger to be used with pack CLI.
@@ -87,7 +88,7 @@ func (lw *LogWithWriters) WriterForLevel(level logging.Level) io.Writer {
 		return ioutil.Discard
 	}
 
-	if level == logging.ErrorLevel {
+	if level == logging.ErrorLevel || lw.logsOnStderr {
 		return NewLogWriter(lw.errOut, lw.clock, lw.wantTime)
 	}
 
@@ -104,6 +105,12 @@ func (lw *LogWithWriters) WantTime(f bool) {
 	lw.wantTime = f
 }
 
+// WantLogsOnStderr writes logs of every level to stderr, keeping stdout, the base Writer,
+// for the output of a command, such as a json report
+func (lw *LogWithWriters) WantLogsOnStderr(f bool) {
+	lw.logsOnStderr = f
+}
+
 // WantQuiet reduces the number of logs returned
 func (lw *LogWithWriters) WantQuiet(f bool) {
 	if f {
@@ -217,6 +217,31 @@ func testLogWithWriters(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("logs on stderr is set to true", func() {
+		it.Before(func() {
+			logger.WantLogsOnStderr(true)
+		})
+
+		it("logs every message to error writer", func() {
+			logger.Info("info_")
+			logger.Warn("warn_")
+			logger.Error("error_")
+
+			h.AssertEq(t, fOut(), "")
+			output := fErr()
+			h.AssertContains(t, output, "info_\n")
+			h.AssertContains(t, output, "warn_\n")
+			h.AssertContains(t, output, "error_\n")
+		})
+
+		it("will return correct writers", func() {
+			h.AssertSameInstance(t, logger.Writer(), outCons)
+			assertLogWriterHasOut(t, logger.WriterForLevel(logging.InfoLevel), errCons)
+			assertLogWriterHasOut(t, logger.WriterForLevel(logging.WarnLevel), errCons)
+			assertLogWriterHasOut(t, logger.WriterForLevel(logging.ErrorLevel), errCons)
+		})
+	})
+
 	it("will convert an empty string to a line feed", func() {
 		logger.Info("")
 		expected := "\n"
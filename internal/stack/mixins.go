@@ -53,3 +53,18 @@ func removeStageMixins(mixins []string, stage string) []string {
 	}
 	return filtered
 }
+
+// FindMissingRunMixins returns the mixins of requiredMixins that runImageMixins lacks, ignoring 'run:' stage prefixes.
+func FindMissingRunMixins(requiredMixins []string, runImageMixins []string) []string {
+	_, missing, _ := stringset.Compare(trimStagePrefix(runImageMixins, "run"), trimStagePrefix(requiredMixins, "run"))
+	sort.Strings(missing)
+	return missing
+}
+
+func trimStagePrefix(mixins []string, stage string) []string {
+	var trimmed []string
+	for _, m := range mixins {
+		trimmed = append(trimmed, strings.TrimPrefix(m, stage+":"))
+	}
+	return trimmed
+}
@@ -70,4 +70,19 @@ func testMixinValidation(t *testing.T, when spec.G, it spec.S) {
 			h.AssertEq(t, runMixins, []string{"run:mixinB", "run:mixinD"})
 		})
 	})
+
+	when("#FindMissingRunMixins", func() {
+		it("returns the required mixins the run image lacks, ignoring run stage prefixes", func() {
+			required := []string{"mixinA", "run:mixinB", "mixinD", "run:mixinC"}
+			runMixins := []string{"run:mixinA", "mixinB", "mixinE"}
+
+			missing := stack.FindMissingRunMixins(required, runMixins)
+
+			h.AssertEq(t, missing, []string{"mixinC", "mixinD"})
+		})
+
+		it("returns nothing when the run image provides every required mixin", func() {
+			h.AssertEq(t, len(stack.FindMissingRunMixins([]string{"mixinA"}, []string{"mixinA", "mixinB"})), 0)
+		})
+	})
 }
@@ -0,0 +1,158 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	// DefaultInterval is how often the watched directory is checked for changes.
+	DefaultInterval = 500 * time.Millisecond
+
+	// DefaultQuietPeriod is how long the watched directory must go without changes
+	// before the changes are reported, so that a burst of changes is reported once.
+	DefaultQuietPeriod = time.Second
+)
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+	mode    os.FileMode
+}
+
+// Watcher reports changes to the files in a directory, by polling it.
+type Watcher struct {
+	dir         string
+	fileFilter  func(string) bool
+	interval    time.Duration
+	quietPeriod time.Duration
+	files       map[string]fileState
+}
+
+type Option func(*Watcher)
+
+func WithInterval(interval time.Duration) Option {
+	return func(w *Watcher) {
+		w.interval = interval
+	}
+}
+
+func WithQuietPeriod(quietPeriod time.Duration) Option {
+	return func(w *Watcher) {
+		w.quietPeriod = quietPeriod
+	}
+}
+
+// NewWatcher starts watching the files in dir that pass fileFilter, which receives the path of each file
+// as it would when the directory is copied into a build.
+func NewWatcher(dir string, fileFilter func(string) bool, opts ...Option) (*Watcher, error) {
+	w := &Watcher{
+		dir:         dir,
+		fileFilter:  fileFilter,
+		interval:    DefaultInterval,
+		quietPeriod: DefaultQuietPeriod,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	var err error
+	if w.files, err = w.scan(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Wait blocks until files have changed since the last call, and then have not changed for the quiet period.
+// It returns the changed files relative to the watched directory, or the error of ctx once it is done.
+func (w *Watcher) Wait(ctx context.Context) ([]string, error) {
+	changed := map[string]bool{}
+	var lastChange time.Time
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case now := <-ticker.C:
+			files, err := w.scan()
+			if err != nil {
+				return nil, err
+			}
+
+			if diff := changes(w.files, files); len(diff) > 0 {
+				for _, file := range diff {
+					changed[file] = true
+				}
+				lastChange = now
+				w.files = files
+				continue
+			}
+
+			if len(changed) > 0 && now.Sub(lastChange) >= w.quietPeriod {
+				var result []string
+				for file := range changed {
+					result = append(result, file)
+				}
+				sort.Strings(result)
+				return result, nil
+			}
+		}
+	}
+}
+
+func (w *Watcher) scan() (map[string]fileState, error) {
+	files := map[string]fileState{}
+	err := filepath.Walk(w.dir, func(file string, fi os.FileInfo, err error) error {
+		if w.fileFilter != nil && !w.fileFilter(file) {
+			return nil
+		}
+		if err != nil {
+			// files may be removed while walking
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		relPath, err := filepath.Rel(w.dir, file)
+		if err != nil {
+			return err
+		} else if relPath == "." {
+			return nil
+		}
+
+		// the mod time of directories changes with files excluded by the filter, while changes to those
+		// included show up in their own state
+		if fi.IsDir() {
+			files[relPath] = fileState{mode: fi.Mode()}
+			return nil
+		}
+
+		files[relPath] = fileState{modTime: fi.ModTime(), size: fi.Size(), mode: fi.Mode()}
+		return nil
+	})
+	return files, err
+}
+
+// changes returns the files that were added, removed or modified between two scans.
+func changes(before, after map[string]fileState) []string {
+	var changed []string
+	for file, state := range after {
+		if previous, ok := before[file]; !ok || previous != state {
+			changed = append(changed, file)
+		}
+	}
+	for file := range before {
+		if _, ok := after[file]; !ok {
+			changed = append(changed, file)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,112 @@
+package watch_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/watch"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestWatcher(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Watcher", testWatcher, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testWatcher(t *testing.T, when spec.G, it spec.S) {
+	var (
+		tmpDir string
+		opts   []watch.Option
+	)
+
+	writeFile := func(name, contents string) {
+		path := filepath.Join(tmpDir, name)
+		h.AssertNil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		h.AssertNil(t, ioutil.WriteFile(path, []byte(contents), 0644))
+	}
+
+	it.Before(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "watcher")
+		h.AssertNil(t, err)
+
+		writeFile("some-file.txt", "some-contents")
+		writeFile("sub-dir/other-file.txt", "other-contents")
+
+		opts = []watch.Option{watch.WithInterval(10 * time.Millisecond), watch.WithQuietPeriod(50 * time.Millisecond)}
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	when("#Wait", func() {
+		it("reports added, modified and removed files once they settle", func() {
+			watcher, err := watch.NewWatcher(tmpDir, nil, opts...)
+			h.AssertNil(t, err)
+
+			writeFile("some-file.txt", "modified-contents")
+			writeFile("new-file.txt", "new-contents")
+			h.AssertNil(t, os.Remove(filepath.Join(tmpDir, "sub-dir", "other-file.txt")))
+
+			changed, err := watcher.Wait(context.TODO())
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, changed, []string{"new-file.txt", "some-file.txt", filepath.Join("sub-dir", "other-file.txt")})
+		})
+
+		it("reports a burst of changes at once", func() {
+			watcher, err := watch.NewWatcher(tmpDir, nil, opts...)
+			h.AssertNil(t, err)
+
+			go func() {
+				for i := 0; i < 3; i++ {
+					writeFile("some-file.txt", strings.Repeat("x", i+1))
+					time.Sleep(20 * time.Millisecond)
+				}
+				writeFile("new-file.txt", "new-contents")
+			}()
+
+			changed, err := watcher.Wait(context.TODO())
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, changed, []string{"new-file.txt", "some-file.txt"})
+		})
+
+		it("ignores files excluded by the filter", func() {
+			watcher, err := watch.NewWatcher(tmpDir, func(path string) bool {
+				return !strings.HasSuffix(path, ".log")
+			}, opts...)
+			h.AssertNil(t, err)
+
+			writeFile(filepath.Join("sub-dir", "some.log"), "some-log")
+			writeFile("some-file.txt", "modified-contents")
+
+			changed, err := watcher.Wait(context.TODO())
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, changed, []string{"some-file.txt"})
+		})
+
+		it("returns when the context is done", func() {
+			watcher, err := watch.NewWatcher(tmpDir, nil, opts...)
+			h.AssertNil(t, err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			_, err = watcher.Wait(ctx)
+			h.AssertError(t, err, "context deadline exceeded")
+		})
+	})
+}
@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// PrefixLogger is a logger that prefixes each line logged, or written to its writers, before passing it on to another logger.
+// It allows the output of concurrent operations sharing a logger to be told apart.
+type PrefixLogger struct {
+	logger     Logger
+	name       string
+	linePrefix string
+}
+
+// NewPrefixLogger messages logged by l will be prefixed
+func NewPrefixLogger(l Logger, prefix string) *PrefixLogger {
+	return &PrefixLogger{
+		logger:     l,
+		name:       prefix,
+		linePrefix: fmt.Sprintf("[%s] ", style.Prefix(prefix)),
+	}
+}
+
+func (l *PrefixLogger) Debug(msg string) {
+	l.logger.Debug(l.prefix(msg))
+}
+
+func (l *PrefixLogger) Debugf(format string, v ...interface{}) {
+	l.logger.Debug(l.prefix(fmt.Sprintf(format, v...)))
+}
+
+func (l *PrefixLogger) Info(msg string) {
+	l.logger.Info(l.prefix(msg))
+}
+
+func (l *PrefixLogger) Infof(format string, v ...interface{}) {
+	l.logger.Info(l.prefix(fmt.Sprintf(format, v...)))
+}
+
+func (l *PrefixLogger) Warn(msg string) {
+	l.logger.Warn(l.prefix(msg))
+}
+
+func (l *PrefixLogger) Warnf(format string, v ...interface{}) {
+	l.logger.Warn(l.prefix(fmt.Sprintf(format, v...)))
+}
+
+func (l *PrefixLogger) Error(msg string) {
+	l.logger.Error(l.prefix(msg))
+}
+
+func (l *PrefixLogger) Errorf(format string, v ...interface{}) {
+	l.logger.Error(l.prefix(fmt.Sprintf(format, v...)))
+}
+
+func (l *PrefixLogger) Writer() io.Writer {
+	return l.prefixWriter(l.logger.Writer())
+}
+
+// WriterForLevel returns a writer that prefixes lines written to the writer of the underlying logger for the level.
+func (l *PrefixLogger) WriterForLevel(level Level) io.Writer {
+	return l.prefixWriter(GetWriterForLevel(l.logger, level))
+}
+
+func (l *PrefixLogger) IsVerbose() bool {
+	return l.logger.IsVerbose()
+}
+
+func (l *PrefixLogger) prefixWriter(w io.Writer) io.Writer {
+	// keep a quiet logger quiet
+	if w == ioutil.Discard {
+		return w
+	}
+	return NewPrefixWriter(w, l.name)
+}
+
+// prefix prefixes each line of msg.
+func (l *PrefixLogger) prefix(msg string) string {
+	lines := strings.Split(msg, "\n")
+	for i, line := range lines {
+		if line != "" || i < len(lines)-1 {
+			lines[i] = l.linePrefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
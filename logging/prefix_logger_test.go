@@ -0,0 +1,54 @@
+package logging_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	ilogging "github.com/buildpacks/pack/internal/logging"
+	"github.com/buildpacks/pack/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestPrefixLogger(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "PrefixLogger", testPrefixLogger, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testPrefixLogger(t *testing.T, when spec.G, it spec.S) {
+	var (
+		out    bytes.Buffer
+		logger *logging.PrefixLogger
+	)
+
+	it.Before(func() {
+		logger = logging.NewPrefixLogger(ilogging.NewLogWithWriters(&out, &out), "some-app")
+	})
+
+	it("prefixes each line of messages", func() {
+		logger.Infof("first %s\nsecond line", "line")
+		logger.Error("some-error")
+
+		h.AssertEq(t, out.String(), "[some-app] first line\n[some-app] second line\nERROR: [some-app] some-error\n")
+	})
+
+	it("prefixes lines written to its writers", func() {
+		_, err := fmt.Fprint(logging.GetWriterForLevel(logger, logging.InfoLevel), "some-output\n")
+		h.AssertNil(t, err)
+
+		h.AssertEq(t, out.String(), "[some-app] some-output\n")
+	})
+
+	it("stays quiet when the logger is quiet", func() {
+		quietLogger := ilogging.NewLogWithWriters(&out, &out)
+		quietLogger.WantQuiet(true)
+
+		h.AssertEq(t, logging.GetWriterForLevel(logging.NewPrefixLogger(quietLogger, "some-app"), logging.InfoLevel), ioutil.Discard)
+	})
+}
@@ -0,0 +1,82 @@
+package pack
+
+import (
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	v1remote "github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/image"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+const (
+	// OCIOutputFormat saves the built image as an OCI image layout directory, as in 'oci:<dir>'.
+	OCIOutputFormat = "oci"
+
+	// DockerArchiveOutputFormat saves the built image as a tarball that 'docker load' accepts, as in 'docker-archive:<file.tar>'.
+	DockerArchiveOutputFormat = "docker-archive"
+
+	// annotation naming the image in the index of an OCI image layout
+	ociRefNameAnnotation = "org.opencontainers.image.ref.name"
+)
+
+// imageOutput is a file the built image is saved to.
+type imageOutput struct {
+	format string
+	path   string
+}
+
+// parseImageOutput parses an output in the form '<format>:<path>'.
+func parseImageOutput(output string) (*imageOutput, error) {
+	parts := strings.SplitN(output, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, errors.Errorf("output must be in the form '%s:<dir>' or '%s:<file.tar>'", OCIOutputFormat, DockerArchiveOutputFormat)
+	}
+
+	switch parts[0] {
+	case OCIOutputFormat, DockerArchiveOutputFormat:
+		return &imageOutput{format: parts[0], path: parts[1]}, nil
+	default:
+		return nil, errors.Errorf("unknown output format %s, must be one of %s or %s",
+			style.Symbol(parts[0]), style.Symbol(OCIOutputFormat), style.Symbol(DockerArchiveOutputFormat))
+	}
+}
+
+// saveImage saves the built image, read from the registry if it was published or else from the docker daemon, to output.
+func (c *Client) saveImage(publish bool, imageRef name.Reference, output *imageOutput) error {
+	var (
+		img v1.Image
+		err error
+	)
+	if publish {
+		img, err = v1remote.Image(imageRef, v1remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	} else {
+		// stream the image from the daemon each time it is read, rather than holding it in memory
+		img, err = daemon.Image(imageRef, daemon.WithClient(c.docker), daemon.WithUnbufferedOpener())
+	}
+	if err != nil {
+		return errors.Wrapf(err, "reading built image %s", style.Symbol(imageRef.Name()))
+	}
+
+	switch output.format {
+	case OCIOutputFormat:
+		err = image.WriteLayout(output.path, img, layout.WithAnnotations(map[string]string{
+			ociRefNameAnnotation: imageRef.Name(),
+		}))
+	case DockerArchiveOutputFormat:
+		err = tarball.WriteToFile(output.path, imageRef, img)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "saving image to %s", style.Symbol(output.path))
+	}
+
+	c.logger.Infof("Saved image %s to %s", style.Symbol(imageRef.Name()), style.Symbol(output.path))
+	return nil
+}
@@ -0,0 +1,140 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+	"github.com/buildpacks/pack/testmocks"
+)
+
+func TestOutput(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Output", testOutput, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testOutput(t *testing.T, when spec.G, it spec.S) {
+	when("#parseImageOutput", func() {
+		it("parses an OCI layout output", func() {
+			output, err := parseImageOutput("oci:some/dir")
+			h.AssertNil(t, err)
+			h.AssertEq(t, output.format, OCIOutputFormat)
+			h.AssertEq(t, output.path, "some/dir")
+		})
+
+		it("parses a docker archive output", func() {
+			output, err := parseImageOutput("docker-archive:some/file.tar")
+			h.AssertNil(t, err)
+			h.AssertEq(t, output.format, DockerArchiveOutputFormat)
+			h.AssertEq(t, output.path, "some/file.tar")
+		})
+
+		it("errors when the path is missing", func() {
+			_, err := parseImageOutput("oci:")
+			h.AssertError(t, err, "output must be in the form 'oci:<dir>' or 'docker-archive:<file.tar>'")
+		})
+
+		it("errors on an unknown format", func() {
+			_, err := parseImageOutput("zip:some/file.zip")
+			h.AssertError(t, err, "unknown output format 'zip'")
+		})
+	})
+
+	when("#saveImage", func() {
+		var (
+			mockController   *gomock.Controller
+			mockDockerClient *testmocks.MockCommonAPIClient
+			subject          *Client
+			tmpDir           string
+			imageRef         name.Reference
+			builtImage       v1.Image
+			out              bytes.Buffer
+		)
+
+		it.Before(func() {
+			mockController = gomock.NewController(t)
+			mockDockerClient = testmocks.NewMockCommonAPIClient(mockController)
+
+			var err error
+			subject, err = NewClient(WithLogger(logging.NewLogWithWriters(&out, &out)), WithDockerClient(mockDockerClient))
+			h.AssertNil(t, err)
+
+			tmpDir, err = ioutil.TempDir("", "pack.output.test.")
+			h.AssertNil(t, err)
+
+			imageRef, err = name.NewTag("some/app:latest")
+			h.AssertNil(t, err)
+
+			builtImage, err = random.Image(1024, 2)
+			h.AssertNil(t, err)
+
+			var saved bytes.Buffer
+			h.AssertNil(t, tarball.Write(imageRef, builtImage, &saved))
+
+			mockDockerClient.EXPECT().NegotiateAPIVersion(gomock.Any()).AnyTimes()
+			mockDockerClient.EXPECT().ImageSave(gomock.Any(), []string{imageRef.Name()}).
+				DoAndReturn(func(context.Context, []string) (io.ReadCloser, error) {
+					return ioutil.NopCloser(bytes.NewReader(saved.Bytes())), nil
+				}).AnyTimes()
+		})
+
+		it.After(func() {
+			mockController.Finish()
+			h.AssertNil(t, os.RemoveAll(tmpDir))
+		})
+
+		it("saves an image from the daemon to an OCI layout", func() {
+			layoutDir := filepath.Join(tmpDir, "layout")
+			h.AssertNil(t, subject.saveImage(false, imageRef, &imageOutput{format: OCIOutputFormat, path: layoutDir}))
+
+			index, err := layout.ImageIndexFromPath(layoutDir)
+			h.AssertNil(t, err)
+
+			manifest, err := index.IndexManifest()
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(manifest.Manifests), 1)
+			h.AssertEq(t, manifest.Manifests[0].Annotations[ociRefNameAnnotation], imageRef.Name())
+
+			saved, err := index.Image(manifest.Manifests[0].Digest)
+			h.AssertNil(t, err)
+
+			expectedConfig, err := builtImage.ConfigName()
+			h.AssertNil(t, err)
+			actualConfig, err := saved.ConfigName()
+			h.AssertNil(t, err)
+			h.AssertEq(t, actualConfig, expectedConfig)
+			h.AssertContains(t, out.String(), "Saved image 'index.docker.io/some/app:latest'")
+		})
+
+		it("saves an image from the daemon to a docker archive", func() {
+			archivePath := filepath.Join(tmpDir, "image.tar")
+			h.AssertNil(t, subject.saveImage(false, imageRef, &imageOutput{format: DockerArchiveOutputFormat, path: archivePath}))
+
+			saved, err := tarball.ImageFromPath(archivePath, nil)
+			h.AssertNil(t, err)
+
+			expectedConfig, err := builtImage.ConfigName()
+			h.AssertNil(t, err)
+			actualConfig, err := saved.ConfigName()
+			h.AssertNil(t, err)
+			h.AssertEq(t, actualConfig, expectedConfig)
+		})
+	})
+}
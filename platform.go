@@ -0,0 +1,117 @@
+package pack
+
+import (
+	"context"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/google/go-containerregistry/pkg/authn"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/config"
+	"github.com/buildpacks/pack/internal/image"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// daemonArchitectures maps the machine names the docker daemon reports to the architectures of images.
+var daemonArchitectures = map[string]string{
+	"x86_64":  "amd64",
+	"aarch64": "arm64",
+	"armv7l":  "arm",
+	"i386":    "386",
+	"i686":    "386",
+}
+
+// parsePlatform parses an optional platform, returning nil when none is given.
+func parsePlatform(platform string) (*v1.Platform, error) {
+	if platform == "" {
+		return nil, nil
+	}
+	return image.ParsePlatform(platform)
+}
+
+// fetchForPlatform fetches an image as the image fetcher does, selecting the manifest for platform when the image is an index.
+// Images already on the daemon are used when the pull policy allows it, as long as they are for platform.
+func (c *Client) fetchForPlatform(ctx context.Context, name string, daemon bool, pullPolicy config.PullPolicy, platform *v1.Platform) (imgutil.Image, error) {
+	if platform == nil {
+		return c.imageFetcher.Fetch(ctx, name, daemon, pullPolicy)
+	}
+
+	if daemon && pullPolicy != config.PullAlways {
+		img, err := c.imageFetcher.Fetch(ctx, name, daemon, config.PullNever)
+		if err == nil {
+			err = image.CheckPlatform(img, *platform)
+		}
+		if err == nil || pullPolicy == config.PullNever {
+			return img, err
+		}
+	}
+
+	resolvedName, err := image.ResolvePlatform(name, *platform, authn.DefaultKeychain)
+	switch {
+	case daemon && errors.Is(err, image.ErrNotFound):
+		// the fetcher falls back to the daemon for images missing from the registry
+		resolvedName = name
+	case err != nil:
+		return nil, err
+	}
+
+	img, err := c.imageFetcher.Fetch(ctx, resolvedName, daemon, pullPolicy)
+	if err != nil {
+		return nil, err
+	}
+	return img, image.CheckPlatform(img, *platform)
+}
+
+// imagePlatform returns the platform img was built for, or nil when it does not record one.
+func imagePlatform(img imgutil.Image) (*v1.Platform, error) {
+	os, err := img.OS()
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading OS of %s", style.Symbol(img.Name()))
+	}
+
+	arch, err := img.Architecture()
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading architecture of %s", style.Symbol(img.Name()))
+	}
+
+	if os == "" || arch == "" {
+		return nil, nil
+	}
+	return &v1.Platform{OS: os, Architecture: arch}, nil
+}
+
+// validateDaemonPlatform checks that the docker daemon can run containers from img.
+// Architectures other than that of the daemon are only warned about, as they may be emulated.
+func (c *Client) validateDaemonPlatform(ctx context.Context, img imgutil.Image) error {
+	info, err := c.docker.Info(ctx)
+	if err != nil {
+		return errors.Wrap(err, "getting docker info")
+	}
+
+	imgOS, err := img.OS()
+	if err != nil {
+		return errors.Wrapf(err, "reading OS of %s", style.Symbol(img.Name()))
+	}
+
+	if imgOS != info.OSType {
+		return errors.Errorf("image %s is for OS %s, but the docker daemon runs %s",
+			style.Symbol(img.Name()), style.Symbol(imgOS), style.Symbol(info.OSType))
+	}
+
+	arch, err := img.Architecture()
+	if err != nil {
+		return errors.Wrapf(err, "reading architecture of %s", style.Symbol(img.Name()))
+	}
+
+	daemonArch := info.Architecture
+	if mapped, ok := daemonArchitectures[daemonArch]; ok {
+		daemonArch = mapped
+	}
+
+	if arch != daemonArch {
+		c.logger.Warnf("Image %s is for architecture %s, but the docker daemon runs on %s. Its containers will only run if the daemon emulates %s.",
+			style.Symbol(img.Name()), style.Symbol(arch), style.Symbol(daemonArch), style.Symbol(arch))
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package project
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+)
+
+// App is an app of a repository holding several apps.
+type App struct {
+	// Name prefixes the output of the build of the app.
+	// Defaults to the name of the app directory.
+	Name string `toml:"name"`
+
+	// Image to build.
+	Image string `toml:"image"`
+
+	// Path to the app directory, relative to the manifest.
+	// Defaults to the directory of the manifest.
+	Path string `toml:"path"`
+
+	// Builder to build the app with, instead of the default builder.
+	Builder string `toml:"builder"`
+
+	// Build-time environment variables of the app, in addition to those of its project descriptor.
+	Env map[string]string `toml:"env"`
+}
+
+// Manifest lists the apps of a repository to build together.
+// It is either a file of its own, or the [[apps]] table of a root project descriptor.
+type Manifest struct {
+	Apps []App `toml:"apps"`
+}
+
+// ReadManifest reads the apps of a manifest, resolving their relative paths relative to it.
+func ReadManifest(pathToFile string) (Manifest, error) {
+	manifestContents, err := ioutil.ReadFile(pathToFile)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	var manifest Manifest
+	if _, err := toml.Decode(string(manifestContents), &manifest); err != nil {
+		return Manifest{}, err
+	}
+
+	manifestDir, err := filepath.Abs(filepath.Dir(pathToFile))
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	for i := range manifest.Apps {
+		app := &manifest.Apps[i]
+		app.Path = filepath.FromSlash(app.Path)
+		if !filepath.IsAbs(app.Path) {
+			app.Path = filepath.Join(manifestDir, app.Path)
+		}
+		if app.Name == "" {
+			app.Name = filepath.Base(app.Path)
+		}
+	}
+
+	return manifest, manifest.validate()
+}
+
+func (m Manifest) validate() error {
+	if len(m.Apps) == 0 {
+		return errors.New("manifest: must define at least one app in [[apps]]")
+	}
+
+	names := map[string]bool{}
+	for _, app := range m.Apps {
+		if app.Image == "" {
+			return errors.Errorf("manifest: app '%s' must have an image defined", app.Name)
+		}
+		if names[app.Name] {
+			return errors.Errorf("manifest: app name '%s' must be unique", app.Name)
+		}
+		names[app.Name] = true
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package project
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestManifest(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "Manifest", testManifest, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testManifest(t *testing.T, when spec.G, it spec.S) {
+	var tmpDir string
+
+	writeManifest := func(contents string) string {
+		path := filepath.Join(tmpDir, "apps.toml")
+		h.AssertNil(t, ioutil.WriteFile(path, []byte(contents), 0644))
+		return path
+	}
+
+	it.Before(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "manifest")
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		h.AssertNil(t, os.RemoveAll(tmpDir))
+	})
+
+	when("#ReadManifest", func() {
+		it("reads the apps, resolving their paths relative to the manifest", func() {
+			manifest, err := ReadManifest(writeManifest(`
+[[apps]]
+image = "some-registry/api"
+path = "services/api"
+builder = "some/builder"
+env = { SOME_VAR = "some-value" }
+
+[[apps]]
+name = "frontend"
+image = "some-registry/web"
+path = "services/web"
+`))
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, manifest.Apps, []App{
+				{
+					Name:    "api",
+					Image:   "some-registry/api",
+					Path:    filepath.Join(tmpDir, "services", "api"),
+					Builder: "some/builder",
+					Env:     map[string]string{"SOME_VAR": "some-value"},
+				},
+				{
+					Name:  "frontend",
+					Image: "some-registry/web",
+					Path:  filepath.Join(tmpDir, "services", "web"),
+				},
+			})
+		})
+
+		it("keeps absolute app paths as they are", func() {
+			appDir := filepath.Join(tmpDir, "elsewhere", "api")
+			manifest, err := ReadManifest(writeManifest(`
+[[apps]]
+image = "some-registry/api"
+path = "` + filepath.ToSlash(appDir) + `"
+`))
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(manifest.Apps), 1)
+			h.AssertEq(t, manifest.Apps[0].Path, appDir)
+			h.AssertEq(t, manifest.Apps[0].Name, "api")
+		})
+
+		it("reads the apps of a root project descriptor", func() {
+			path := filepath.Join(tmpDir, "project.toml")
+			h.AssertNil(t, ioutil.WriteFile(path, []byte(`
+[project]
+name = "monorepo"
+
+[[apps]]
+image = "some-registry/api"
+path = "api"
+`), 0644))
+
+			manifest, err := ReadManifest(path)
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(manifest.Apps), 1)
+			h.AssertEq(t, manifest.Apps[0].Name, "api")
+		})
+
+		it("errors when there are no apps", func() {
+			_, err := ReadManifest(writeManifest(`[project]`))
+			h.AssertError(t, err, "manifest: must define at least one app in [[apps]]")
+		})
+
+		it("errors when an app has no image", func() {
+			_, err := ReadManifest(writeManifest(`
+[[apps]]
+path = "api"
+`))
+			h.AssertError(t, err, "manifest: app 'api' must have an image defined")
+		})
+
+		it("errors when app names are not unique", func() {
+			_, err := ReadManifest(writeManifest(`
+[[apps]]
+image = "some-registry/api"
+path = "v1/api"
+
+[[apps]]
+image = "some-registry/api-v2"
+path = "v2/api"
+`))
+			h.AssertError(t, err, "manifest: app name 'api' must be unique")
+		})
+	})
+}
@@ -0,0 +1,76 @@
+package project
+
+import (
+	"bytes"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// Migrate rewrites the contents of a project descriptor in the layout of LatestSchemaVersion,
+// reporting whether they changed. Tables the descriptor does not define, such as those of other tools,
+// are kept as they are, as are the tables of its metadata. Comments are not kept.
+func Migrate(contents string) (string, bool, error) {
+	var raw map[string]interface{}
+	md, err := toml.Decode(contents, &raw)
+	if err != nil {
+		return "", false, err
+	}
+
+	if md.IsDefined("_") {
+		schema, _ := raw["_"].(map[string]interface{})
+		if version, _ := schema["schema-version"].(string); version != LatestSchemaVersion {
+			return "", false, errors.Errorf("project.toml: unsupported schema-version %s, must be %s",
+				style.Symbol(version), style.Symbol(LatestSchemaVersion))
+		}
+		return contents, false, nil
+	}
+
+	schema := map[string]interface{}{"schema-version": LatestSchemaVersion}
+	if legacyProject, ok := raw["project"].(map[string]interface{}); ok {
+		for k, v := range legacyProject {
+			schema[k] = v
+		}
+	}
+	if metadata, ok := raw["metadata"]; ok {
+		schema["metadata"] = metadata
+	}
+
+	buildpacks := map[string]interface{}{}
+	if legacyBuild, ok := raw["build"].(map[string]interface{}); ok {
+		for k, v := range legacyBuild {
+			switch k {
+			case "buildpacks":
+				buildpacks["group"] = v
+			case "env":
+				buildpacks["build"] = map[string]interface{}{"env": v}
+			default:
+				buildpacks[k] = v
+			}
+		}
+	}
+
+	delete(raw, "project")
+	delete(raw, "build")
+	delete(raw, "metadata")
+
+	raw["_"] = schema
+	if len(buildpacks) > 0 {
+		io, ok := raw["io"].(map[string]interface{})
+		if !ok {
+			io = map[string]interface{}{}
+		}
+		io["buildpacks"] = buildpacks
+		raw["io"] = io
+	}
+
+	var buf bytes.Buffer
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = ""
+	if err := enc.Encode(raw); err != nil {
+		return "", false, errors.Wrap(err, "encoding project descriptor")
+	}
+	return buf.String(), true, nil
+}
@@ -0,0 +1,76 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestMigrate(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Migrate", testMigrate, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testMigrate(t *testing.T, when spec.G, it spec.S) {
+	when("#Migrate", func() {
+		it("migrates a descriptor without a schema version to the latest schema version", func() {
+			legacy := `
+[project]
+name = "gallant"
+[[project.licenses]]
+type = "MIT"
+[build]
+exclude = [ "*.jar" ]
+[[build.buildpacks]]
+id = "example/lua"
+version = "1.0"
+[[build.env]]
+name = "JAVA_OPTS"
+value = "-Xmx300m"
+[metadata]
+pipeline = "Lucerne"
+[metadata.deploy]
+region = "eu"
+[com.example.tool]
+setting = true
+`
+			migrated, changed, err := Migrate(legacy)
+			h.AssertNil(t, err)
+			h.AssertEq(t, changed, true)
+			h.AssertContains(t, migrated, `schema-version = "0.2"`)
+			h.AssertContains(t, migrated, "[com.example.tool]")
+
+			legacyFile, err := createTmpProjectTomlFile(legacy)
+			h.AssertNil(t, err)
+			migratedFile, err := createTmpProjectTomlFile(migrated)
+			h.AssertNil(t, err)
+
+			expected, err := ReadProjectDescriptor(legacyFile.Name())
+			h.AssertNil(t, err)
+			actual, err := ReadProjectDescriptor(migratedFile.Name())
+			h.AssertNil(t, err)
+
+			expected.SchemaVersion = LatestSchemaVersion
+			h.AssertEq(t, actual, expected)
+		})
+
+		it("leaves a descriptor of the latest schema version unchanged", func() {
+			contents := "[_]\nschema-version = \"0.2\"\n"
+
+			migrated, changed, err := Migrate(contents)
+			h.AssertNil(t, err)
+			h.AssertEq(t, changed, false)
+			h.AssertEq(t, migrated, contents)
+		})
+
+		it("fails for an unsupported schema version", func() {
+			_, _, err := Migrate("[_]\nschema-version = \"9.9\"\n")
+			h.AssertError(t, err, "unsupported schema-version '9.9'")
+		})
+	})
+}
@@ -5,12 +5,33 @@ import (
 
 	"github.com/BurntSushi/toml"
 	"github.com/pkg/errors"
+	ignore "github.com/sabhiram/go-gitignore"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/logging"
+)
+
+const (
+	// LegacySchemaVersion is the schema version of descriptors without a [_] table,
+	// with [project], [build] and [metadata] tables.
+	LegacySchemaVersion = "0.1"
+
+	// LatestSchemaVersion is the schema version of descriptors with a [_] table and an [io.buildpacks] table.
+	LatestSchemaVersion = "0.2"
 )
 
 type Buildpack struct {
 	ID      string `toml:"id"`
 	Version string `toml:"version"`
 	URI     string `toml:"uri"`
+	Script  Script `toml:"script"`
+}
+
+// Script defines an inline buildpack, whose build runs the script with a shell.
+type Script struct {
+	API    string `toml:"api"`
+	Shell  string `toml:"shell"`
+	Inline string `toml:"inline"`
 }
 
 type EnvVar struct {
@@ -23,6 +44,14 @@ type Build struct {
 	Exclude    []string    `toml:"exclude"`
 	Buildpacks []Buildpack `toml:"buildpacks"`
 	Env        []EnvVar    `toml:"env"`
+
+	// Settings of the build of the project, used unless given on the command line.
+	Builder        string   `toml:"builder"`
+	RunImage       string   `toml:"run-image"`
+	DefaultProcess string   `toml:"default-process"`
+	PullPolicy     string   `toml:"pull-policy"`
+	Tags           []string `toml:"tags"`
+	Volumes        []string `toml:"volumes"`
 }
 
 type License struct {
@@ -31,31 +60,148 @@ type License struct {
 }
 
 type Project struct {
-	Name     string    `toml:"name"`
-	Licenses []License `toml:"licenses"`
+	ID               string    `toml:"id"`
+	Name             string    `toml:"name"`
+	Version          string    `toml:"version"`
+	Authors          []string  `toml:"authors"`
+	DocumentationURL string    `toml:"documentation-url"`
+	SourceURL        string    `toml:"source-url"`
+	Licenses         []License `toml:"licenses"`
 }
 
+// Descriptor is a project descriptor, whatever its schema version.
 type Descriptor struct {
+	// Schema version the descriptor was read from.
+	SchemaVersion string `toml:"-"`
+
 	Project  Project                `toml:"project"`
 	Build    Build                  `toml:"build"`
 	Metadata map[string]interface{} `toml:"metadata"`
 }
 
+// descriptorV02 is the layout of a descriptor of schema version 0.2.
+type descriptorV02 struct {
+	Project projectV02 `toml:"_"`
+	IO      struct {
+		Buildpacks buildV02 `toml:"buildpacks"`
+	} `toml:"io"`
+}
+
+type projectV02 struct {
+	SchemaVersion string `toml:"schema-version"`
+	Project
+	Metadata map[string]interface{} `toml:"metadata"`
+}
+
+type buildV02 struct {
+	Include        []string    `toml:"include"`
+	Exclude        []string    `toml:"exclude"`
+	Group          []Buildpack `toml:"group"`
+	Builder        string      `toml:"builder"`
+	RunImage       string      `toml:"run-image"`
+	DefaultProcess string      `toml:"default-process"`
+	PullPolicy     string      `toml:"pull-policy"`
+	Tags           []string    `toml:"tags"`
+	Volumes        []string    `toml:"volumes"`
+	Build          struct {
+		Env []EnvVar `toml:"env"`
+	} `toml:"build"`
+}
+
+// ReadProjectDescriptor reads a project descriptor of any supported schema version.
 func ReadProjectDescriptor(pathToFile string) (Descriptor, error) {
+	return ReadProjectDescriptorWithLogger(pathToFile, logging.New(ioutil.Discard))
+}
+
+// ReadProjectDescriptorWithLogger reads a project descriptor in the same way as ReadProjectDescriptor,
+// warning logger about deprecated forms.
+func ReadProjectDescriptorWithLogger(pathToFile string, logger logging.Logger) (Descriptor, error) {
 	projectTomlContents, err := ioutil.ReadFile(pathToFile)
 	if err != nil {
 		return Descriptor{}, err
 	}
 
-	var descriptor Descriptor
-	_, err = toml.Decode(string(projectTomlContents), &descriptor)
+	var versioned struct {
+		Schema struct {
+			SchemaVersion string `toml:"schema-version"`
+		} `toml:"_"`
+	}
+	md, err := toml.Decode(string(projectTomlContents), &versioned)
 	if err != nil {
 		return Descriptor{}, err
 	}
 
+	var descriptor Descriptor
+	switch {
+	case !md.IsDefined("_"):
+		logger.Warnf("Project descriptor %s has no schema version and uses a deprecated layout. Run 'pack project migrate' to update it to schema version %s.",
+			style.Symbol(pathToFile), LatestSchemaVersion)
+		if _, err := toml.Decode(string(projectTomlContents), &descriptor); err != nil {
+			return Descriptor{}, err
+		}
+		descriptor.SchemaVersion = LegacySchemaVersion
+	case versioned.Schema.SchemaVersion == LatestSchemaVersion:
+		for _, legacyTable := range []string{"project", "build", "metadata"} {
+			if md.IsDefined(legacyTable) {
+				logger.Warnf("Ignoring deprecated table %s of project descriptor %s, which has schema version %s.",
+					style.Symbol("["+legacyTable+"]"), style.Symbol(pathToFile), LatestSchemaVersion)
+			}
+		}
+		if descriptor, err = decodeV02(string(projectTomlContents)); err != nil {
+			return Descriptor{}, err
+		}
+	default:
+		return Descriptor{}, errors.Errorf("project.toml: unsupported schema-version %s, must be %s",
+			style.Symbol(versioned.Schema.SchemaVersion), style.Symbol(LatestSchemaVersion))
+	}
+
 	return descriptor, descriptor.validate()
 }
 
+// decodeV02 decodes a descriptor of schema version 0.2 into the model every schema version is read into.
+func decodeV02(contents string) (Descriptor, error) {
+	var v02 descriptorV02
+	if _, err := toml.Decode(contents, &v02); err != nil {
+		return Descriptor{}, err
+	}
+
+	return Descriptor{
+		SchemaVersion: v02.Project.SchemaVersion,
+		Project:       v02.Project.Project,
+		Build: Build{
+			Include:    v02.IO.Buildpacks.Include,
+			Exclude:    v02.IO.Buildpacks.Exclude,
+			Buildpacks: v02.IO.Buildpacks.Group,
+			Env:        v02.IO.Buildpacks.Build.Env,
+
+			Builder:        v02.IO.Buildpacks.Builder,
+			RunImage:       v02.IO.Buildpacks.RunImage,
+			DefaultProcess: v02.IO.Buildpacks.DefaultProcess,
+			PullPolicy:     v02.IO.Buildpacks.PullPolicy,
+			Tags:           v02.IO.Buildpacks.Tags,
+			Volumes:        v02.IO.Buildpacks.Volumes,
+		},
+		Metadata: v02.Project.Metadata,
+	}, nil
+}
+
+// FileFilter returns a function that reports whether a file of the app is part of the build,
+// according to the include or exclude lists. It returns nil if every file is part of the build.
+func (p Descriptor) FileFilter() func(string) bool {
+	if len(p.Build.Exclude) > 0 {
+		excludes := ignore.CompileIgnoreLines(p.Build.Exclude...)
+		return func(fileName string) bool {
+			return !excludes.MatchesPath(fileName)
+		}
+	}
+	if len(p.Build.Include) > 0 {
+		includes := ignore.CompileIgnoreLines(p.Build.Include...)
+		return includes.MatchesPath
+	}
+
+	return nil
+}
+
 func (p Descriptor) validate() error {
 	if p.Build.Exclude != nil && p.Build.Include != nil {
 		return errors.New("project.toml: cannot have both include and exclude defined")
@@ -75,6 +221,14 @@ func (p Descriptor) validate() error {
 		if bp.URI != "" && bp.Version != "" {
 			return errors.New("project.toml: buildpacks cannot have both uri and version defined")
 		}
+		if bp.Script.Inline != "" {
+			if bp.ID == "" || bp.URI != "" {
+				return errors.New("project.toml: buildpacks with a script must have an id and no uri defined")
+			}
+			if bp.Script.API == "" {
+				return errors.New("project.toml: buildpacks with a script must have a script api defined")
+			}
+		}
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package project
 
 import (
+	"bytes"
 	"io/ioutil"
 	"log"
 	"math/rand"
@@ -12,6 +13,8 @@ import (
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
+	ilogging "github.com/buildpacks/pack/internal/logging"
+	"github.com/buildpacks/pack/logging"
 	h "github.com/buildpacks/pack/testhelpers"
 )
 
@@ -25,6 +28,15 @@ func TestProject(t *testing.T) {
 }
 
 func testProject(t *testing.T, when spec.G, it spec.S) {
+	var (
+		outBuf bytes.Buffer
+		logger logging.Logger
+	)
+
+	it.Before(func() {
+		logger = ilogging.NewLogWithWriters(&outBuf, &outBuf)
+	})
+
 	when("#ReadProjectDescriptor", func() {
 		it("should parse a valid project.toml file", func() {
 			projectToml := `
@@ -133,6 +145,97 @@ name = "gallant"
 			}
 		})
 
+		it("should warn that a descriptor without a schema version is deprecated", func() {
+			tmpProjectToml, err := createTmpProjectTomlFile(`
+[project]
+name = "gallant"
+`)
+			h.AssertNil(t, err)
+
+			projectDescriptor, err := ReadProjectDescriptorWithLogger(tmpProjectToml.Name(), logger)
+			h.AssertNil(t, err)
+			h.AssertEq(t, projectDescriptor.SchemaVersion, "0.1")
+			h.AssertContains(t, outBuf.String(), "has no schema version and uses a deprecated layout. Run 'pack project migrate'")
+		})
+
+		it("should parse a project.toml file of schema version 0.2", func() {
+			tmpProjectToml, err := createTmpProjectTomlFile(`
+[_]
+schema-version = "0.2"
+id = "io.buildpacks.gallant"
+name = "gallant"
+version = "1.0.0"
+
+[[_.licenses]]
+type = "MIT"
+
+[_.metadata]
+pipeline = "Lucerne"
+
+[io.buildpacks]
+exclude = [ "*.jar" ]
+builder = "cnbs/sample-builder:bionic"
+pull-policy = "if-not-present"
+
+[[io.buildpacks.group]]
+id = "example/lua"
+version = "1.0"
+
+[[io.buildpacks.build.env]]
+name = "JAVA_OPTS"
+value = "-Xmx300m"
+`)
+			h.AssertNil(t, err)
+
+			projectDescriptor, err := ReadProjectDescriptorWithLogger(tmpProjectToml.Name(), logger)
+			h.AssertNil(t, err)
+			h.AssertEq(t, projectDescriptor, Descriptor{
+				SchemaVersion: "0.2",
+				Project: Project{
+					ID:       "io.buildpacks.gallant",
+					Name:     "gallant",
+					Version:  "1.0.0",
+					Licenses: []License{{Type: "MIT"}},
+				},
+				Build: Build{
+					Exclude:    []string{"*.jar"},
+					Buildpacks: []Buildpack{{ID: "example/lua", Version: "1.0"}},
+					Env:        []EnvVar{{Name: "JAVA_OPTS", Value: "-Xmx300m"}},
+					Builder:    "cnbs/sample-builder:bionic",
+					PullPolicy: "if-not-present",
+				},
+				Metadata: map[string]interface{}{"pipeline": "Lucerne"},
+			})
+			h.AssertEq(t, outBuf.String(), "")
+		})
+
+		it("should warn about legacy tables of a descriptor of schema version 0.2", func() {
+			tmpProjectToml, err := createTmpProjectTomlFile(`
+[_]
+schema-version = "0.2"
+
+[build]
+exclude = [ "*.jar" ]
+`)
+			h.AssertNil(t, err)
+
+			projectDescriptor, err := ReadProjectDescriptorWithLogger(tmpProjectToml.Name(), logger)
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(projectDescriptor.Build.Exclude), 0)
+			h.AssertContains(t, outBuf.String(), "Ignoring deprecated table '[build]'")
+		})
+
+		it("should fail for an unsupported schema version", func() {
+			tmpProjectToml, err := createTmpProjectTomlFile(`
+[_]
+schema-version = "9.9"
+`)
+			h.AssertNil(t, err)
+
+			_, err = ReadProjectDescriptor(tmpProjectToml.Name())
+			h.AssertError(t, err, "unsupported schema-version '9.9', must be '0.2'")
+		})
+
 		it("should fail for an invalid project.toml path", func() {
 			_, err := ReadProjectDescriptor("/path/that/does/not/exist/project.toml")
 
@@ -202,6 +305,74 @@ version = "1.2.3"
 		}
 	})
 
+	it("should parse inline buildpack scripts", func() {
+		projectToml := `
+[project]
+name = "inline buildpack"
+
+[[build.buildpacks]]
+id = "example/migrate"
+
+  [build.buildpacks.script]
+  api = "0.4"
+  shell = "/bin/bash"
+  inline = "./migrate.sh"
+`
+		tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		projectDescriptor, err := ReadProjectDescriptor(tmpProjectToml.Name())
+		h.AssertNil(t, err)
+		h.AssertEq(t, projectDescriptor.Build.Buildpacks[0].Script, Script{
+			API:    "0.4",
+			Shell:  "/bin/bash",
+			Inline: "./migrate.sh",
+		})
+	})
+
+	it("should require an id and no uri for inline buildpacks", func() {
+		projectToml := `
+[project]
+name = "inline buildpack with a uri"
+
+[[build.buildpacks]]
+uri = "https://example.com/buildpack"
+
+  [build.buildpacks.script]
+  api = "0.4"
+  inline = "echo hello"
+`
+		tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = ReadProjectDescriptor(tmpProjectToml.Name())
+		h.AssertError(t, err, "buildpacks with a script must have an id and no uri defined")
+	})
+
+	it("should require an api for inline buildpacks", func() {
+		projectToml := `
+[project]
+name = "inline buildpack without an api"
+
+[[build.buildpacks]]
+id = "example/migrate"
+
+  [build.buildpacks.script]
+  inline = "echo hello"
+`
+		tmpProjectToml, err := createTmpProjectTomlFile(projectToml)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = ReadProjectDescriptor(tmpProjectToml.Name())
+		h.AssertError(t, err, "buildpacks with a script must have a script api defined")
+	})
+
 	it("should require either a type or uri for licenses", func() {
 		projectToml := `
 [project]
@@ -219,6 +390,28 @@ name = "licenses should have either a type or uri defined"
 			t.Fatal("Expected error for having neither type or uri defined for licenses")
 		}
 	})
+
+	when("#FileFilter", func() {
+		it("excludes the files matching exclude", func() {
+			filter := Descriptor{Build: Build{Exclude: []string{"*.log"}}}.FileFilter()
+
+			h.AssertEq(t, filter("app.go"), true)
+			h.AssertEq(t, filter("debug.log"), false)
+		})
+
+		it("includes only the files matching include", func() {
+			filter := Descriptor{Build: Build{Include: []string{"*.go"}}}.FileFilter()
+
+			h.AssertEq(t, filter("app.go"), true)
+			h.AssertEq(t, filter("debug.log"), false)
+		})
+
+		it("is nil when every file is included", func() {
+			if filter := (Descriptor{}).FileFilter(); filter != nil {
+				t.Fatal("Expected no file filter")
+			}
+		})
+	})
 }
 
 func createTmpProjectTomlFile(projectToml string) (*os.File, error) {
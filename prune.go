@@ -0,0 +1,228 @@
+package pack
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/build"
+	"github.com/buildpacks/pack/internal/cache"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// Kinds of resources removed by Prune.
+const (
+	PruneKindContainer   = "container"
+	PruneKindVolume      = "volume"
+	PruneKindCacheVolume = "cache volume"
+	PruneKindImage       = "image"
+)
+
+const (
+	phaseContainerLabel   = "author"
+	phaseContainerAuthor  = "pack"
+	runningContainerState = "running"
+	unknownSharedSize     = -1
+)
+
+// PruneOptions defines which resources are removed by Prune.
+type PruneOptions struct {
+	// Report what would be removed, without removing anything.
+	DryRun bool
+
+	// Only remove resources created more than this long ago, or for ephemeral builders, last used.
+	// Zero removes resources regardless of their age, except for containers that are still running
+	// and ephemeral builders kept for reuse by later builds.
+	OlderThan time.Duration
+
+	// Also remove build and launch caches.
+	IncludeCaches bool
+}
+
+// PrunedResource is a docker resource that was, or in a dry run would be, removed by Prune.
+type PrunedResource struct {
+	// Kind of resource, one of the PruneKind constants.
+	Kind string
+
+	// Name of the volume or image, or ID of the container.
+	Name string
+
+	// Disk space used by the resource, in bytes.
+	Size int64
+
+	// When the resource was created, or the zero time if unknown.
+	Created time.Time
+}
+
+// PruneReport describes the result of Prune.
+type PruneReport struct {
+	// Resources removed, or that would be removed in a dry run, in the order of removal.
+	Removed []PrunedResource
+
+	// Resources that could not be removed, with the reason why.
+	Failed map[string]error
+
+	// Disk space freed, or that would be freed in a dry run, in bytes.
+	SpaceReclaimed int64
+}
+
+// Prune removes the docker resources that builds leave behind when they are interrupted:
+// phase containers, layers and app volumes and ephemeral builder images.
+// Resources that cannot be removed, for example because they are in use, are reported as failed.
+func (c *Client) Prune(ctx context.Context, opts PruneOptions) (*PruneReport, error) {
+	usage, err := c.docker.DiskUsage(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading docker disk usage")
+	}
+
+	var cutoff time.Time
+	if opts.OlderThan > 0 {
+		cutoff = time.Now().Add(-opts.OlderThan)
+	}
+	isOldEnough := func(created time.Time) bool {
+		return cutoff.IsZero() || (!created.IsZero() && created.Before(cutoff))
+	}
+
+	var candidates []PrunedResource
+	// images of the phase containers that are kept, which may be the ephemeral builders of builds in progress
+	keptImages := map[string]bool{}
+	for _, ctr := range usage.Containers {
+		if ctr.Labels[phaseContainerLabel] != phaseContainerAuthor {
+			continue
+		}
+
+		created := time.Unix(ctr.Created, 0)
+		// a running container may belong to a build in progress, unless it is older than the cutoff
+		if (ctr.State == runningContainerState && cutoff.IsZero()) || !isOldEnough(created) {
+			keptImages[ctr.Image] = true
+			keptImages[ctr.ImageID] = true
+			continue
+		}
+
+		candidates = append(candidates, PrunedResource{Kind: PruneKindContainer, Name: ctr.ID, Size: ctr.SizeRw, Created: created})
+	}
+
+	for _, vol := range usage.Volumes {
+		kind := prunableVolumeKind(vol.Name, opts.IncludeCaches)
+		if kind == "" {
+			continue
+		}
+
+		resource := PrunedResource{Kind: kind, Name: vol.Name}
+		if vol.UsageData != nil {
+			resource.Size = vol.UsageData.Size
+		}
+		if created, err := time.Parse(time.RFC3339, vol.CreatedAt); err == nil {
+			resource.Created = created
+		}
+
+		if isOldEnough(resource.Created) {
+			candidates = append(candidates, resource)
+		}
+	}
+
+	builderUsage, err := builderUsageDir()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, img := range usage.Images {
+		if img.ID != "" && keptImages[img.ID] {
+			continue
+		}
+
+		for _, tag := range img.RepoTags {
+			if !strings.HasPrefix(tag, ephemeralBuilderRepo+"/") || keptImages[tag] {
+				continue
+			}
+
+			// builders with a recorded use are kept for reuse by later builds, and evicted by them,
+			// unless they have not been used for longer than the cutoff
+			lastUsed, err := cache.LastUse(builderUsage, builderUsageKey(tag))
+			if err != nil {
+				return nil, errors.Wrapf(err, "reading use of builder %s", style.Symbol(tag))
+			}
+			if !lastUsed.IsZero() && cutoff.IsZero() {
+				continue
+			}
+
+			resource := PrunedResource{Kind: PruneKindImage, Name: tag, Size: img.Size, Created: time.Unix(img.Created, 0)}
+			// most of an ephemeral builder is shared with the builder it was created from
+			if img.SharedSize != unknownSharedSize {
+				resource.Size -= img.SharedSize
+			}
+
+			if lastUsed.Before(resource.Created) {
+				lastUsed = resource.Created
+			}
+			if isOldEnough(lastUsed) {
+				candidates = append(candidates, resource)
+			}
+		}
+	}
+
+	// containers are removed first, as they hold on to volumes and images
+	order := map[string]int{PruneKindContainer: 0, PruneKindVolume: 1, PruneKindCacheVolume: 2, PruneKindImage: 3}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return order[candidates[i].Kind] < order[candidates[j].Kind]
+	})
+
+	report := &PruneReport{Failed: map[string]error{}}
+	for _, resource := range candidates {
+		if !opts.DryRun {
+			if err := c.removeResource(ctx, resource); err != nil {
+				c.logger.Debugf("Unable to remove %s %s: %s", resource.Kind, style.Symbol(resource.Name), err)
+				report.Failed[resource.Name] = err
+				continue
+			}
+		}
+
+		report.Removed = append(report.Removed, resource)
+		report.SpaceReclaimed += resource.Size
+	}
+	return report, nil
+}
+
+func (c *Client) removeResource(ctx context.Context, resource PrunedResource) error {
+	switch resource.Kind {
+	case PruneKindContainer:
+		return c.docker.ContainerRemove(ctx, resource.Name, types.ContainerRemoveOptions{Force: true})
+	case PruneKindVolume:
+		// volumes are not forced, so that those still used by a build are left alone
+		return c.docker.VolumeRemove(ctx, resource.Name, false)
+	case PruneKindCacheVolume:
+		if err := c.docker.VolumeRemove(ctx, resource.Name, false); err != nil {
+			return err
+		}
+
+		usageDir, err := cacheUsageDir()
+		if err != nil {
+			return err
+		}
+		return cache.ForgetUse(usageDir, resource.Name)
+	default:
+		// images are not forced either, so that the builders of builds in progress are left alone
+		if _, err := c.docker.ImageRemove(ctx, resource.Name, types.ImageRemoveOptions{PruneChildren: true}); err != nil {
+			return err
+		}
+
+		c.forgetBuilderUse(resource.Name)
+		return nil
+	}
+}
+
+// prunableVolumeKind returns the kind of resource a volume is, or an empty string if it is not pruned.
+func prunableVolumeKind(name string, includeCaches bool) string {
+	switch {
+	case strings.HasPrefix(name, build.LayersVolumePrefix), strings.HasPrefix(name, build.AppVolumePrefix):
+		return PruneKindVolume
+	case includeCaches && strings.HasPrefix(name, cache.VolumePrefix):
+		return PruneKindCacheVolume
+	default:
+		return ""
+	}
+}
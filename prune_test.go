@@ -0,0 +1,184 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/cache"
+	"github.com/buildpacks/pack/internal/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+	"github.com/buildpacks/pack/testmocks"
+)
+
+func TestPrune(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Prune", testPrune, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+func testPrune(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		mockDockerClient *testmocks.MockCommonAPIClient
+		mockController   *gomock.Controller
+		out              bytes.Buffer
+		packHome         string
+		now              time.Time
+		old              time.Time
+	)
+
+	removedNames := func(report *PruneReport) []string {
+		var names []string
+		for _, resource := range report.Removed {
+			names = append(names, resource.Name)
+		}
+		return names
+	}
+
+	it.Before(func() {
+		var err error
+		packHome, err = ioutil.TempDir("", "prune-test")
+		h.AssertNil(t, err)
+		h.AssertNil(t, os.Setenv("PACK_HOME", packHome))
+
+		mockController = gomock.NewController(t)
+		mockDockerClient = testmocks.NewMockCommonAPIClient(mockController)
+
+		subject, err = NewClient(WithLogger(logging.NewLogWithWriters(&out, &out)), WithDockerClient(mockDockerClient))
+		h.AssertNil(t, err)
+
+		now = time.Now()
+		old = now.Add(-48 * time.Hour)
+
+		mockDockerClient.EXPECT().DiskUsage(gomock.Any()).Return(types.DiskUsage{
+			Containers: []*types.Container{
+				{ID: "exited-phase", Labels: map[string]string{"author": "pack"}, State: "exited", SizeRw: 10, Created: old.Unix()},
+				{ID: "running-phase", Labels: map[string]string{"author": "pack"}, State: "running", SizeRw: 20, Created: old.Unix(), Image: "pack.local/builder/in-use:latest"},
+				{ID: "other-container", State: "exited", SizeRw: 30, Created: old.Unix()},
+			},
+			Volumes: []*types.Volume{
+				{Name: "pack-layers-abc", CreatedAt: old.Format(time.RFC3339), UsageData: &types.VolumeUsageData{Size: 100}},
+				{Name: "pack-app-abc", CreatedAt: now.Format(time.RFC3339), UsageData: &types.VolumeUsageData{Size: 200}},
+				{Name: "pack-cache-abc.build", CreatedAt: old.Format(time.RFC3339), UsageData: &types.VolumeUsageData{Size: 300}},
+				{Name: "other-volume", CreatedAt: old.Format(time.RFC3339), UsageData: &types.VolumeUsageData{Size: 400}},
+			},
+			Images: []*types.ImageSummary{
+				{RepoTags: []string{"pack.local/builder/abc:latest"}, Size: 1500, SharedSize: 1000, Created: old.Unix()},
+				{RepoTags: []string{"pack.local/builder/in-use:latest"}, Size: 1500, SharedSize: 1000, Created: old.Unix()},
+				{RepoTags: []string{"some/builder:latest"}, Size: 1000, SharedSize: 1000, Created: old.Unix()},
+			},
+		}, nil)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+		os.Unsetenv("PACK_HOME")
+		h.AssertNil(t, os.RemoveAll(packHome))
+	})
+
+	when("#Prune", func() {
+		it("removes the resources left behind by builds", func() {
+			mockDockerClient.EXPECT().ContainerRemove(gomock.Any(), "exited-phase", types.ContainerRemoveOptions{Force: true}).Return(nil)
+			mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), "pack-layers-abc", false).Return(nil)
+			mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), "pack-app-abc", false).Return(nil)
+			mockDockerClient.EXPECT().ImageRemove(gomock.Any(), "pack.local/builder/abc:latest", types.ImageRemoveOptions{PruneChildren: true}).Return(nil, nil)
+
+			report, err := subject.Prune(context.TODO(), PruneOptions{})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, removedNames(report), []string{"exited-phase", "pack-layers-abc", "pack-app-abc", "pack.local/builder/abc:latest"})
+			h.AssertEq(t, report.SpaceReclaimed, int64(10+100+200+500))
+			h.AssertEq(t, len(report.Failed), 0)
+		})
+
+		it("removes nothing in a dry run", func() {
+			report, err := subject.Prune(context.TODO(), PruneOptions{DryRun: true})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, removedNames(report), []string{"exited-phase", "pack-layers-abc", "pack-app-abc", "pack.local/builder/abc:latest"})
+			h.AssertEq(t, report.SpaceReclaimed, int64(810))
+		})
+
+		it("includes caches when requested", func() {
+			report, err := subject.Prune(context.TODO(), PruneOptions{DryRun: true, IncludeCaches: true})
+			h.AssertNil(t, err)
+
+			h.AssertSliceContains(t, removedNames(report), "pack-cache-abc.build")
+		})
+
+		it("only includes old enough resources, including running containers", func() {
+			report, err := subject.Prune(context.TODO(), PruneOptions{DryRun: true, OlderThan: 24 * time.Hour})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, removedNames(report), []string{"exited-phase", "running-phase", "pack-layers-abc", "pack.local/builder/abc:latest", "pack.local/builder/in-use:latest"})
+		})
+
+		it("keeps the builder of a running phase container", func() {
+			report, err := subject.Prune(context.TODO(), PruneOptions{DryRun: true})
+			h.AssertNil(t, err)
+
+			h.AssertSliceNotContains(t, removedNames(report), "pack.local/builder/in-use:latest")
+		})
+
+		when("a builder has a recorded use", func() {
+			useBuilder := func(at time.Time) {
+				usageDir := filepath.Join(packHome, "builder-usage")
+				h.AssertNil(t, cache.RecordUse(usageDir, "abc"))
+				h.AssertNil(t, os.Chtimes(filepath.Join(usageDir, "abc"), at, at))
+			}
+
+			it("keeps it for reuse", func() {
+				useBuilder(old)
+
+				report, err := subject.Prune(context.TODO(), PruneOptions{DryRun: true})
+				h.AssertNil(t, err)
+
+				h.AssertSliceNotContains(t, removedNames(report), "pack.local/builder/abc:latest")
+			})
+
+			it("removes it when it was last used before the cutoff", func() {
+				useBuilder(old)
+
+				report, err := subject.Prune(context.TODO(), PruneOptions{DryRun: true, OlderThan: 24 * time.Hour})
+				h.AssertNil(t, err)
+
+				h.AssertSliceContains(t, removedNames(report), "pack.local/builder/abc:latest")
+			})
+
+			it("keeps it when it was used since the cutoff", func() {
+				useBuilder(now)
+
+				report, err := subject.Prune(context.TODO(), PruneOptions{DryRun: true, OlderThan: 24 * time.Hour})
+				h.AssertNil(t, err)
+
+				h.AssertSliceNotContains(t, removedNames(report), "pack.local/builder/abc:latest")
+			})
+		})
+
+		it("reports resources that cannot be removed", func() {
+			mockDockerClient.EXPECT().ContainerRemove(gomock.Any(), "exited-phase", gomock.Any()).Return(nil)
+			mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), "pack-layers-abc", false).Return(errors.New("volume is in use"))
+			mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), "pack-app-abc", false).Return(nil)
+			mockDockerClient.EXPECT().ImageRemove(gomock.Any(), "pack.local/builder/abc:latest", gomock.Any()).Return(nil, nil)
+
+			report, err := subject.Prune(context.TODO(), PruneOptions{})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, removedNames(report), []string{"exited-phase", "pack-app-abc", "pack.local/builder/abc:latest"})
+			h.AssertError(t, report.Failed["pack-layers-abc"], "volume is in use")
+			h.AssertEq(t, report.SpaceReclaimed, int64(710))
+		})
+	})
+}
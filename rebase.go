@@ -2,14 +2,19 @@ package pack
 
 import (
 	"context"
+	"strings"
 
 	"github.com/buildpacks/pack/config"
 
+	"github.com/buildpacks/imgutil"
 	"github.com/buildpacks/lifecycle"
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 
 	"github.com/buildpacks/pack/internal/builder"
 	"github.com/buildpacks/pack/internal/dist"
+	"github.com/buildpacks/pack/internal/image"
+	"github.com/buildpacks/pack/internal/stack"
 	"github.com/buildpacks/pack/internal/style"
 )
 
@@ -33,21 +38,98 @@ type RebaseOptions struct {
 	// AdditionalMirrors gives us inputs to recalculate the 'best' run image
 	// based on the registry we are publishing to.
 	AdditionalMirrors map[string][]string
+
+	// Platform of the image, in the form '<os>/<arch>[/<variant>]', such as 'linux/arm64'.
+	// It selects the matching image from a run image index.
+	// If unset, the registry chooses the image of an index.
+	Platform string
+
+	// Rebase even when the run image is of another stack than the image,
+	// or lacks run image mixins the image has. The failed validations are logged as warnings.
+	Force bool
+
+	// Validate the rebase, and report what it would change, without changing the image.
+	DryRun bool
+}
+
+// RebaseReport describes what a rebase changes in an app image.
+type RebaseReport struct {
+	// Name of the app image.
+	Image string `json:"image"`
+
+	// Name of the run image the app image is rebased on, chosen from the run image and mirrors
+	// of the app image when not given.
+	RunImage string `json:"runImage"`
+
+	// The run image the app image was built on, or last rebased on.
+	PreviousBase RebaseBase `json:"previousBase"`
+
+	// The run image the app image is rebased on.
+	NewBase RebaseBase `json:"newBase"`
+
+	// Number of layers of the previous run image the rebase replaces.
+	// Layers the previous and new run images share are not counted.
+	LayersSwapped int `json:"layersSwapped"`
+
+	// Whether the app image was left unchanged, as for a dry run.
+	DryRun bool `json:"dryRun"`
+
+	// Digest of the published app image, or its ID in the docker daemon, once rebased.
+	// Empty for a dry run.
+	ImageDigest string `json:"imageDigest,omitempty"`
+}
+
+// RebaseBase is a run image an app image is based on.
+type RebaseBase struct {
+	// Diff ID of the top layer of the run image.
+	TopLayer string `json:"topLayer"`
+
+	// Digest reference of the run image in a registry, or its ID in the docker daemon.
+	Reference string `json:"reference"`
+
+	// Digest of the run image in a registry, or its ID in the docker daemon.
+	Digest string `json:"digest"`
 }
 
 // Rebase updates the run image layers in an app image.
-// This operation mutates the image specified in opts.
+// This operation mutates the image specified in opts, unless opts.DryRun is set.
 func (c *Client) Rebase(ctx context.Context, opts RebaseOptions) error {
+	return c.rebase(ctx, opts, nil)
+}
+
+// RebaseWithReport rebases an app image in the same way as Rebase.
+// On success it also returns a RebaseReport describing what the rebase changed, or would change for a dry run.
+func (c *Client) RebaseWithReport(ctx context.Context, opts RebaseOptions) (*RebaseReport, error) {
+	report := &RebaseReport{}
+	if err := c.rebase(ctx, opts, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// rebase runs the rebase. If report is not nil, it is populated as the rebase progresses.
+func (c *Client) rebase(ctx context.Context, opts RebaseOptions, report *RebaseReport) error {
 	imageRef, err := c.parseTagReference(opts.RepoName)
 	if err != nil {
 		return errors.Wrapf(err, "invalid image name '%s'", opts.RepoName)
 	}
 
+	platform, err := parsePlatform(opts.Platform)
+	if err != nil {
+		return errors.Wrap(err, "invalid platform")
+	}
+
 	appImage, err := c.imageFetcher.Fetch(ctx, opts.RepoName, !opts.Publish, opts.PullPolicy)
 	if err != nil {
 		return err
 	}
 
+	if platform != nil {
+		if err := image.CheckPlatform(appImage, *platform); err != nil {
+			return err
+		}
+	}
+
 	var md lifecycle.LayersMetadataCompat
 	if ok, err := dist.GetLabel(appImage, lifecycle.LayerMetadataLabel, &md); err != nil {
 		return err
@@ -55,8 +137,67 @@ func (c *Client) Rebase(ctx context.Context, opts RebaseOptions) error {
 		return errors.Errorf("could not find label %s on image", style.Symbol(lifecycle.LayerMetadataLabel))
 	}
 
-	runImageName := c.resolveRunImage(
-		opts.RunImage,
+	runImageName := c.resolveAppRunImage(imageRef, md, opts.RunImage, opts.AdditionalMirrors, opts.Publish)
+	if runImageName == "" {
+		return errors.New("run image must be specified")
+	}
+
+	baseImage, err := c.fetchForPlatform(ctx, runImageName, !opts.Publish, opts.PullPolicy, platform)
+	if err != nil {
+		return err
+	}
+
+	if err := validateRebase(appImage, baseImage); err != nil {
+		if !opts.Force {
+			return err
+		}
+		c.logger.Warnf("Rebasing anyway, as forced: %s", err)
+	}
+
+	if report != nil {
+		if err := c.prepareRebaseReport(ctx, report, appImage, md, runImageName, baseImage); err != nil {
+			return err
+		}
+		report.DryRun = opts.DryRun
+	}
+
+	if opts.DryRun {
+		c.logger.Infof("Would rebase %s on run image %s", style.Symbol(appImage.Name()), style.Symbol(baseImage.Name()))
+		return nil
+	}
+
+	c.logger.Infof("Rebasing %s on run image %s", style.Symbol(appImage.Name()), style.Symbol(baseImage.Name()))
+	rebaseImage := appImage
+	if opts.Force {
+		rebaseImage = &forcedRebaseImage{Image: appImage, runImage: baseImage}
+	}
+	rebaser := &lifecycle.Rebaser{Logger: c.logger}
+	if _, err := rebaser.Rebase(rebaseImage, baseImage, nil); err != nil {
+		return err
+	}
+
+	appImageIdentifier, err := appImage.Identifier()
+	if err != nil {
+		return err
+	}
+
+	if report != nil {
+		resolved, err := resolveImage(appImage.Name(), appImage)
+		if err != nil {
+			return err
+		}
+		report.ImageDigest = resolved.Digest
+	}
+
+	c.logger.Infof("Rebased Image: %s", style.Symbol(appImageIdentifier.String()))
+	return nil
+}
+
+// resolveAppRunImage returns the name of the run image to rebase the app image imageRef on, preferring runImage,
+// then the run image or mirror recorded in md, or in additionalMirrors, that is on the registry of the app image.
+func (c *Client) resolveAppRunImage(imageRef name.Reference, md lifecycle.LayersMetadataCompat, runImage string, additionalMirrors map[string][]string, publish bool) string {
+	return c.resolveRunImage(
+		runImage,
 		imageRef.Context().RegistryStr(),
 		"",
 		builder.StackMetadata{
@@ -65,30 +206,119 @@ func (c *Client) Rebase(ctx context.Context, opts RebaseOptions) error {
 				Mirrors: md.Stack.RunImage.Mirrors,
 			},
 		},
-		opts.AdditionalMirrors,
-		opts.Publish)
+		additionalMirrors,
+		publish)
+}
 
-	if runImageName == "" {
-		return errors.New("run image must be specified")
+// prepareRebaseReport records the previous and new run images of appImage, and the layers the rebase replaces.
+func (c *Client) prepareRebaseReport(ctx context.Context, report *RebaseReport, appImage imgutil.Image, md lifecycle.LayersMetadataCompat, runImageName string, baseImage imgutil.Image) error {
+	report.Image = appImage.Name()
+	report.RunImage = runImageName
+	report.PreviousBase = RebaseBase{
+		TopLayer:  md.RunImage.TopLayer,
+		Reference: md.RunImage.Reference,
+		Digest:    referenceDigest(md.RunImage.Reference),
+	}
+
+	topLayer, err := baseImage.TopLayer()
+	if err != nil {
+		return errors.Wrapf(err, "reading top layer of %s", style.Symbol(baseImage.Name()))
+	}
+	identifier, err := baseImage.Identifier()
+	if err != nil {
+		return errors.Wrapf(err, "reading identifier of %s", style.Symbol(baseImage.Name()))
+	}
+	report.NewBase = RebaseBase{
+		TopLayer:  topLayer,
+		Reference: identifier.String(),
+		Digest:    referenceDigest(identifier.String()),
 	}
 
-	baseImage, err := c.imageFetcher.Fetch(ctx, runImageName, !opts.Publish, opts.PullPolicy)
+	appLayers, err := c.layerReader.Layers(ctx, appImage)
+	if err != nil {
+		return err
+	}
+	baseLayers, err := c.layerReader.Layers(ctx, baseImage)
 	if err != nil {
 		return err
 	}
 
-	c.logger.Infof("Rebasing %s on run image %s", style.Symbol(appImage.Name()), style.Symbol(baseImage.Name()))
-	rebaser := &lifecycle.Rebaser{Logger: c.logger}
-	_, err = rebaser.Rebase(appImage, baseImage, nil)
+	previousBaseLayers := -1
+	for i, layer := range appLayers {
+		if layer.DiffID == md.RunImage.TopLayer {
+			previousBaseLayers = i + 1
+			break
+		}
+	}
+	if previousBaseLayers < 0 {
+		return errors.Errorf("top layer %s of the run image of %s is not a layer of the image",
+			style.Symbol(md.RunImage.TopLayer), style.Symbol(appImage.Name()))
+	}
+
+	newDiffIDs := map[string]bool{}
+	for _, layer := range baseLayers {
+		newDiffIDs[layer.DiffID] = true
+	}
+	for _, layer := range appLayers[:previousBaseLayers] {
+		if !newDiffIDs[layer.DiffID] {
+			report.LayersSwapped++
+		}
+	}
+	return nil
+}
+
+// referenceDigest returns the digest of a digest reference such as 'some/run@sha256:...', or else the reference itself,
+// such as an image ID.
+func referenceDigest(reference string) string {
+	if i := strings.LastIndex(reference, "@"); i >= 0 {
+		return reference[i+1:]
+	}
+	return reference
+}
+
+// validateRebase ensures that runImage is of the stack of appImage, and provides the mixins of the run image appImage
+// was built on, as the buildpacks of appImage may require any of them.
+func validateRebase(appImage, runImage imgutil.Image) error {
+	appStackID, err := appImage.Label(lifecycle.StackIDLabel)
 	if err != nil {
 		return err
 	}
 
-	appImageIdentifier, err := appImage.Identifier()
+	runStackID, err := runImage.Label(lifecycle.StackIDLabel)
 	if err != nil {
 		return err
 	}
 
-	c.logger.Infof("Rebased Image: %s", style.Symbol(appImageIdentifier.String()))
+	if appStackID != runStackID {
+		return errors.Errorf("run image %s has stack %s, which does not match the stack %s of %s",
+			style.Symbol(runImage.Name()), style.Symbol(runStackID), style.Symbol(appStackID), style.Symbol(appImage.Name()))
+	}
+
+	var appMixins, runMixins []string
+	if _, err := dist.GetLabel(appImage, stack.MixinsLabel, &appMixins); err != nil {
+		return err
+	}
+	if _, err := dist.GetLabel(runImage, stack.MixinsLabel, &runMixins); err != nil {
+		return err
+	}
+
+	if missing := stack.FindMissingRunMixins(appMixins, runMixins); len(missing) > 0 {
+		return errors.Errorf("run image %s is missing mixin(s) required by %s: %s",
+			style.Symbol(runImage.Name()), style.Symbol(appImage.Name()), strings.Join(missing, ", "))
+	}
 	return nil
 }
+
+// forcedRebaseImage is an app image the lifecycle rebaser takes as being of the stack, and requiring the mixins,
+// of its new run image, so that a forced rebase skips only the validation of the rebaser.
+type forcedRebaseImage struct {
+	imgutil.Image
+	runImage imgutil.Image
+}
+
+func (i *forcedRebaseImage) Label(name string) (string, error) {
+	if name == lifecycle.StackIDLabel || name == lifecycle.MixinsLabel {
+		return i.runImage.Label(name)
+	}
+	return i.Image.Label(name)
+}
@@ -0,0 +1,140 @@
+package pack
+
+import (
+	"context"
+	"sync"
+
+	"github.com/buildpacks/imgutil"
+
+	"github.com/buildpacks/pack/config"
+	"github.com/buildpacks/pack/logging"
+)
+
+// RebaseAllOptions configures RebaseAll.
+type RebaseAllOptions struct {
+	// Names of the app images to rebase.
+	Images []string
+
+	// Options every image is rebased with. RepoName is ignored.
+	Options RebaseOptions
+
+	// Maximum number of rebases to run at the same time.
+	// Defaults to 1, rebasing the images one after the other.
+	Parallel int
+
+	// Whether to report what each rebase changes, as RebaseWithReport does.
+	Report bool
+}
+
+// ImageRebaseResult is the outcome of the rebase of an image.
+type ImageRebaseResult struct {
+	// Name of the image.
+	Image string
+
+	// What the rebase changed, if reports were asked for and the rebase succeeded.
+	Report *RebaseReport
+
+	// Why the rebase failed, or nil if it succeeded.
+	Err error
+}
+
+// RebaseAll rebases several images, running up to Parallel rebases at the same time.
+// Images shared by the rebases, such as the run image, are fetched once.
+// The output of each rebase is prefixed with the name of its image.
+// A failed rebase does not stop the others, and the result of every rebase is returned in the order of the images,
+// leaving out repeated images.
+func (c *Client) RebaseAll(ctx context.Context, opts RebaseAllOptions) []ImageRebaseResult {
+	parallel := opts.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	shared := c.withImageFetcher(newRemoteImageCache(newSharedImageFetcher(c.imageFetcher)))
+
+	var images []string
+	seen := map[string]bool{}
+	for _, img := range opts.Images {
+		if !seen[img] {
+			seen[img] = true
+			images = append(images, img)
+		}
+	}
+
+	results := make([]ImageRebaseResult, len(images))
+	slots := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, img := range images {
+		results[i] = ImageRebaseResult{Image: img}
+
+		rebaseOpts := opts.Options
+		rebaseOpts.RepoName = img
+
+		var report *RebaseReport
+		if opts.Report {
+			report = &RebaseReport{}
+		}
+
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(result *ImageRebaseResult, rebaseOpts RebaseOptions, report *RebaseReport) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			result.Err = shared.withLogger(logging.NewPrefixLogger(c.logger, rebaseOpts.RepoName)).rebase(ctx, rebaseOpts, report)
+			if result.Err == nil {
+				result.Report = report
+			}
+		}(&results[i], rebaseOpts, report)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// remoteImageCache fetches each registry image once, across the rebases it is shared by.
+// This is safe as rebases only read the run image they share, and each app image is rebased once.
+type remoteImageCache struct {
+	fetcher ImageFetcher
+
+	mu      sync.Mutex
+	fetches map[string]*remoteFetch
+}
+
+type remoteFetch struct {
+	done chan struct{}
+	img  imgutil.Image
+	err  error
+}
+
+func newRemoteImageCache(fetcher ImageFetcher) *remoteImageCache {
+	return &remoteImageCache{fetcher: fetcher, fetches: map[string]*remoteFetch{}}
+}
+
+func (f *remoteImageCache) Fetch(ctx context.Context, name string, daemon bool, pullPolicy config.PullPolicy) (imgutil.Image, error) {
+	if daemon {
+		return f.fetcher.Fetch(ctx, name, daemon, pullPolicy)
+	}
+
+	f.mu.Lock()
+	fetch, fetching := f.fetches[name]
+	if !fetching {
+		fetch = &remoteFetch{done: make(chan struct{})}
+		f.fetches[name] = fetch
+	}
+	f.mu.Unlock()
+
+	if !fetching {
+		fetch.img, fetch.err = f.fetcher.Fetch(ctx, name, daemon, pullPolicy)
+		close(fetch.done)
+		return fetch.img, fetch.err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-fetch.done:
+	}
+	return fetch.img, fetch.err
+}
@@ -0,0 +1,171 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/buildpacks/imgutil/fakes"
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/config"
+	ifakes "github.com/buildpacks/pack/internal/fakes"
+	"github.com/buildpacks/pack/internal/image"
+	"github.com/buildpacks/pack/internal/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+	"github.com/buildpacks/pack/testmocks"
+)
+
+func TestRebaseAll(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "RebaseAll", testRebaseAll, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+func testRebaseAll(t *testing.T, when spec.G, it spec.S) {
+	var (
+		mockController *gomock.Controller
+		out            bytes.Buffer
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#RebaseAll", func() {
+		var (
+			fakeImageFetcher *ifakes.FakeImageFetcher
+			fakeLayerReader  *ifakes.FakeLayerReader
+			fakeRunImage     *fakes.Image
+			fakeAppImages    []*fakes.Image
+			subject          *Client
+		)
+
+		it.Before(func() {
+			fakeImageFetcher = ifakes.NewFakeImageFetcher()
+			fakeLayerReader = ifakes.NewFakeLayerReader()
+
+			fakeRunImage = fakes.NewImage("some/run", "run-image-top-layer-sha", &fakeIdentifier{name: "run-image-digest"})
+			h.AssertNil(t, fakeRunImage.SetLabel("io.buildpacks.stack.id", "io.buildpacks.stacks.bionic"))
+			fakeImageFetcher.LocalImages["some/run"] = fakeRunImage
+			fakeLayerReader.ImageLayers["some/run"] = []image.Layer{{DiffID: "run-image-top-layer-sha"}}
+
+			for _, name := range []string{"some/app", "other/app"} {
+				fakeAppImage := fakes.NewImage(name, "", &fakeIdentifier{name: name + "-digest"})
+				h.AssertNil(t, fakeAppImage.SetLabel("io.buildpacks.lifecycle.metadata",
+					`{"runImage":{"topLayer":"old-top-layer"},"stack":{"runImage":{"image":"some/run"}}}`))
+				h.AssertNil(t, fakeAppImage.SetLabel("io.buildpacks.stack.id", "io.buildpacks.stacks.bionic"))
+				fakeImageFetcher.LocalImages[name] = fakeAppImage
+				fakeLayerReader.ImageLayers[name] = []image.Layer{{DiffID: "old-top-layer"}, {DiffID: "app-layer"}}
+				fakeAppImages = append(fakeAppImages, fakeAppImage)
+			}
+
+			subject = &Client{
+				logger:       logging.NewLogWithWriters(&out, &out),
+				imageFetcher: fakeImageFetcher,
+				layerReader:  fakeLayerReader,
+			}
+		})
+
+		it.After(func() {
+			fakeRunImage.Cleanup()
+			for _, img := range fakeAppImages {
+				img.Cleanup()
+			}
+		})
+
+		it("returns the result of every rebase in the order of the images", func() {
+			results := subject.RebaseAll(context.TODO(), RebaseAllOptions{
+				Images:   []string{"some/app", "missing/app", "other/app", "some/app"},
+				Options:  RebaseOptions{PullPolicy: config.PullNever},
+				Parallel: 2,
+			})
+
+			h.AssertEq(t, len(results), 3)
+			h.AssertEq(t, results[0].Image, "some/app")
+			h.AssertNil(t, results[0].Err)
+			h.AssertEq(t, results[1].Image, "missing/app")
+			h.AssertNotNil(t, results[1].Err)
+			h.AssertEq(t, results[2].Image, "other/app")
+			h.AssertNil(t, results[2].Err)
+
+			h.AssertEq(t, fakeAppImages[0].Base(), "some/run")
+			h.AssertEq(t, fakeAppImages[1].Base(), "some/run")
+			h.AssertContains(t, out.String(), "[some/app] Rebasing 'some/app' on run image 'some/run'")
+		})
+
+		it("reports each rebase when asked", func() {
+			results := subject.RebaseAll(context.TODO(), RebaseAllOptions{
+				Images:  []string{"some/app", "other/app"},
+				Options: RebaseOptions{PullPolicy: config.PullNever, DryRun: true},
+				Report:  true,
+			})
+
+			h.AssertEq(t, len(results), 2)
+			for _, result := range results {
+				h.AssertNil(t, result.Err)
+				h.AssertEq(t, result.Report.Image, result.Image)
+				h.AssertEq(t, result.Report.LayersSwapped, 1)
+				h.AssertEq(t, result.Report.DryRun, true)
+			}
+			h.AssertEq(t, fakeAppImages[0].IsSaved(), false)
+		})
+	})
+
+	when("#remoteImageCache", func() {
+		var (
+			mockImageFetcher *testmocks.MockImageFetcher
+			subject          *remoteImageCache
+			img              *fakes.Image
+		)
+
+		it.Before(func() {
+			mockImageFetcher = testmocks.NewMockImageFetcher(mockController)
+			subject = newRemoteImageCache(mockImageFetcher)
+			img = fakes.NewImage("some/run", "", nil)
+		})
+
+		it("fetches a registry image once", func() {
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/run", false, config.PullAlways).Return(img, nil).Times(1)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 5; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					fetched, err := subject.Fetch(context.TODO(), "some/run", false, config.PullAlways)
+					h.AssertNil(t, err)
+					h.AssertEq(t, fetched.Name(), "some/run")
+				}()
+			}
+			wg.Wait()
+		})
+
+		it("passes daemon fetches on", func() {
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/run", true, config.PullAlways).Return(img, nil).Times(2)
+
+			for i := 0; i < 2; i++ {
+				_, err := subject.Fetch(context.TODO(), "some/run", true, config.PullAlways)
+				h.AssertNil(t, err)
+			}
+		})
+
+		it("fails later fetches when the fetch failed", func() {
+			mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/run", false, config.PullAlways).Return(nil, errors.New("some-fetch-error")).Times(1)
+
+			for i := 0; i < 2; i++ {
+				_, err := subject.Fetch(context.TODO(), "some/run", false, config.PullAlways)
+				h.AssertError(t, err, "some-fetch-error")
+			}
+		})
+	})
+}
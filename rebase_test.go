@@ -3,6 +3,7 @@ package pack
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/buildpacks/pack/config"
@@ -13,6 +14,7 @@ import (
 	"github.com/sclevine/spec/report"
 
 	ifakes "github.com/buildpacks/pack/internal/fakes"
+	"github.com/buildpacks/pack/internal/image"
 	"github.com/buildpacks/pack/internal/logging"
 	h "github.com/buildpacks/pack/testhelpers"
 )
@@ -31,6 +33,7 @@ func testRebase(t *testing.T, when spec.G, it spec.S) {
 			fakeAppImage       *fakes.Image
 			fakeRunImage       *fakes.Image
 			fakeRunImageMirror *fakes.Image
+			fakeLayerReader    *ifakes.FakeLayerReader
 			out                bytes.Buffer
 		)
 
@@ -51,10 +54,13 @@ func testRebase(t *testing.T, when spec.G, it spec.S) {
 			h.AssertNil(t, fakeRunImageMirror.SetLabel("io.buildpacks.stack.id", "io.buildpacks.stacks.bionic"))
 			fakeImageFetcher.LocalImages["example.com/some/run"] = fakeRunImageMirror
 
+			fakeLayerReader = ifakes.NewFakeLayerReader()
+
 			fakeLogger := logging.NewLogWithWriters(&out, &out)
 			subject = &Client{
 				logger:       fakeLogger,
 				imageFetcher: fakeImageFetcher,
+				layerReader:  fakeLayerReader,
 			}
 		})
 
@@ -219,6 +225,233 @@ func testRebase(t *testing.T, when spec.G, it spec.S) {
 					})
 				})
 			})
+
+			when("platform is provided", func() {
+				it("rebases onto a run image for the platform", func() {
+					h.AssertNil(t, fakeAppImage.SetArchitecture("arm64"))
+					h.AssertNil(t, fakeRunImage.SetArchitecture("arm64"))
+
+					h.AssertNil(t, subject.Rebase(context.TODO(), RebaseOptions{
+						RepoName:   "some/app",
+						PullPolicy: config.PullNever,
+						Platform:   "linux/arm64",
+					}))
+					h.AssertEq(t, fakeAppImage.Base(), "some/run")
+				})
+
+				it("errors when the app image is for another platform", func() {
+					err := subject.Rebase(context.TODO(), RebaseOptions{
+						RepoName:   "some/app",
+						PullPolicy: config.PullNever,
+						Platform:   "linux/arm64",
+					})
+					h.AssertError(t, err, "image 'some/app' is for platform 'linux/amd64', not 'linux/arm64'")
+				})
+
+				it("errors when the local run image is for another platform", func() {
+					h.AssertNil(t, fakeAppImage.SetArchitecture("arm64"))
+
+					err := subject.Rebase(context.TODO(), RebaseOptions{
+						RepoName:   "some/app",
+						PullPolicy: config.PullNever,
+						Platform:   "linux/arm64",
+					})
+					h.AssertError(t, err, "image 'some/run' is for platform 'linux/amd64', not 'linux/arm64'")
+				})
+
+				it("errors for an invalid platform", func() {
+					err := subject.Rebase(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+						Platform: "arm64",
+					})
+					h.AssertError(t, err, "invalid platform: platform 'arm64' must be in the form '<os>/<arch>[/<variant>]'")
+				})
+			})
+
+			when("dry run", func() {
+				it("validates the rebase without changing the image", func() {
+					h.AssertNil(t, subject.Rebase(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+						DryRun:   true,
+					}))
+					h.AssertEq(t, fakeAppImage.Base(), "")
+					h.AssertEq(t, fakeAppImage.IsSaved(), false)
+					h.AssertContains(t, out.String(), "Would rebase 'some/app' on run image 'some/run'")
+				})
+
+				it("still fails validation", func() {
+					h.AssertNil(t, fakeRunImage.SetLabel("io.buildpacks.stack.id", "io.buildpacks.stacks.other"))
+
+					err := subject.Rebase(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+						DryRun:   true,
+					})
+					h.AssertError(t, err, "does not match the stack")
+				})
+			})
+
+			when("#RebaseWithReport", func() {
+				it.Before(func() {
+					h.AssertNil(t, fakeAppImage.SetLabel("io.buildpacks.lifecycle.metadata",
+						`{"runImage":{"topLayer":"old-top-layer","reference":"some/run@sha256:old"},"stack":{"runImage":{"image":"some/run"}}}`))
+
+					fakeLayerReader.ImageLayers["some/app"] = []image.Layer{
+						{DiffID: "shared-layer"}, {DiffID: "old-layer"}, {DiffID: "old-top-layer"}, {DiffID: "app-layer"},
+					}
+					fakeLayerReader.ImageLayers["some/run"] = []image.Layer{
+						{DiffID: "shared-layer"}, {DiffID: "new-layer"}, {DiffID: "run-image-top-layer-sha"},
+					}
+				})
+
+				it("reports what a dry run would change", func() {
+					report, err := subject.RebaseWithReport(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+						DryRun:   true,
+					})
+					h.AssertNil(t, err)
+
+					h.AssertEq(t, report, &RebaseReport{
+						Image:    "some/app",
+						RunImage: "some/run",
+						PreviousBase: RebaseBase{
+							TopLayer:  "old-top-layer",
+							Reference: "some/run@sha256:old",
+							Digest:    "sha256:old",
+						},
+						NewBase: RebaseBase{
+							TopLayer:  "run-image-top-layer-sha",
+							Reference: "run-image-digest",
+							Digest:    "run-image-digest",
+						},
+						LayersSwapped: 2,
+						DryRun:        true,
+					})
+					h.AssertEq(t, fakeAppImage.IsSaved(), false)
+				})
+
+				it("reports the rebase", func() {
+					report, err := subject.RebaseWithReport(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+					})
+					h.AssertNil(t, err)
+
+					h.AssertEq(t, report.DryRun, false)
+					h.AssertEq(t, report.LayersSwapped, 2)
+					h.AssertEq(t, report.NewBase.TopLayer, "run-image-top-layer-sha")
+					h.AssertEq(t, fakeAppImage.Base(), "some/run")
+				})
+
+				it("errors when the top layer of the run image is not a layer of the image", func() {
+					fakeLayerReader.ImageLayers["some/app"] = []image.Layer{{DiffID: "app-layer"}}
+
+					_, err := subject.RebaseWithReport(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+						DryRun:   true,
+					})
+					h.AssertError(t, err, "top layer 'old-top-layer' of the run image of 'some/app' is not a layer of the image")
+				})
+			})
+
+			when("the image has stack labels", func() {
+				it.Before(func() {
+					h.AssertNil(t, fakeAppImage.SetLabel("io.buildpacks.stack.maintainer", "some-old-maintainer"))
+					h.AssertNil(t, fakeRunImage.SetLabel("io.buildpacks.stack.distro.name", "some-distro"))
+				})
+
+				for _, force := range []bool{false, true} {
+					force := force
+					it(fmt.Sprintf("replaces them with those of the run image (force=%t)", force), func() {
+						h.AssertNil(t, subject.Rebase(context.TODO(), RebaseOptions{
+							RepoName: "some/app",
+							Force:    force,
+						}))
+
+						labels, err := fakeAppImage.Labels()
+						h.AssertNil(t, err)
+						_, hasMaintainer := labels["io.buildpacks.stack.maintainer"]
+						h.AssertEq(t, hasMaintainer, false)
+						h.AssertEq(t, labels["io.buildpacks.stack.distro.name"], "some-distro")
+						h.AssertEq(t, labels["io.buildpacks.stack.id"], "io.buildpacks.stacks.bionic")
+						h.AssertContains(t, labels["io.buildpacks.lifecycle.metadata"], `"runImage":{"topLayer":"run-image-top-layer-sha","reference":"run-image-digest"`)
+						h.AssertEq(t, fakeAppImage.IsSaved(), true)
+					})
+				}
+			})
+
+			when("the run image is of another stack", func() {
+				it.Before(func() {
+					h.AssertNil(t, fakeRunImage.SetLabel("io.buildpacks.stack.id", "io.buildpacks.stacks.other"))
+				})
+
+				it("errors without changing the image", func() {
+					err := subject.Rebase(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+					})
+					h.AssertError(t, err, "run image 'some/run' has stack 'io.buildpacks.stacks.other', which does not match the stack 'io.buildpacks.stacks.bionic' of 'some/app'")
+					h.AssertEq(t, fakeAppImage.Base(), "")
+					h.AssertEq(t, fakeAppImage.IsSaved(), false)
+				})
+
+				when("forced", func() {
+					it("rebases with a warning", func() {
+						h.AssertNil(t, subject.Rebase(context.TODO(), RebaseOptions{
+							RepoName: "some/app",
+							Force:    true,
+						}))
+						h.AssertContains(t, out.String(), "Warning: Rebasing anyway, as forced: run image 'some/run' has stack 'io.buildpacks.stacks.other'")
+						h.AssertEq(t, fakeAppImage.Base(), "some/run")
+						h.AssertEq(t, fakeAppImage.IsSaved(), true)
+
+						stackID, err := fakeAppImage.Label("io.buildpacks.stack.id")
+						h.AssertNil(t, err)
+						h.AssertEq(t, stackID, "io.buildpacks.stacks.other")
+
+						lbl, _ := fakeAppImage.Label("io.buildpacks.lifecycle.metadata")
+						h.AssertContains(t, lbl, `"runImage":{"topLayer":"run-image-top-layer-sha","reference":"run-image-digest"`)
+					})
+				})
+			})
+
+			when("the image has mixins", func() {
+				it.Before(func() {
+					h.AssertNil(t, fakeAppImage.SetLabel("io.buildpacks.stack.mixins", `["mixinA", "run:mixinB", "mixinC"]`))
+				})
+
+				it("rebases when the run image provides them", func() {
+					h.AssertNil(t, fakeRunImage.SetLabel("io.buildpacks.stack.mixins", `["mixinA", "mixinB", "run:mixinC", "mixinD"]`))
+
+					h.AssertNil(t, subject.Rebase(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+					}))
+					h.AssertEq(t, fakeAppImage.Base(), "some/run")
+				})
+
+				it("errors with the mixins the run image lacks, without changing the image", func() {
+					h.AssertNil(t, fakeRunImage.SetLabel("io.buildpacks.stack.mixins", `["mixinA"]`))
+
+					err := subject.Rebase(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+					})
+					h.AssertError(t, err, "run image 'some/run' is missing mixin(s) required by 'some/app': mixinB, mixinC")
+					h.AssertEq(t, fakeAppImage.Base(), "")
+					h.AssertEq(t, fakeAppImage.IsSaved(), false)
+				})
+
+				it("rebases with a warning when forced", func() {
+					h.AssertNil(t, fakeRunImage.SetLabel("io.buildpacks.stack.mixins", `["mixinA"]`))
+
+					h.AssertNil(t, subject.Rebase(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+						Force:    true,
+					}))
+					h.AssertContains(t, out.String(), "Warning: Rebasing anyway, as forced: run image 'some/run' is missing mixin(s) required by 'some/app': mixinB, mixinC")
+					h.AssertEq(t, fakeAppImage.Base(), "some/run")
+
+					mixins, err := fakeAppImage.Label("io.buildpacks.stack.mixins")
+					h.AssertNil(t, err)
+					h.AssertEq(t, mixins, `["mixinA"]`)
+				})
+			})
 		})
 	})
 }